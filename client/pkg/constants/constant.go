@@ -1,8 +1,21 @@
 package constants
 
+import "time"
+
 const (
 	// OperationBufferSize denotes the size of operation buffer
 	OperationBufferSize int = 1024
+	// DefaultMaxUnpushedOperations is the default limit of the local operations not pushed to the server yet
+	DefaultMaxUnpushedOperations = 65536
+)
+
+const (
+	// MaxSyncRetries is the maximum number of retries of a push-pull aborted by the server
+	MaxSyncRetries = 5
+	// SyncRetryBackoff is the unit of the backoff before retrying a push-pull, which grows with the retries
+	SyncRetryBackoff = 200 * time.Millisecond
+	// MaxSyncRounds is the maximum number of consecutive rounds of push-pulls in a sync without progress
+	MaxSyncRounds = 100
 )
 
 const (
@@ -63,6 +63,10 @@ const (
 	DatatypeMarshal
 	DatatypeNoTarget
 	DatatypeInvalidPatch
+	DatatypeSync
+	DatatypeOutOfPath
+	DatatypeTooManyUnpushed
+	DatatypeFlush
 )
 
 var datatypeErrFormats = map[ErrorCode]string{
@@ -77,6 +81,10 @@ var datatypeErrFormats = map[ErrorCode]string{
 	DatatypeMarshal:           "fail to (un)marshal: %v",
 	DatatypeNoTarget:          "fail to find target: %v",
 	DatatypeInvalidPatch:      "fail to patch: %v",
+	DatatypeSync:              "fail to synchronize with server: %v",
+	DatatypeOutOfPath:         "fail to %v out of the subscribed path: %v",
+	DatatypeTooManyUnpushed:   "fail to issue operation over %v operations not pushed yet",
+	DatatypeFlush:             "fail to flush operations not acknowledged: %v",
 }
 
 // ServerXXX denotes the errors when Server is running.
@@ -92,19 +100,23 @@ const (
 	ServerDBClose
 	ServerUpdateSnapshot
 	ServerInternal
+	ServerDBConflict
+	ServerIncompatibleClient
 )
 
 var serverErrFormats = map[ErrorCode]string{
-	ServerDBQuery:        "fail to succeed DB query: %v",
-	ServerDBDecode:       "fail to decode in DB: %v",
-	ServerNoResource:     "find no resource: %v",
-	ServerNoPermission:   "have no permission: %v",
-	ServerInit:           "fail to initialize server: %v",
-	ServerNotify:         "fail to notify push-pull: %v",
-	ServerBadRequest:     "fail to process due to bad request: %v",
-	ServerDBClose:        "fail to close mongoDB: %v",
-	ServerUpdateSnapshot: "fail to update snapshot: %v",
-	ServerInternal:       "internal server error: %v",
+	ServerDBQuery:            "fail to succeed DB query: %v",
+	ServerDBDecode:           "fail to decode in DB: %v",
+	ServerNoResource:         "find no resource: %v",
+	ServerNoPermission:       "have no permission: %v",
+	ServerInit:               "fail to initialize server: %v",
+	ServerNotify:             "fail to notify push-pull: %v",
+	ServerBadRequest:         "fail to process due to bad request: %v",
+	ServerDBClose:            "fail to close mongoDB: %v",
+	ServerUpdateSnapshot:     "fail to update snapshot: %v",
+	ServerInternal:           "internal server error: %v",
+	ServerDBConflict:         "fail to commit due to concurrent update: %v",
+	ServerIncompatibleClient: "incompatible client: %v",
 }
 
 // PushPullXXX denotes the errors during PushPull
@@ -114,6 +126,8 @@ const (
 	PushPullDuplicateKey
 	PushPullMissingOps
 	PushPullNoDatatypeToSubscribe
+	PushPullEraMismatch
+	PushPullBehindCompaction
 )
 
 var pushPullErrFormats = map[ErrorCode]string{
@@ -122,4 +136,6 @@ var pushPullErrFormats = map[ErrorCode]string{
 	PushPullDuplicateKey:          "duplicate datatype key: %v",
 	PushPullMissingOps:            "aborted push due to missing operations: %v",
 	PushPullNoDatatypeToSubscribe: "no datatype to subscribe: %v",
+	PushPullEraMismatch:           "push-pull with an old era: %v",
+	PushPullBehindCompaction:      "push behind the compacted operations: %v",
 }
@@ -43,6 +43,10 @@ func NewRPCError(oErr OrdaError) error {
 		c = codes.Internal
 	case ServerBadRequest:
 		c = codes.InvalidArgument
+	case ServerDBConflict:
+		c = codes.Aborted
+	case ServerIncompatibleClient:
+		c = codes.FailedPrecondition
 	}
 	return status.Error(c, oErr.Error())
 }
@@ -1,9 +1,11 @@
 package iface
 
 import (
+	"context"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
+	"time"
 )
 
 // BaseDatatype defines a base operations for datatype
@@ -29,7 +31,9 @@ type SnapshotDatatype interface {
 	GetSnapshot() Snapshot
 	GetMetaAndSnapshot() ([]byte, []byte, errors.OrdaError)
 	SetMetaAndSnapshot(meta []byte, snap []byte) errors.OrdaError
+	MarshalSnapshot(inBinary bool) ([]byte, errors.OrdaError)
 	CreateSnapshotOperation() (Operation, errors.OrdaError)
+	PurgeTombstones(stable *model.Timestamp) int
 	ToJSON() interface{}
 }
 
@@ -40,11 +44,17 @@ type WiredDatatype interface {
 	ReceiveRemoteModelOperations(ops []*model.Operation, obtainList bool) ([]interface{}, errors.OrdaError)
 	ApplyPushPullPack(*model.PushPullPack)
 	CreatePushPullPack() *model.PushPullPack
+	BufferTransaction(transaction []Operation)
 	DeliverTransaction(transaction []Operation)
 	NeedPull(sseq uint64) bool
 	NeedPush() bool
+	NeedRebase() bool
+	GetRetryBackoff() time.Duration
+	SetEra(era uint32)
 	SubscribeOrCreate(state model.StateOfDatatype) errors.OrdaError
 	ResetWired()
+	SetMaxUnpushed(maxUnpushed int)
+	Flush(ctx context.Context) errors.OrdaError
 }
 
 // OperationalDatatype defines interfaces related to executing operations.
@@ -59,6 +69,7 @@ type HandleableDatatype interface {
 	HandleStateChange(oldState, newState model.StateOfDatatype)
 	HandleErrors(err ...errors.OrdaError)
 	HandleRemoteOperations(operations []interface{})
+	HandleSnapshotProgress(received, total uint64)
 }
 
 // Datatype defines the interface of executing operations, which is implemented by every datatype.
@@ -1,6 +1,15 @@
 package iface
 
+import "github.com/orda-io/orda/client/pkg/model"
+
 // Snapshot defines the interfaces for snapshot used in a datatype. Snapshot contains metadata
 type Snapshot interface {
 	ToJSON() interface{}
 }
+
+// PurgeableSnapshot defines the snapshot having tombstones, which can be purged under the causal stability;
+// every operation to come has seen the deletions of the tombstones before the stable timestamp.
+type PurgeableSnapshot interface {
+	Snapshot
+	PurgeTombstones(stable *model.Timestamp) int
+}
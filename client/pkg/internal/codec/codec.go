@@ -0,0 +1,430 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/orda-io/orda/client/pkg/model"
+)
+
+// Marker leads any data encoded in binary; data encoded in JSON never begins with it.
+const Marker byte = 0x00
+
+// SnapshotVersion is the version of the binary snapshot format, which follows Marker.
+const SnapshotVersion byte = 1
+
+// tags of the values, which are decoded as the same types of encoding/json.
+const (
+	tagNull byte = iota
+	tagFalse
+	tagTrue
+	tagInt
+	tagFloat
+	tagString
+	tagArray
+	tagObject
+)
+
+// IsBinary examines if the data is encoded in binary.
+func IsBinary(data []byte) bool {
+	return len(data) > 0 && data[0] == Marker
+}
+
+// Writer encodes data with varints, length-prefixed strings, and tagged values.
+// Compact timestamps intern CUIDs and delta-encode lamports with the previously written one.
+type Writer struct {
+	buf     bytes.Buffer
+	tmp     [binary.MaxVarintLen64]byte
+	cuids   map[string]uint64
+	lamport uint64
+}
+
+// NewWriter creates a Writer, which begins with Marker.
+func NewWriter() *Writer {
+	w := &Writer{cuids: make(map[string]uint64)}
+	w.buf.WriteByte(Marker)
+	return w
+}
+
+// NewSnapshotWriter creates a Writer, which begins with Marker and SnapshotVersion.
+func NewSnapshotWriter() *Writer {
+	w := NewWriter()
+	w.buf.WriteByte(SnapshotVersion)
+	return w
+}
+
+// Bytes returns the encoded data.
+func (its *Writer) Bytes() []byte {
+	return its.buf.Bytes()
+}
+
+// PutByte writes a byte.
+func (its *Writer) PutByte(b byte) {
+	its.buf.WriteByte(b)
+}
+
+// PutUvarint writes an unsigned varint.
+func (its *Writer) PutUvarint(v uint64) {
+	n := binary.PutUvarint(its.tmp[:], v)
+	its.buf.Write(its.tmp[:n])
+}
+
+// PutVarint writes a signed varint.
+func (its *Writer) PutVarint(v int64) {
+	n := binary.PutVarint(its.tmp[:], v)
+	its.buf.Write(its.tmp[:n])
+}
+
+// PutString writes a length-prefixed string.
+func (its *Writer) PutString(s string) {
+	its.PutUvarint(uint64(len(s)))
+	its.buf.WriteString(s)
+}
+
+// PutTimestamp writes a timestamp with all its fields.
+func (its *Writer) PutTimestamp(ts *model.Timestamp) {
+	if ts == nil {
+		its.buf.WriteByte(0)
+		return
+	}
+	its.buf.WriteByte(1)
+	its.PutUvarint(uint64(ts.Era))
+	its.PutUvarint(ts.Lamport)
+	its.PutString(ts.CUID)
+	its.PutUvarint(uint64(ts.Delimiter))
+}
+
+// PutTimestamps writes timestamps with all their fields.
+func (its *Writer) PutTimestamps(tsList []*model.Timestamp) {
+	its.PutUvarint(uint64(len(tsList)))
+	for _, ts := range tsList {
+		its.PutTimestamp(ts)
+	}
+}
+
+// PutCompactTimestamp writes a timestamp with the index of its interned CUID and the delta of its lamport.
+// A new CUID is written after the index equal to the number of the interned ones.
+func (its *Writer) PutCompactTimestamp(ts *model.Timestamp) {
+	if ts == nil {
+		its.PutUvarint(0)
+		return
+	}
+	idx, ok := its.cuids[ts.CUID]
+	if !ok {
+		idx = uint64(len(its.cuids))
+		its.cuids[ts.CUID] = idx
+	}
+	its.PutUvarint(idx + 1)
+	if !ok {
+		its.PutString(ts.CUID)
+	}
+	its.PutUvarint(uint64(ts.Era))
+	its.PutVarint(int64(ts.Lamport - its.lamport))
+	its.lamport = ts.Lamport
+	its.PutUvarint(uint64(ts.Delimiter))
+}
+
+// PutValues writes values of JSON types.
+func (its *Writer) PutValues(values []interface{}) error {
+	its.PutUvarint(uint64(len(values)))
+	for _, v := range values {
+		if err := its.PutValue(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PutValue writes a value of JSON types; other types are converted through encoding/json.
+func (its *Writer) PutValue(v interface{}) error {
+	switch cast := v.(type) {
+	case nil:
+		its.buf.WriteByte(tagNull)
+	case bool:
+		if cast {
+			its.buf.WriteByte(tagTrue)
+		} else {
+			its.buf.WriteByte(tagFalse)
+		}
+	case int:
+		its.putInt(int64(cast))
+	case int32:
+		its.putInt(int64(cast))
+	case int64:
+		its.putInt(cast)
+	case float64:
+		if cast == math.Trunc(cast) && math.Abs(cast) < 1<<53 {
+			its.putInt(int64(cast))
+			return nil
+		}
+		its.buf.WriteByte(tagFloat)
+		binary.LittleEndian.PutUint64(its.tmp[:8], math.Float64bits(cast))
+		its.buf.Write(its.tmp[:8])
+	case string:
+		its.buf.WriteByte(tagString)
+		its.PutString(cast)
+	case []interface{}:
+		its.buf.WriteByte(tagArray)
+		return its.PutValues(cast)
+	case map[string]interface{}:
+		its.buf.WriteByte(tagObject)
+		keys := make([]string, 0, len(cast))
+		for k := range cast {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		its.PutUvarint(uint64(len(keys)))
+		for _, k := range keys {
+			its.PutString(k)
+			if err := its.PutValue(cast[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		j, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var generic interface{}
+		if err := json.Unmarshal(j, &generic); err != nil {
+			return err
+		}
+		return its.PutValue(generic)
+	}
+	return nil
+}
+
+func (its *Writer) putInt(v int64) {
+	its.buf.WriteByte(tagInt)
+	its.PutVarint(v)
+}
+
+// Reader decodes data encoded by Writer.
+type Reader struct {
+	r       *bytes.Reader
+	cuids   []string
+	lamport uint64
+}
+
+// NewReader creates a Reader of the data leading with Marker.
+func NewReader(data []byte) (*Reader, error) {
+	if !IsBinary(data) {
+		return nil, fmt.Errorf("not encoded in binary")
+	}
+	return &Reader{r: bytes.NewReader(data[1:])}, nil
+}
+
+// NewSnapshotReader creates a Reader of the data leading with Marker and a supported version of snapshot.
+func NewSnapshotReader(data []byte) (*Reader, error) {
+	r, err := NewReader(data)
+	if err != nil {
+		return nil, err
+	}
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 || version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported version of snapshot: %d", version)
+	}
+	return r, nil
+}
+
+// ReadByte reads a byte.
+func (its *Reader) ReadByte() (byte, error) {
+	return its.r.ReadByte()
+}
+
+// ReadUvarint reads an unsigned varint.
+func (its *Reader) ReadUvarint() (uint64, error) {
+	return binary.ReadUvarint(its.r)
+}
+
+// ReadVarint reads a signed varint.
+func (its *Reader) ReadVarint() (int64, error) {
+	return binary.ReadVarint(its.r)
+}
+
+// ReadLength reads a length, which cannot exceed the remaining bytes.
+func (its *Reader) ReadLength() (int, error) {
+	n, err := its.ReadUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(its.r.Len()) {
+		return 0, fmt.Errorf("invalid length %d over %d remaining bytes", n, its.r.Len())
+	}
+	return int(n), nil
+}
+
+// ReadString reads a length-prefixed string.
+func (its *Reader) ReadString() (string, error) {
+	n, err := its.ReadLength()
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(its.r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ReadTimestamp reads a timestamp written by PutTimestamp.
+func (its *Reader) ReadTimestamp() (*model.Timestamp, error) {
+	present, err := its.r.ReadByte()
+	if err != nil || present == 0 {
+		return nil, err
+	}
+	era, err := its.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	lamport, err := its.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	cuid, err := its.ReadString()
+	if err != nil {
+		return nil, err
+	}
+	delimiter, err := its.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Timestamp{Era: uint32(era), Lamport: lamport, CUID: cuid, Delimiter: uint32(delimiter)}, nil
+}
+
+// ReadTimestamps reads timestamps written by PutTimestamps.
+func (its *Reader) ReadTimestamps() ([]*model.Timestamp, error) {
+	n, err := its.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	var tsList []*model.Timestamp
+	for i := 0; i < n; i++ {
+		ts, err := its.ReadTimestamp()
+		if err != nil {
+			return nil, err
+		}
+		tsList = append(tsList, ts)
+	}
+	return tsList, nil
+}
+
+// ReadCompactTimestamp reads a timestamp written by PutCompactTimestamp.
+func (its *Reader) ReadCompactTimestamp() (*model.Timestamp, error) {
+	ref, err := its.ReadUvarint()
+	if err != nil || ref == 0 {
+		return nil, err
+	}
+	idx := ref - 1
+	if idx == uint64(len(its.cuids)) {
+		cuid, err := its.ReadString()
+		if err != nil {
+			return nil, err
+		}
+		its.cuids = append(its.cuids, cuid)
+	} else if idx > uint64(len(its.cuids)) {
+		return nil, fmt.Errorf("invalid index of CUID: %d", idx)
+	}
+	era, err := its.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	delta, err := its.ReadVarint()
+	if err != nil {
+		return nil, err
+	}
+	its.lamport += uint64(delta)
+	delimiter, err := its.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Timestamp{
+		Era:       uint32(era),
+		Lamport:   its.lamport,
+		CUID:      its.cuids[idx],
+		Delimiter: uint32(delimiter),
+	}, nil
+}
+
+// ReadValues reads values written by PutValues.
+func (its *Reader) ReadValues() ([]interface{}, error) {
+	n, err := its.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for i := 0; i < n; i++ {
+		v, err := its.ReadValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// ReadValue reads a value written by PutValue.
+func (its *Reader) ReadValue() (interface{}, error) {
+	tag, err := its.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagNull:
+		return nil, nil
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagInt:
+		v, err := its.ReadVarint()
+		return float64(v), err
+	case tagFloat:
+		var b [8]byte
+		if _, err := io.ReadFull(its.r, b[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+	case tagString:
+		return its.ReadString()
+	case tagArray:
+		values, err := its.ReadValues()
+		if values == nil && err == nil {
+			values = []interface{}{}
+		}
+		return values, err
+	case tagObject:
+		n, err := its.ReadLength()
+		if err != nil {
+			return nil, err
+		}
+		obj := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			k, err := its.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			if obj[k], err = its.ReadValue(); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("invalid tag of value: %d", tag)
+}
+
+// Done returns an error if any bytes remain.
+func (its *Reader) Done() error {
+	if its.r.Len() > 0 {
+		return fmt.Errorf("%d bytes remain after decoding", its.r.Len())
+	}
+	return nil
+}
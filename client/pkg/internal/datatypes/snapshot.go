@@ -1,9 +1,13 @@
 package datatypes
 
 import (
+	"encoding"
 	"encoding/json"
+	"fmt"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/internal/codec"
+	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/operations"
 )
 
@@ -28,13 +32,51 @@ func NewSnapshotDatatype(b *BaseDatatype, snap iface.Snapshot) *SnapshotDatatype
 
 // ApplySnapshot applies for a snapshot
 func (its *SnapshotDatatype) ApplySnapshot(snapBody []byte) errors.OrdaError {
-	its.L().Infof("apply SnapshotOperation: %v", string(snapBody))
-	if err := json.Unmarshal(snapBody, its.Snapshot); err != nil {
+	if codec.IsBinary(snapBody) {
+		its.L().Infof("apply SnapshotOperation: %d bytes in binary", len(snapBody))
+	} else {
+		its.L().Infof("apply SnapshotOperation: %v", string(snapBody))
+	}
+	if err := its.unmarshalSnapshot(snapBody); err != nil {
 		return errors.DatatypeSnapshot.New(its.L(), err.Error())
 	}
 	return nil
 }
 
+// MarshalSnapshot returns the snapshot encoded in binary or in JSON.
+func (its *SnapshotDatatype) MarshalSnapshot(inBinary bool) ([]byte, errors.OrdaError) {
+	var snap []byte
+	var err error
+	if marshaler, ok := its.Snapshot.(encoding.BinaryMarshaler); ok && inBinary {
+		snap, err = marshaler.MarshalBinary()
+	} else {
+		snap, err = json.Marshal(its.Snapshot)
+	}
+	if err != nil {
+		return nil, errors.DatatypeMarshal.New(its.L(), err.Error())
+	}
+	return snap, nil
+}
+
+// unmarshalSnapshot sets the snapshot encoded in binary or in JSON, which is the legacy format.
+func (its *SnapshotDatatype) unmarshalSnapshot(snap []byte) error {
+	if !codec.IsBinary(snap) {
+		return json.Unmarshal(snap, its.Snapshot)
+	}
+	if unmarshaler, ok := its.Snapshot.(encoding.BinaryUnmarshaler); ok {
+		return unmarshaler.UnmarshalBinary(snap)
+	}
+	return fmt.Errorf("snapshot of %v cannot be decoded in binary", its.TypeOf)
+}
+
+// PurgeTombstones purges the tombstones deleted before the stable timestamp, and returns the number of them.
+func (its *SnapshotDatatype) PurgeTombstones(stable *model.Timestamp) int {
+	if purgeable, ok := its.Snapshot.(iface.PurgeableSnapshot); ok {
+		return purgeable.PurgeTombstones(stable)
+	}
+	return 0
+}
+
 // GetSnapshot returns the snapshot
 func (its *SnapshotDatatype) GetSnapshot() iface.Snapshot {
 	return its.Snapshot
@@ -46,9 +88,9 @@ func (its *SnapshotDatatype) GetMetaAndSnapshot() ([]byte, []byte, errors.OrdaEr
 	if oErr != nil {
 		return nil, nil, oErr
 	}
-	snap, err := json.Marshal(its.GetSnapshot())
-	if err != nil {
-		return nil, nil, errors.DatatypeMarshal.New(its.L(), err.Error())
+	snap, oErr := its.MarshalSnapshot(true)
+	if oErr != nil {
+		return nil, nil, oErr
 	}
 	return meta, snap, nil
 }
@@ -58,17 +100,18 @@ func (its *SnapshotDatatype) SetMetaAndSnapshot(meta, snap []byte) errors.OrdaEr
 	if err := its.SetMeta(meta); err != nil {
 		return err
 	}
-	if err := json.Unmarshal(snap, its.GetSnapshot()); err != nil {
+	if err := its.unmarshalSnapshot(snap); err != nil {
 		return errors.DatatypeMarshal.New(its.L(), err.Error())
 	}
 	return nil
 }
 
-// CreateSnapshotOperation returns the SnapshotOperation from the snapshot and meta
+// CreateSnapshotOperation returns the SnapshotOperation from the snapshot and meta. The snapshot is encoded
+// in JSON, so that any client can read it.
 func (its *SnapshotDatatype) CreateSnapshotOperation() (iface.Operation, errors.OrdaError) {
-	snap, err := json.Marshal(its.Snapshot)
+	snap, err := its.MarshalSnapshot(false)
 	if err != nil {
-		return nil, errors.DatatypeMarshal.New(its.L(), err.Error())
+		return nil, err
 	}
 	snapOp := operations.NewSnapshotOperation(its.TypeOf, snap)
 	return snapOp, nil
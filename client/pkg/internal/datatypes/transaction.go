@@ -112,8 +112,8 @@ func (its *TransactionDatatype) BeginTransaction(
 	txCtx *TransactionContext,
 	newTxnOp bool,
 ) *TransactionContext {
-	if its.isLocked && its.txCtx == txCtx {
-		return nil // called after DoTransaction() succeeds.
+	if txCtx != nil && its.txCtx == txCtx {
+		return nil // called in the transaction which already locks the datatype.
 	}
 	its.txCtx = its.setTransactionContextAndLock(tag)
 	if newTxnOp {
@@ -149,41 +149,60 @@ func (its *TransactionDatatype) SetTransactionFail() {
 	its.success = false
 }
 
-// EndTransaction is called when a transaction ends
+// EndTransaction is called when a transaction ends. The local operations of the transaction are buffered while the
+// datatype is locked, and delivered to the wire after it is unlocked, because the wire may sync the datatype.
 func (its *TransactionDatatype) EndTransaction(txCtx *TransactionContext, withOp, isLocal bool) errors.OrdaError {
-	if txCtx == its.txCtx {
-		defer its.unlock()
-		if its.success {
-			if withOp {
-				beginOp, ok := its.txCtx.opBuffer[0].(*operations.TransactionOperation)
-				if !ok {
-					return errors.DatatypeTransaction.New(its.L(), "no transaction operation")
-				}
-				beginOp.SetNumOfOps(len(its.txCtx.opBuffer))
-			}
-			its.rollbackOps = append(its.rollbackOps, its.txCtx.opBuffer...)
-			if isLocal {
-				its.DeliverTransaction(its.txCtx.opBuffer)
-			}
-			if its.txCtx.tag != NotUserTransactionTag {
-				its.L().Infof("End the transaction: `%s`", its.txCtx.tag)
-			}
-		} else if err := its.Rollback(); err != nil {
+	if txCtx == nil || txCtx != its.txCtx {
+		return nil
+	}
+	buffered, err := its.endTransactionAndUnlock(withOp, isLocal)
+	if buffered {
+		its.DeliverTransaction(nil)
+	}
+	return err
+}
+
+func (its *TransactionDatatype) endTransactionAndUnlock(withOp, isLocal bool) (bool, errors.OrdaError) {
+	defer its.unlock()
+	if !its.success {
+		if err := its.Rollback(); err != nil {
 			panic(err)
 		}
+		return false, nil
 	}
-	return nil
+	if withOp {
+		beginOp, ok := its.txCtx.opBuffer[0].(*operations.TransactionOperation)
+		if !ok {
+			return false, errors.DatatypeTransaction.New(its.L(), "no transaction operation")
+		}
+		beginOp.SetNumOfOps(len(its.txCtx.opBuffer))
+	}
+	its.rollbackOps = append(its.rollbackOps, its.txCtx.opBuffer...)
+	buffered := isLocal && len(its.txCtx.opBuffer) > 0
+	if buffered {
+		its.BufferTransaction(its.txCtx.opBuffer)
+	}
+	if its.txCtx.tag != NotUserTransactionTag {
+		its.L().Infof("End the transaction: `%s`", its.txCtx.tag)
+	}
+	return buffered, nil
 }
 
 func (its *TransactionDatatype) unlock() {
 	if its.isLocked {
 		its.txCtx = nil
 		its.success = true
-		its.mutex.Unlock()
 		its.isLocked = false
+		its.mutex.Unlock()
 	}
 }
 
+// RLock locks the datatype for reading, and returns the function to unlock it.
+func (its *TransactionDatatype) RLock() func() {
+	its.mutex.RLock()
+	return its.mutex.RUnlock
+}
+
 // DoTransaction enables datatypes to perform a transaction.
 func (its *TransactionDatatype) DoTransaction(
 	tag string,
@@ -209,7 +228,7 @@ func (its *TransactionDatatype) ExecuteRemoteTransactionWithCtx(
 	currentTxCtx *TransactionContext,
 	obtainList bool,
 ) ([]interface{}, errors.OrdaError) {
-	var txCtx *TransactionContext
+	txCtx := currentTxCtx
 	if len(transaction) > 1 {
 		txOp, ok := operations.ModelToOperation(transaction[0]).(*operations.TransactionOperation)
 		if !ok {
@@ -218,12 +237,14 @@ func (its *TransactionDatatype) ExecuteRemoteTransactionWithCtx(
 		if int(txOp.GetNumOfOps()) != len(transaction) {
 			return nil, errors.DatatypeTransaction.New(its.L(), "not matched number of operations")
 		}
-		txCtx = its.BeginTransaction(txOp.GetBody().Tag, currentTxCtx, false)
-		defer func() {
-			if err := its.EndTransaction(txCtx, false, false); err != nil {
-				// _ = log.OrdaError(err)
-			}
-		}()
+		if began := its.BeginTransaction(txOp.GetBody().Tag, currentTxCtx, false); began != nil {
+			txCtx = began
+			defer func() {
+				if err := its.EndTransaction(began, false, false); err != nil {
+					// _ = log.OrdaError(err)
+				}
+			}()
+		}
 		transaction = transaction[1:]
 	}
 	var opList []interface{}
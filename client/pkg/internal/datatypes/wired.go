@@ -1,12 +1,14 @@
 package datatypes
 
 import (
+	gocontext "context"
 	"fmt"
 	"github.com/orda-io/orda/client/pkg/constants"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/operations"
+	"time"
 )
 
 // WiredDatatype implements the datatype features related to the synchronization with Orda server
@@ -15,6 +17,29 @@ type WiredDatatype struct {
 	wire        iface.Wire
 	checkPoint  *model.CheckPoint
 	localBuffer []*model.Operation
+	rebasing    bool
+	retries     int
+	pulling     *pullingSnapshot
+	path        string
+	seenMarks   []seenMark
+	maxUnpushed int
+	sentSeq     uint64
+	followUps   []func()
+	acked       chan struct{}
+}
+
+// seenMark records the sseq which the client had seen when it made the operations from seq.
+type seenMark struct {
+	seq  uint64
+	sseq uint64
+}
+
+// pullingSnapshot is the snapshot being pulled in chunks, which is applied when all the chunks are received.
+type pullingSnapshot struct {
+	sseq   uint64
+	total  uint64
+	option model.PushPullPackOption
+	data   []byte
 }
 
 // NewWiredDatatype creates a new wiredDatatype
@@ -24,6 +49,7 @@ func NewWiredDatatype(w iface.Wire, t *TransactionDatatype) *WiredDatatype {
 		checkPoint:          model.NewCheckPoint(),
 		localBuffer:         make([]*model.Operation, 0, constants.OperationBufferSize),
 		wire:                w,
+		acked:               make(chan struct{}),
 	}
 }
 
@@ -31,6 +57,45 @@ func NewWiredDatatype(w iface.Wire, t *TransactionDatatype) *WiredDatatype {
 func (its *WiredDatatype) ResetWired() {
 	its.localBuffer = make([]*model.Operation, 0, constants.OperationBufferSize)
 	its.opID.Seq = 0
+	its.pulling = nil
+	its.seenMarks = nil
+	its.sentSeq = 0
+}
+
+// SetMaxUnpushed sets the limit of the local operations not pushed yet. If it is 0, the operations are unlimited.
+func (its *WiredDatatype) SetMaxUnpushed(maxUnpushed int) {
+	its.maxUnpushed = maxUnpushed
+}
+
+// SentenceInTx executes an operation with a transaction, rejecting the local operation if the operations not pushed
+// yet reach the limit.
+func (its *WiredDatatype) SentenceInTx(
+	ctx *TransactionContext,
+	op iface.Operation,
+	isLocal bool,
+) (interface{}, errors.OrdaError) {
+	txCtx := its.BeginTransaction(NotUserTransactionTag, ctx, false)
+	defer func() {
+		if err := its.EndTransaction(txCtx, false, isLocal); err != nil {
+
+		}
+	}()
+	if isLocal && its.maxUnpushed > 0 && its.opID.Seq-its.checkPoint.Cseq >= uint64(its.maxUnpushed) {
+		if its.coalesce(); its.opID.Seq-its.checkPoint.Cseq >= uint64(its.maxUnpushed) {
+			return nil, errors.DatatypeTooManyUnpushed.New(its.L(), its.maxUnpushed)
+		}
+	}
+	return its.TransactionDatatype.SentenceInTx(its.txCtx, op, isLocal)
+}
+
+// SetPath sets the path of the subtree to which the Document subscribes partially.
+func (its *WiredDatatype) SetPath(path string) {
+	its.path = path
+}
+
+// GetPath returns the path of the subtree to which the Document subscribes partially; empty for the whole one.
+func (its *WiredDatatype) GetPath() string {
+	return its.path
 }
 
 // SetCheckPoint sets the CheckPoint
@@ -39,9 +104,24 @@ func (its *WiredDatatype) SetCheckPoint(sseq uint64, cseq uint64) {
 	its.checkPoint.Cseq = cseq
 }
 
+// SetEra sets the era of the operations made from now on.
+func (its *WiredDatatype) SetEra(era uint32) {
+	its.opID.Era = era
+}
+
 // ReceiveRemoteModelOperations executes remote model operations.
 func (its *WiredDatatype) ReceiveRemoteModelOperations(ops []*model.Operation, obtainList bool) ([]interface{}, errors.OrdaError) {
-	// datatype := its.datatype
+	txCtx := its.BeginTransaction(NotUserTransactionTag, nil, false)
+	defer func() {
+		if err := its.EndTransaction(txCtx, false, false); err != nil {
+
+		}
+	}()
+	return its.receiveRemoteModelOperations(ops, obtainList)
+}
+
+// receiveRemoteModelOperations executes remote model operations in the transaction which locks the datatype.
+func (its *WiredDatatype) receiveRemoteModelOperations(ops []*model.Operation, obtainList bool) ([]interface{}, errors.OrdaError) {
 	var opList []interface{}
 	for i := 0; i < len(ops); {
 		modelOp := ops[i]
@@ -56,7 +136,7 @@ func (its *WiredDatatype) ReceiveRemoteModelOperations(ops []*model.Operation, o
 			transaction = []*model.Operation{modelOp}
 			i++
 		}
-		txList, err := its.ExecuteRemoteTransaction(transaction, obtainList)
+		txList, err := its.ExecuteRemoteTransactionWithCtx(transaction, its.txCtx, obtainList)
 		if err != nil {
 			return nil, err
 		}
@@ -67,8 +147,26 @@ func (its *WiredDatatype) ReceiveRemoteModelOperations(ops []*model.Operation, o
 
 // CreatePushPullPack creates a PushPullPack
 func (its *WiredDatatype) CreatePushPullPack() *model.PushPullPack {
+	txCtx := its.BeginTransaction(NotUserTransactionTag, nil, false)
+	defer func() {
+		if err := its.EndTransaction(txCtx, false, false); err != nil {
+
+		}
+	}()
 	seq := its.checkPoint.Cseq
-	modelOps := its.getModelOperations(seq + 1)
+	var modelOps []*model.Operation
+	var chunk *model.SnapshotChunk
+	if its.pulling != nil {
+		// the local operations are pushed after the snapshot is applied
+		modelOps = []*model.Operation{}
+		chunk = &model.SnapshotChunk{Sseq: its.pulling.sseq, Offset: uint64(len(its.pulling.data))}
+	} else {
+		its.coalesce()
+		modelOps = its.getModelOperations(seq + 1)
+		if n := len(modelOps); n > 0 && modelOps[n-1].ID.GetSeq() > its.sentSeq {
+			its.sentSeq = modelOps[n-1].ID.GetSeq()
+		}
+	}
 	cp := &model.CheckPoint{
 		Sseq: its.checkPoint.GetSseq(),
 		Cseq: its.checkPoint.GetCseq() + uint64(len(modelOps)),
@@ -80,16 +178,123 @@ func (its *WiredDatatype) CreatePushPullPack() *model.PushPullPack {
 		option.SetSubscribeBit()
 	} else if its.state == model.StateOfDatatype_DUE_TO_SUBSCRIBE_CREATE {
 		option.SetSubscribeBit().SetCreateBit()
+	} else if its.rebasing {
+		option.SetSnapshotBit()
 	}
+	seenSseq, lowest := its.getStabilityBounds()
 	return &model.PushPullPack{
-		Key:        its.Key,
-		DUID:       its.id,
-		Option:     uint32(option),
-		CheckPoint: cp,
-		Era:        its.GetEra(),
-		Type:       its.TypeOf,
-		Operations: modelOps,
+		Key:           its.Key,
+		DUID:          its.id,
+		Option:        uint32(option),
+		CheckPoint:    cp,
+		Era:           its.GetEra(),
+		Type:          its.TypeOf,
+		Operations:    modelOps,
+		SnapshotChunk: chunk,
+		Path:          its.path,
+		SeenSseq:      seenSseq,
+		Lowest:        lowest,
+	}
+}
+
+// getStabilityBounds returns the sseq which the client had seen when it made the oldest operation not acknowledged,
+// and the timestamp of the operation. Without such an operation, they are of the current CheckPoint and the next
+// operation. The server computes the causal stability from the bounds of all the clients.
+func (its *WiredDatatype) getStabilityBounds() (uint64, *model.Timestamp) {
+	unacked := its.getModelOperations(its.checkPoint.Cseq + 1)
+	if len(unacked) == 0 {
+		its.seenMarks = nil
+		return its.checkPoint.Sseq, its.opID.Clone().Next().GetTimestamp()
+	}
+	seq := unacked[0].ID.GetSeq()
+	for len(its.seenMarks) > 1 && its.seenMarks[1].seq <= seq {
+		its.seenMarks = its.seenMarks[1:]
+	}
+	if len(its.seenMarks) == 0 || its.seenMarks[0].seq > seq {
+		return 0, unacked[0].ID.GetTimestamp()
+	}
+	return its.seenMarks[0].sseq, unacked[0].ID.GetTimestamp()
+}
+
+// markSeen records the sseq seen by the operation of seq. While pulling a snapshot in chunks, nothing is regarded
+// as seen, because the CheckPoint is ahead of the applied operations.
+func (its *WiredDatatype) markSeen(seq uint64) {
+	sseq := its.checkPoint.Sseq
+	if its.pulling != nil {
+		sseq = 0
 	}
+	if n := len(its.seenMarks); n > 0 && its.seenMarks[n-1].sseq == sseq {
+		return
+	}
+	its.seenMarks = append(its.seenMarks, seenMark{seq: seq, sseq: sseq})
+}
+
+// coalesce merges the redundant local operations which have never been pushed, and renumbers their seqs to follow
+// the operations pushed. The operations pushed once are kept, because the server might have received them.
+func (its *WiredDatatype) coalesce() {
+	from := its.checkPoint.Cseq
+	if its.sentSeq > from {
+		from = its.sentSeq
+	}
+	ops := its.getModelOperations(from + 1)
+	if len(ops) < 2 || ops[len(ops)-1].ID.GetSeq() != its.opID.Seq {
+		// the operations of a transaction in progress are not in the local buffer yet
+		return
+	}
+	coalesced := operations.Coalesce(ops)
+	removed := len(ops) - len(coalesced)
+	if removed == 0 {
+		return
+	}
+	its.renumberSeenMarks(from, coalesced)
+	for i, op := range coalesced {
+		if seq := from + uint64(i) + 1; op.ID.GetSeq() != seq {
+			opID := op.ID.Clone()
+			opID.Seq = seq
+			coalesced[i] = &model.Operation{ID: opID, OpType: op.OpType, Body: op.Body}
+		}
+	}
+	its.localBuffer = append(its.localBuffer[:len(its.localBuffer)-len(ops)], coalesced...)
+	its.opID.Seq -= uint64(removed)
+	its.L().Infof("coalesce %d local operations into %d", len(ops), len(coalesced))
+}
+
+// renumberSeenMarks moves the marks after from to the operations coalesced. A mark of the operations dropped moves to
+// the next operation left, and the earliest mark is kept if several marks move to the same operation.
+func (its *WiredDatatype) renumberSeenMarks(from uint64, coalesced []*model.Operation) {
+	marks := make([]seenMark, 0, len(its.seenMarks))
+	i := 0
+	for _, mark := range its.seenMarks {
+		if mark.seq <= from {
+			marks = append(marks, mark)
+			continue
+		}
+		for i < len(coalesced) && coalesced[i].ID.GetSeq() < mark.seq {
+			i++
+		}
+		if i == len(coalesced) {
+			break
+		}
+		seq := from + uint64(i) + 1
+		if n := len(marks); n > 0 && marks[n-1].seq == seq {
+			continue
+		}
+		marks = append(marks, seenMark{seq: seq, sseq: mark.sseq})
+	}
+	its.seenMarks = marks
+}
+
+// trimLocalBuffer removes the operations acknowledged by the server from the local buffer.
+func (its *WiredDatatype) trimLocalBuffer() {
+	acked := 0
+	for acked < len(its.localBuffer) && its.localBuffer[acked].ID.GetSeq() <= its.checkPoint.Cseq {
+		acked++
+	}
+	if acked == 0 {
+		return
+	}
+	remains := make([]*model.Operation, 0, constants.OperationBufferSize)
+	its.localBuffer = append(remains, its.localBuffer[acked:]...)
 }
 
 func (its *WiredDatatype) getModelOperations(cseq uint64) []*model.Operation {
@@ -115,27 +320,7 @@ func (its *WiredDatatype) calculatePullingOperations(newCheckPoint *model.CheckP
 }
 
 func (its *WiredDatatype) checkOptionAndError(ppp *model.PushPullPack) errors.OrdaError {
-	if ppp.GetPushPullPackOption().HasErrorBit() {
-		modelOp := ppp.GetOperations()[0]
-		errOp, ok := operations.ModelToOperation(modelOp).(*operations.ErrorOperation)
-		if ok {
-			switch errOp.GetPushPullError().Code {
-			case errors.PushPullAbortionOfServer:
-				// TODO: implement me.
-			case errors.PushPullAbortionOfClient:
-				// TODO: implement me.
-			case errors.PushPullDuplicateKey:
-				return errors.DatatypeCreate.New(its.L(), fmt.Sprintf("duplicated key:'%s'", its.Key))
-			case errors.PushPullMissingOps:
-				// TODO: implement me.
-			case errors.PushPullNoDatatypeToSubscribe:
-				return errors.DatatypeSubscribe.New(its.L(), fmt.Sprintf("%v", errOp.GetPushPullError().Msg))
-			}
-			panic("Not implemented yet")
-		} else {
-			panic("Not implemented yet")
-		}
-	} else if ppp.GetPushPullPackOption().HasSubscribeBit() {
+	if ppp.GetPushPullPackOption().HasSubscribeBit() {
 		modelOp := ppp.GetOperations()[0]
 		_, ok := operations.ModelToOperation(modelOp).(*operations.SnapshotOperation)
 		if !ok {
@@ -147,10 +332,161 @@ func (its *WiredDatatype) checkOptionAndError(ppp *model.PushPullPack) errors.Or
 		its.checkPoint.Cseq = ppp.CheckPoint.Cseq
 		its.checkPoint.Sseq = ppp.CheckPoint.Sseq - uint64(len(ppp.Operations))
 		its.L().Infof("ready to subscribe: %s", its.checkPoint.ToString())
+	} else if ppp.GetPushPullPackOption().HasSnapshotBit() {
+		if len(ppp.GetOperations()) == 0 {
+			return errors.DatatypeSnapshot.New(its.L(), "catch up without SnapshotOp")
+		}
+		if _, ok := operations.ModelToOperation(ppp.GetOperations()[0]).(*operations.SnapshotOperation); !ok {
+			return errors.DatatypeSnapshot.New(its.L(), "catch up without SnapshotOp")
+		}
 	}
 	return nil
 }
 
+// receiveSnapshotChunk accumulates the chunk of the snapshot in the PushPullPack. It returns true when all the chunks
+// are received; then, the PushPullPack is restored to have the SnapshotOperation as if it was pulled at once.
+func (its *WiredDatatype) receiveSnapshotChunk(ppp *model.PushPullPack) bool {
+	chunk := ppp.GetSnapshotChunk()
+	pulling := its.pulling
+	if pulling == nil || pulling.sseq != chunk.Sseq || uint64(len(pulling.data)) != chunk.Offset {
+		if chunk.Offset != 0 {
+			its.L().Warnf("pull the snapshot of sseq %d again due to the chunk at offset %d", chunk.Sseq, chunk.Offset)
+			its.pulling = &pullingSnapshot{sseq: chunk.Sseq, total: chunk.Total}
+			its.rebasing = true
+			return false
+		}
+		option := *ppp.GetPushPullPackOption() &^ model.PushPullBitMore
+		pulling = &pullingSnapshot{sseq: chunk.Sseq, total: chunk.Total, option: option, data: make([]byte, 0, chunk.Total)}
+		its.pulling = pulling
+	}
+	pulling.data = append(pulling.data, chunk.Data...)
+	its.L().Infof("pull %d/%d bytes of snapshot of sseq %d", len(pulling.data), pulling.total, pulling.sseq)
+	received, total := uint64(len(pulling.data)), pulling.total
+	its.followUps = append(its.followUps, func() {
+		its.HandleSnapshotProgress(received, total)
+	})
+	if uint64(len(pulling.data)) < pulling.total {
+		its.syncCheckPoint(ppp.CheckPoint)
+		return false
+	}
+	its.pulling = nil
+	snapOp := operations.NewSnapshotOperation(ppp.Type, pulling.data)
+	ppp.Operations = append([]*model.Operation{snapOp.ToModelOperation()}, ppp.Operations...)
+	ppp.Option |= uint32(pulling.option)
+	ppp.SnapshotChunk = nil
+	return true
+}
+
+// handlePushPullError recovers from the error of the push-pull delivered by the server. It returns the error
+// which should be surfaced to the error handler, or nil if the datatype recovers by itself.
+func (its *WiredDatatype) handlePushPullError(ppp *model.PushPullPack) errors.OrdaError {
+	if len(ppp.GetOperations()) == 0 {
+		return errors.DatatypeSync.New(its.L(), "push-pull error without ErrorOperation")
+	}
+	errOp, ok := operations.ModelToOperation(ppp.GetOperations()[0]).(*operations.ErrorOperation)
+	if !ok {
+		return errors.DatatypeSync.New(its.L(), "push-pull error without ErrorOperation")
+	}
+	ppErr := errOp.GetPushPullError()
+	switch ppErr.Code {
+	case errors.PushPullAbortionOfServer:
+		return its.retry(ppErr)
+	case errors.PushPullAbortionOfClient:
+		return errors.DatatypeSync.New(its.L(), ppErr.Msg)
+	case errors.PushPullDuplicateKey:
+		return errors.DatatypeCreate.New(its.L(), fmt.Sprintf("duplicated key:'%s'", its.Key))
+	case errors.PushPullMissingOps:
+		return its.resend(ppp.CheckPoint, ppErr)
+	case errors.PushPullNoDatatypeToSubscribe:
+		if its.state == model.StateOfDatatype_SUBSCRIBED {
+			return its.createAgain()
+		}
+		return errors.DatatypeSubscribe.New(its.L(), ppErr.Msg)
+	case errors.PushPullEraMismatch:
+		// the local operations not pushed yet are replayed on the snapshot of the new era
+		its.L().Infof("rebase on a snapshot of era %d: %v", ppp.Era, ppErr.Msg)
+		its.SetEra(ppp.Era)
+		its.rebasing = true
+		return nil
+	case errors.PushPullBehindCompaction:
+		// the local operations might depend on the tombstones purged by the compaction
+		its.L().Infof("rebase on the latest snapshot: %v", ppErr.Msg)
+		its.rebasing = true
+		return nil
+	}
+	return errors.DatatypeSync.New(its.L(), "unknown push-pull error: "+ppErr.Error())
+}
+
+// retry makes the datatype push-pull again after a backoff, until the retries exceed the limit.
+func (its *WiredDatatype) retry(ppErr *errors.PushPullError) errors.OrdaError {
+	if its.retries >= constants.MaxSyncRetries {
+		its.retries = 0
+		return errors.DatatypeSync.New(its.L(), fmt.Sprintf("give up after %d retries: %v", constants.MaxSyncRetries, ppErr.Msg))
+	}
+	its.retries++
+	its.L().Warnf("retry push-pull (%d/%d): %v", its.retries, constants.MaxSyncRetries, ppErr.Msg)
+	return nil
+}
+
+// resend rewinds the CheckPoint to that of the server, so that the operations missed by the server are pushed again.
+// If the local buffer no longer has them, the datatype rebases on a snapshot discarding the local operations.
+func (its *WiredDatatype) resend(serverCP *model.CheckPoint, ppErr *errors.PushPullError) errors.OrdaError {
+	if len(its.localBuffer) > 0 && its.localBuffer[0].ID.GetSeq() <= serverCP.Cseq+1 {
+		its.L().Infof("resend operations after cseq %d: %v", serverCP.Cseq, ppErr.Msg)
+		its.checkPoint.Cseq = serverCP.Cseq
+		return its.retry(ppErr)
+	}
+	discarded := its.opID.Seq - serverCP.Cseq
+	its.localBuffer = make([]*model.Operation, 0, constants.OperationBufferSize)
+	its.seenMarks = nil
+	its.opID.Seq = serverCP.Cseq
+	its.sentSeq = serverCP.Cseq
+	its.checkPoint.Cseq = serverCP.Cseq
+	its.rebasing = true
+	return errors.DatatypeSync.New(its.L(), fmt.Sprintf("discard %d local operations missed by server: %v", discarded, ppErr.Msg))
+}
+
+// createAgain makes the datatype created again with the local state, when it has been removed from the server.
+// The SnapshotOperation to create it is issued after the PushPullPack is applied.
+func (its *WiredDatatype) createAgain() errors.OrdaError {
+	its.L().Infof("create again the datatype removed from the server")
+	its.state = model.StateOfDatatype_DUE_TO_SUBSCRIBE_CREATE
+	its.ResetWired()
+	its.SetEra(0)
+	its.checkPoint = model.NewCheckPoint()
+	its.rebasing = true
+	state := its.state
+	its.followUps = append(its.followUps, func() {
+		if err := its.Datatype.SubscribeOrCreate(state); err != nil {
+			its.HandleErrors(err)
+		}
+	})
+	return nil
+}
+
+// NeedRebase verifies if the datatype needs to sync again in order to rebase
+func (its *WiredDatatype) NeedRebase() bool {
+	return its.rebasing
+}
+
+// GetRetryBackoff returns how long the datatype should wait before retrying the failed push-pull;
+// zero means no retry is needed.
+func (its *WiredDatatype) GetRetryBackoff() time.Duration {
+	return time.Duration(its.retries) * constants.SyncRetryBackoff
+}
+
+// resetForSnapshot resets the snapshot in order to catch up with the snapshot delivered by the server.
+// It returns the local operations which are not reflected in the delivered snapshot, and should be replayed.
+func (its *WiredDatatype) resetForSnapshot() ([]*model.Operation, errors.OrdaError) {
+	unacked := its.getModelOperations(its.checkPoint.Cseq + 1)
+	its.ResetSnapshot()
+	if err := its.ResetTransaction(); err != nil {
+		return nil, err
+	}
+	its.L().Infof("catch up with snapshot, and replay %d local operations", len(unacked))
+	return unacked, nil
+}
+
 func (its *WiredDatatype) excludeDuplicatedOperations(ppp *model.PushPullPack) {
 	pulled := its.calculatePullingOperations(ppp.CheckPoint)
 	if len(ppp.Operations) > pulled {
@@ -185,6 +521,8 @@ func (its *WiredDatatype) updateStateOfDatatype(
 		model.StateOfDatatype_DUE_TO_SUBSCRIBE_CREATE:
 		if its.state == model.StateOfDatatype_DUE_TO_SUBSCRIBE_CREATE && ppp.GetPushPullPackOption().HasSubscribeBit() {
 			its.localBuffer = make([]*model.Operation, 0, constants.OperationBufferSize)
+			its.seenMarks = nil
+			its.sentSeq = 0
 			newOpID := model.NewOperationIDWithCUID(its.opID.CUID)
 			newOpID.Lamport = 1 // Because of SnapshotOperation
 			its.SetOpID(newOpID)
@@ -206,24 +544,70 @@ func (its *WiredDatatype) updateStateOfDatatype(
 	return oldState, its.state, err
 }
 
-// ApplyPushPullPack applies for PushPullPack
+// ApplyPushPullPack applies for PushPullPack. The datatype is locked while applying it, and what cannot be done in
+// the lock, such as calling a handler synchronously, follows after it is unlocked.
 func (its *WiredDatatype) ApplyPushPullPack(ppp *model.PushPullPack) {
+	txCtx := its.BeginTransaction(NotUserTransactionTag, nil, false)
+	its.applyPushPullPack(ppp)
+	followUps := its.followUps
+	its.followUps = nil
+	close(its.acked)
+	its.acked = make(chan struct{})
+	if err := its.EndTransaction(txCtx, false, false); err != nil {
+
+	}
+	for _, followUp := range followUps {
+		followUp()
+	}
+}
+
+func (its *WiredDatatype) applyPushPullPack(ppp *model.PushPullPack) {
 	defer its.L().Infof("end ApplyPushPull")
 	var oldState, newState model.StateOfDatatype
 	var errs errors.OrdaError = &errors.MultipleOrdaErrors{}
 	var opList []interface{}
+	var unacked []*model.Operation
+	its.rebasing = false
+	if ppp.GetPushPullPackOption().HasErrorBit() {
+		if err := its.handlePushPullError(ppp); err != nil {
+			go its.callHandlers(err, its.state, its.state, nil)
+		}
+		return
+	}
+	its.retries = 0
+	if ppp.GetSnapshotChunk() != nil && !its.receiveSnapshotChunk(ppp) {
+		return
+	}
 	err := its.checkOptionAndError(ppp)
 	if err == nil {
-		its.excludeDuplicatedOperations(ppp)
+		its.SetEra(ppp.Era)
+		if ppp.GetPushPullPackOption().HasSnapshotBit() {
+			if unacked, err = its.resetForSnapshot(); err != nil {
+				errs = errs.Append(err)
+			}
+		} else {
+			its.excludeDuplicatedOperations(ppp)
+		}
 		its.syncCheckPoint(ppp.CheckPoint)
 		oldState, newState, err = its.updateStateOfDatatype(ppp)
 		if err != nil {
 			errs = errs.Append(err)
 		}
-		opList, err = its.ReceiveRemoteModelOperations(ppp.Operations, true)
+		opList, err = its.receiveRemoteModelOperations(ppp.Operations, true)
 		if err != nil {
 			errs = errs.Append(err)
 		}
+		if len(unacked) > 0 {
+			if _, err = its.receiveRemoteModelOperations(unacked, false); err != nil {
+				errs = errs.Append(err)
+			}
+		}
+		if stable := ppp.GetStable(); stable != nil {
+			if purged := its.PurgeTombstones(stable); purged > 0 {
+				its.L().Infof("purge %d tombstones deleted before %s", purged, stable.ToString())
+			}
+		}
+		its.trimLocalBuffer()
 	} else {
 		errs = errs.Append(err)
 	}
@@ -247,12 +631,26 @@ func (its *WiredDatatype) callHandlers(
 	}
 }
 
-// DeliverTransaction delivers the transaction if needed
-func (its *WiredDatatype) DeliverTransaction(transaction []iface.Operation) {
-
+// BufferTransaction appends the local operations of the transaction to the local buffer.
+func (its *WiredDatatype) BufferTransaction(transaction []iface.Operation) {
+	if len(transaction) > 0 {
+		its.markSeen(transaction[0].GetID().GetSeq())
+	}
 	for _, op := range transaction {
 		its.localBuffer = append(its.localBuffer, op.ToModelOperation())
 	}
+}
+
+// DeliverTransaction delivers the transaction if needed. It must not be called while the datatype is locked, because
+// the wire may sync the datatype.
+func (its *WiredDatatype) DeliverTransaction(transaction []iface.Operation) {
+	if len(transaction) > 0 {
+		txCtx := its.BeginTransaction(NotUserTransactionTag, nil, false)
+		its.BufferTransaction(transaction)
+		if err := its.EndTransaction(txCtx, false, false); err != nil {
+
+		}
+	}
 	if its.wire == nil && its.ctx.Client.SyncType != model.SyncType_REALTIME {
 		return
 	}
@@ -260,12 +658,44 @@ func (its *WiredDatatype) DeliverTransaction(transaction []iface.Operation) {
 	its.wire.DeliverTransaction(its)
 }
 
+// Flush blocks until the local operations issued before are acknowledged by the server, or the context is done.
+// Since coalescing only lowers the seqs of the operations not pushed yet, they are acknowledged when the Cseq of the
+// CheckPoint reaches the last seq at the call, or the current last seq if it is lower.
+func (its *WiredDatatype) Flush(ctx gocontext.Context) errors.OrdaError {
+	if its.ctx.Client.SyncType == model.SyncType_LOCAL_ONLY {
+		return nil
+	}
+	unlock := its.RLock()
+	seq := its.opID.Seq
+	unlock()
+	for {
+		unlock = its.RLock()
+		acked := its.acked
+		done := its.checkPoint.Cseq >= seq || its.checkPoint.Cseq >= its.opID.Seq
+		unlock()
+		if done {
+			return nil
+		}
+		if its.wire != nil {
+			// pushes the operations unless another sync is in progress, which wakes this up when it ends
+			its.wire.DeliverTransaction(its)
+		}
+		select {
+		case <-acked:
+		case <-ctx.Done():
+			return errors.DatatypeFlush.New(its.L(), ctx.Err())
+		}
+	}
+}
+
 // NeedPull verifies if the datatype needs to pull
 func (its *WiredDatatype) NeedPull(sseq uint64) bool {
+	defer its.RLock()()
 	return its.checkPoint.Sseq < sseq
 }
 
 // NeedPush verifies if the datatype needs to push
 func (its *WiredDatatype) NeedPush() bool {
+	defer its.RLock()()
 	return its.checkPoint.Cseq < its.opID.GetSeq()
 }
@@ -1,21 +1,28 @@
 package managers
 
 import (
+	gocontext "context"
 	"fmt"
+	"github.com/orda-io/orda/client/pkg/constants"
 	"github.com/orda-io/orda/client/pkg/context"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/sync/semaphore"
 )
 
-// DatatypeManager manages Orda datatypes regarding operations
+// DatatypeManager manages Orda datatypes regarding operations. The dataMap is guarded by the mutex, since it is
+// accessed by the application, the notification and the goroutines delivering transactions; the sema makes the
+// datatypes synchronized one at a time.
 type DatatypeManager struct {
 	ctx         *context.ClientContext
 	syncManager *SyncManager
 	sema        *semaphore.Weighted
+	mutex       sync.RWMutex
 	dataMap     map[string]iface.Datatype
 }
 
@@ -57,7 +64,7 @@ func (its *DatatypeManager) DeliverTransaction(wired iface.WiredDatatype) {
 
 // ExistDatatype returns the datatype if the specified key and type
 func (its *DatatypeManager) ExistDatatype(key string, typeOf model.TypeOfDatatype) (iface.Datatype, errors.OrdaError) {
-	if data, ok := its.dataMap[key]; ok {
+	if data := its.Get(key); data != nil {
 		if data.GetType() == typeOf {
 			its.ctx.L().Warnf("already subscribed datatype '%s'", key)
 			return data, nil
@@ -77,8 +84,8 @@ func (its *DatatypeManager) ReceiveNotification(topic string, notification model
 	}
 	splitTopic := strings.Split(topic, "/")
 	datatypeKey := splitTopic[1]
-	if data, ok := its.dataMap[datatypeKey]; ok && data.GetDUID() == notification.DUID {
-		if err := its.syncIfNeedPull(data, notification.Sseq); err != nil {
+	if data := its.Get(datatypeKey); data != nil {
+		if err := its.syncIfNeedPull(data, notification.DUID, notification.Sseq); err != nil {
 			// TODO: call errorHandler
 			return
 		}
@@ -102,15 +109,42 @@ func (its *DatatypeManager) SyncAll() errors.OrdaError {
 	}()
 
 	var pushPullPacks []*model.PushPullPack
-	for _, data := range its.dataMap {
+	for _, data := range its.getAll() {
 		ppp := data.CreatePushPullPack()
 		pushPullPacks = append(pushPullPacks, ppp)
 	}
 	return its.syncPushPullPacks(pushPullPacks...)
 }
 
-// syncIfNeedPull enables the datatype of the specified key and sseq to be synchronized if needed.
-func (its *DatatypeManager) syncIfNeedPull(data iface.WiredDatatype, sseq uint64) errors.OrdaError {
+// Flush blocks until the local operations of all the datatypes issued before are acknowledged by the server, or the
+// context is done.
+func (its *DatatypeManager) Flush(ctx gocontext.Context) errors.OrdaError {
+	all := its.getAll()
+	errCh := make(chan errors.OrdaError, len(all))
+	for _, data := range all {
+		go func(data iface.Datatype) {
+			errCh <- data.Flush(ctx)
+		}(data)
+	}
+	var errs errors.OrdaError = &errors.MultipleOrdaErrors{}
+	for range all {
+		if err := <-errCh; err != nil {
+			errs = errs.Append(err)
+		}
+	}
+	return errs.Return()
+}
+
+// syncIfNeedPull enables the datatype of the specified DUID and sseq to be synchronized if needed.
+func (its *DatatypeManager) syncIfNeedPull(data iface.WiredDatatype, duid string, sseq uint64) errors.OrdaError {
+	if err := its.sema.Acquire(its.ctx.Ctx(), 1); err != nil {
+		return errors.ClientSync.New(its.ctx.L())
+	}
+	defer its.sema.Release(1)
+	if data.GetDUID() != duid {
+		its.ctx.L().Warnf("receive a notification for not subscribed datatype %s(%s)", data.GetKey(), duid)
+		return nil
+	}
 	if data.NeedPull(sseq) {
 		its.ctx.L().Infof("need to sync after notification: %s (sseq:%d)", data.GetKey(), sseq)
 		return its.sync(data)
@@ -134,6 +168,8 @@ func (its *DatatypeManager) OnChangeDatatypeState(dt iface.Datatype, state model
 
 // Get returns a datatype for the specified key
 func (its *DatatypeManager) Get(key string) iface.Datatype {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
 	dt, ok := its.dataMap[key]
 	if ok {
 		return dt
@@ -141,15 +177,27 @@ func (its *DatatypeManager) Get(key string) iface.Datatype {
 	return nil
 }
 
+// getAll returns all the datatypes managed.
+func (its *DatatypeManager) getAll() []iface.Datatype {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	all := make([]iface.Datatype, 0, len(its.dataMap))
+	for _, data := range its.dataMap {
+		all = append(all, data)
+	}
+	return all
+}
+
 // SubscribeOrCreate links a datatype with the datatype
 func (its *DatatypeManager) SubscribeOrCreate(dt iface.Datatype, state model.StateOfDatatype) errors.OrdaError {
-	if _, ok := its.dataMap[dt.GetKey()]; !ok {
-		its.dataMap[dt.GetKey()] = dt
-		if err := dt.SubscribeOrCreate(state); err != nil {
-			return err
-		}
+	its.mutex.Lock()
+	if _, ok := its.dataMap[dt.GetKey()]; ok {
+		its.mutex.Unlock()
+		return nil
 	}
-	return nil
+	its.dataMap[dt.GetKey()] = dt
+	its.mutex.Unlock()
+	return dt.SubscribeOrCreate(state)
 }
 
 // sync enables a datatype of the specified key to be synchronized.
@@ -159,7 +207,7 @@ func (its *DatatypeManager) sync(data iface.WiredDatatype) errors.OrdaError {
 }
 
 func (its *DatatypeManager) needPush() bool {
-	for _, data := range its.dataMap {
+	for _, data := range its.getAll() {
 		if data.NeedPush() {
 			return true
 		}
@@ -167,15 +215,78 @@ func (its *DatatypeManager) needPush() bool {
 	return false
 }
 
+// syncPushPullPacks syncs the PushPullPacks; the datatypes having more operations to pull or needing to rebase
+// keep syncing until caught up, and those whose push-pulls are aborted by the server retry after a backoff.
+// The sync gives up after MaxSyncRounds consecutive rounds without progress, or when the client context is done
+// while waiting for the backoff.
 func (its *DatatypeManager) syncPushPullPacks(pppList ...*model.PushPullPack) errors.OrdaError {
-	pushPullResponse, err := its.syncManager.Sync(pppList...)
-	if err != nil {
-		return err
-	}
-	for _, ppp := range pushPullResponse.PushPullPacks {
-		if data, ok := its.dataMap[ppp.GetKey()]; ok {
-			data.ApplyPushPullPack(ppp)
+	for stalled := 0; len(pppList) > 0; {
+		if stalled >= constants.MaxSyncRounds {
+			return errors.ClientSync.New(its.ctx.L(), fmt.Sprintf("exceed %d rounds without progress", constants.MaxSyncRounds))
+		}
+		pushPullResponse, err := its.syncManager.Sync(pppList...)
+		if err != nil {
+			return err
+		}
+		sent := make(map[string]*model.PushPullPack)
+		for _, ppp := range pppList {
+			sent[ppp.GetKey()] = ppp
+		}
+		pppList = nil
+		progressed := false
+		var backoff time.Duration
+		var retrying []iface.WiredDatatype
+		for _, ppp := range pushPullResponse.PushPullPacks {
+			if data := its.Get(ppp.GetKey()); data != nil {
+				if hasProgressed(sent[ppp.GetKey()], ppp) {
+					progressed = true
+				}
+				data.ApplyPushPullPack(ppp)
+				if ppp.GetPushPullPackOption().HasMoreBit() {
+					its.ctx.L().Infof("pull more operations of %s", data.GetKey())
+					pppList = append(pppList, data.CreatePushPullPack())
+				} else if data.NeedRebase() {
+					its.ctx.L().Infof("rebase %s", data.GetKey())
+					pppList = append(pppList, data.CreatePushPullPack())
+				} else if b := data.GetRetryBackoff(); b > 0 {
+					retrying = append(retrying, data)
+					if b > backoff {
+						backoff = b
+					}
+				}
+			}
+		}
+		if len(retrying) > 0 {
+			its.ctx.L().Infof("retry push-pull of %d datatypes after %v", len(retrying), backoff)
+			select {
+			case <-time.After(backoff):
+			case <-its.ctx.Done():
+				return errors.ClientSync.New(its.ctx.L(), its.ctx.Err().Error())
+			}
+			for _, data := range retrying {
+				pppList = append(pppList, data.CreatePushPullPack())
+			}
+		}
+		if progressed {
+			stalled = 0
+		} else {
+			stalled++
 		}
 	}
 	return nil
 }
+
+// hasProgressed examines if the received PushPullPack gets the datatype further than the sent one, i.e.,
+// it acknowledges pushed operations, or pulls operations or a chunk of a snapshot.
+func hasProgressed(sent *model.PushPullPack, received *model.PushPullPack) bool {
+	if received.GetPushPullPackOption().HasErrorBit() {
+		return false
+	}
+	if sent == nil {
+		return true
+	}
+	pushedFrom := sent.GetCheckPoint().GetCseq() - uint64(len(sent.GetOperations()))
+	return received.GetCheckPoint().GetSseq() > sent.GetCheckPoint().GetSseq() ||
+		received.GetCheckPoint().GetCseq() > pushedFrom ||
+		len(received.GetSnapshotChunk().GetData()) > 0
+}
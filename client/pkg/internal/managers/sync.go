@@ -1,21 +1,29 @@
 package managers
 
 import (
+	"github.com/orda-io/orda/client/pkg/constants"
 	"github.com/orda-io/orda/client/pkg/context"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/operations"
+	"github.com/orda-io/orda/client/pkg/types"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
 )
 
 // SyncManager is a manager exchanging request and response with Orda server.
 type SyncManager struct {
 	seq           uint32
+	nonce         string // distinguishes the seqs of this SyncManager from those of another with the same CUID
 	ctx           *context.ClientContext
 	conn          *grpc.ClientConn
 	client        *model.Client
 	serverAddr    string
 	serviceClient model.OrdaServiceClient
 	notifyManager *NotifyManager
+	capabilities  model.Capability
 }
 
 // NewSyncManager creates an instance of SyncManager.
@@ -34,6 +42,7 @@ func NewSyncManager(
 	}
 	return &SyncManager{
 		seq:           0,
+		nonce:         types.NewUID(),
 		ctx:           ctx,
 		serverAddr:    serverAddr,
 		client:        client,
@@ -41,10 +50,10 @@ func NewSyncManager(
 	}
 }
 
+// nextSeq returns the seq of the next request, which begins with 1 because 0 means no seq.
 func (its *SyncManager) nextSeq() uint32 {
-	currentSeq := its.seq
 	its.seq++
-	return currentSeq
+	return its.seq
 }
 
 // Connect makes connections with Orda GRPC and notification servers.
@@ -75,11 +84,34 @@ func (its *SyncManager) Close() errors.OrdaError {
 	return nil
 }
 
-// Sync exchanges PUSHPULL_REQUEST and PUSHPULL_RESPONSE
+// Sync exchanges PUSHPULL_REQUEST and PUSHPULL_RESPONSE. When the server is temporarily unavailable, the same request
+// is retried if the server has CapabilityDedup, because it returns the same response for the retried request.
 func (its *SyncManager) Sync(pppList ...*model.PushPullPack) (*model.PushPullMessage, errors.OrdaError) {
+	if its.capabilities.Has(model.CapabilityBinaryEncoding) {
+		encodeOperationsInBinary(pppList)
+	}
 	request := model.NewPushPullMessage(its.nextSeq(), its.client, pppList...)
+	request.Header.Nonce = its.nonce
 	its.ctx.L().Infof("REQ[PUPU] %s", request.ToString(false))
 	response, err := its.serviceClient.ProcessPushPull(its.ctx, request)
+	// without the deduplication of the server, a retried request might push the same operations twice
+	for retry := 1; retry <= constants.MaxSyncRetries && its.capabilities.Has(model.CapabilityDedup) && isTransient(err); retry++ {
+		its.ctx.L().Warnf("retry push-pull seq:%d (%d/%d): %v", request.Header.Seq, retry, constants.MaxSyncRetries, err)
+		select {
+		case <-time.After(time.Duration(retry) * constants.SyncRetryBackoff):
+		case <-its.ctx.Done():
+			return nil, errors.ClientSync.New(its.ctx.L(), its.ctx.Err().Error())
+		}
+		response, err = its.serviceClient.ProcessPushPull(its.ctx, request)
+	}
+	if status.Code(err) == codes.NotFound {
+		// the client might have been removed from the server, for example, by resetting the collection
+		its.ctx.L().Warnf("register the client again: %v", err)
+		if cErr := its.ExchangeClientRequestResponse(); cErr != nil {
+			return nil, cErr
+		}
+		response, err = its.serviceClient.ProcessPushPull(its.ctx, request)
+	}
 	if err != nil {
 		return nil, errors.ClientSync.New(its.ctx.L(), err.Error())
 	}
@@ -87,6 +119,23 @@ func (its *SyncManager) Sync(pppList ...*model.PushPullPack) (*model.PushPullMes
 	return response, nil
 }
 
+// encodeOperationsInBinary replaces the operations of the PushPullPacks with the ones whose bodies are encoded
+// in binary; the local buffers of datatypes still keep the operations in JSON.
+func encodeOperationsInBinary(pppList []*model.PushPullPack) {
+	for _, ppp := range pppList {
+		opList := make([]*model.Operation, len(ppp.Operations))
+		for i, op := range ppp.Operations {
+			opList[i] = operations.EncodeBody(op, true)
+		}
+		ppp.Operations = opList
+	}
+}
+
+func isTransient(err error) bool {
+	code := status.Code(err)
+	return code == codes.Unavailable || code == codes.DeadlineExceeded
+}
+
 // ExchangeClientRequestResponse exchanges CLIENT_REQUEST and CLIENT_RESPONSE
 func (its *SyncManager) ExchangeClientRequestResponse() errors.OrdaError {
 	request := model.NewClientMessage(its.client)
@@ -95,10 +144,16 @@ func (its *SyncManager) ExchangeClientRequestResponse() errors.OrdaError {
 	if err != nil {
 		return errors.ClientSync.New(its.ctx.L(), err.Error())
 	}
+	its.capabilities = model.Capability(response.GetCapabilities())
 	its.ctx.L().Infof("RES[CLIE] response: %s", response.ToString())
 	return nil
 }
 
+// HasCapability examines if the features of the capability are negotiated with the server.
+func (its *SyncManager) HasCapability(c model.Capability) bool {
+	return its.capabilities.Has(c)
+}
+
 func (its *SyncManager) subscribeNotification(topic string) errors.OrdaError {
 	if its.notifyManager != nil {
 		return its.notifyManager.SubscribeNotification(topic)
@@ -0,0 +1,46 @@
+package model
+
+import "strings"
+
+// Capability is a set of the optional features of the protocol, which are negotiated between a client and a server.
+type Capability uint32
+
+// CapabilityXXX denotes an optional feature of the protocol.
+const (
+	// CapabilityDedup means that a retried PushPullMessage of the same seq gets the same response.
+	CapabilityDedup Capability = 1 << iota
+	// CapabilityPagination means that the operations are pulled across multiple PushPullPacks with MoreBit.
+	CapabilityPagination
+	// CapabilityRebase means that a datatype in an old era is rebased on a snapshot.
+	CapabilityRebase
+	// CapabilityStreaming means that push-pulls are exchanged through a stream.
+	CapabilityStreaming
+	// CapabilityBinaryEncoding means that the bodies of operations are encoded in binary.
+	CapabilityBinaryEncoding
+	// CapabilityChunkedSnapshot means that a big snapshot is pulled in chunks across multiple PushPullPacks.
+	CapabilityChunkedSnapshot
+	// CapabilityStability means that the tombstones are purged under the causal stability computed by the server.
+	CapabilityStability
+)
+
+// SupportedCapabilities are the capabilities which this SDK supports.
+const SupportedCapabilities = CapabilityDedup | CapabilityPagination | CapabilityRebase | CapabilityBinaryEncoding |
+	CapabilityChunkedSnapshot | CapabilityStability
+
+var capabilityNames = []string{"dedup", "pagination", "rebase", "streaming", "binary", "chunked", "stability"}
+
+// Has examines if it has all the specified capabilities.
+func (its Capability) Has(c Capability) bool {
+	return its&c == c
+}
+
+// String returns the names of the capabilities.
+func (its Capability) String() string {
+	var names []string
+	for i, name := range capabilityNames {
+		if its.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	return "[" + strings.Join(names, " ") + "]"
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapability(t *testing.T) {
+	c := CapabilityDedup | CapabilityRebase
+	require.True(t, c.Has(CapabilityDedup))
+	require.True(t, c.Has(CapabilityDedup|CapabilityRebase))
+	require.False(t, c.Has(CapabilityDedup|CapabilityStreaming))
+	require.Equal(t, "[dedup rebase]", c.String())
+	require.Equal(t, "[]", Capability(0).String())
+}
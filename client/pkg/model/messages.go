@@ -11,8 +11,10 @@ const (
 
 // NewPushPullMessage creates a new PushPullRequest
 func NewPushPullMessage(seq uint32, client *Client, pushPullPackList ...*PushPullPack) *PushPullMessage {
+	header := NewMessageHeader(RequestType_PUSHPULLS)
+	header.Seq = seq
 	return &PushPullMessage{
-		Header:        NewMessageHeader(RequestType_PUSHPULLS),
+		Header:        header,
 		Collection:    client.Collection,
 		Cuid:          client.CUID,
 		PushPullPacks: pushPullPackList,
@@ -44,11 +46,12 @@ func (its *PushPullMessage) GetClient() *Client {
 // NewClientMessage creates a new ClientRequest
 func NewClientMessage(client *Client) *ClientMessage {
 	return &ClientMessage{
-		Header:      NewMessageHeader(RequestType_CLIENTS),
-		Collection:  client.Collection,
-		Cuid:        client.CUID,
-		ClientAlias: client.Alias,
-		SyncType:    client.SyncType,
+		Header:       NewMessageHeader(RequestType_CLIENTS),
+		Collection:   client.Collection,
+		Cuid:         client.CUID,
+		ClientAlias:  client.Alias,
+		SyncType:     client.SyncType,
+		Capabilities: uint32(SupportedCapabilities),
 	}
 }
 
@@ -58,6 +61,8 @@ func (its *ClientMessage) ToString() string {
 	_, _ = fmt.Fprintf(&b, clientHeadFormat, its.Header.ToString(), its.Collection, its.Cuid)
 	b.WriteString(" SyncType:")
 	b.WriteString(its.SyncType.String())
+	b.WriteString(" Capabilities:")
+	b.WriteString(Capability(its.Capabilities).String())
 	return b.String()
 }
 
@@ -89,6 +89,85 @@ func (x *PatchMessage) GetJson() string {
 	return ""
 }
 
+type RestoreMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Collection string `protobuf:"bytes,1,opt,name=collection,proto3" json:"collection,omitempty"`
+	Key        string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Sseq       uint64 `protobuf:"varint,3,opt,name=sseq,proto3" json:"sseq,omitempty"`
+	Timestamp  int64  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Json       string `protobuf:"bytes,5,opt,name=json,proto3" json:"json,omitempty"`
+}
+
+func (x *RestoreMessage) Reset() {
+	*x = RestoreMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_orda_grpc_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreMessage) ProtoMessage() {}
+
+func (x *RestoreMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_orda_grpc_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreMessage.ProtoReflect.Descriptor instead.
+func (*RestoreMessage) Descriptor() ([]byte, []int) {
+	return file_orda_grpc_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RestoreMessage) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *RestoreMessage) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RestoreMessage) GetSseq() uint64 {
+	if x != nil {
+		return x.Sseq
+	}
+	return 0
+}
+
+func (x *RestoreMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *RestoreMessage) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
 type EncodingMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -101,7 +180,7 @@ type EncodingMessage struct {
 func (x *EncodingMessage) Reset() {
 	*x = EncodingMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_grpc_proto_msgTypes[1]
+		mi := &file_orda_grpc_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -114,7 +193,7 @@ func (x *EncodingMessage) String() string {
 func (*EncodingMessage) ProtoMessage() {}
 
 func (x *EncodingMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_grpc_proto_msgTypes[1]
+	mi := &file_orda_grpc_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -127,7 +206,7 @@ func (x *EncodingMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EncodingMessage.ProtoReflect.Descriptor instead.
 func (*EncodingMessage) Descriptor() ([]byte, []int) {
-	return file_orda_grpc_proto_rawDescGZIP(), []int{1}
+	return file_orda_grpc_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *EncodingMessage) GetType() TypeOfDatatype {
@@ -161,37 +240,54 @@ var file_orda_grpc_proto_rawDesc = []byte{
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f,
 	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
 	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6a, 0x73,
-	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x5c,
-	0x0a, 0x0f, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x28, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x14, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x4f, 0x66, 0x44, 0x61, 0x74,
-	0x61, 0x74, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x02, 0x6f,
-	0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x4f,
-	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x02, 0x6f, 0x70, 0x32, 0xcb, 0x05, 0x0a,
-	0x0b, 0x4f, 0x72, 0x64, 0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x7d, 0x0a, 0x0f,
-	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x12,
-	0x15, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x15, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x75,
-	0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3c, 0x82,
-	0xd3, 0xe4, 0x93, 0x02, 0x36, 0x22, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x63,
-	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x63, 0x6f, 0x6c, 0x6c,
-	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x2f, 0x70, 0x75, 0x73, 0x68, 0x70, 0x75, 0x6c, 0x6c,
-	0x73, 0x2f, 0x7b, 0x63, 0x75, 0x69, 0x64, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x75, 0x0a, 0x0d, 0x50,
-	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x13, 0x2e, 0x6f,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x88,
+	0x01, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x73, 0x65, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x04, 0x73, 0x73, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x5c, 0x0a, 0x0f, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x6f, 0x72, 0x64,
+	0x61, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x4f, 0x66, 0x44, 0x61, 0x74, 0x61, 0x74, 0x79, 0x70, 0x65,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x02, 0x6f, 0x70, 0x32, 0xd0, 0x06, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x61,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x7d, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x12, 0x15, 0x2e, 0x6f, 0x72, 0x64,
+	0x61, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x1a, 0x15, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c,
+	0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x36,
+	0x3a, 0x01, 0x2a, 0x22, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6c,
+	0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x2f, 0x70, 0x75, 0x73, 0x68, 0x70, 0x75, 0x6c, 0x6c, 0x73, 0x2f,
+	0x7b, 0x63, 0x75, 0x69, 0x64, 0x7d, 0x12, 0x75, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x13, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x13, 0x2e, 0x6f,
 	0x72, 0x64, 0x61, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x1a, 0x13, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x34, 0x22, 0x2f,
+	0x65, 0x22, 0x3a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x34, 0x22, 0x2f, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x2f, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x2f, 0x7b, 0x63, 0x75, 0x69, 0x64, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x74, 0x0a,
+	0x0d, 0x50, 0x61, 0x74, 0x63, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12,
+	0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x1a, 0x12, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x35, 0x22, 0x30,
 	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69,
 	0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x7d,
-	0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x2f, 0x7b, 0x63, 0x75, 0x69, 0x64, 0x7d, 0x3a,
-	0x01, 0x2a, 0x12, 0x74, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x63, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d,
-	0x65, 0x6e, 0x74, 0x12, 0x12, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x12, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50,
-	0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3b, 0x82, 0xd3, 0xe4,
-	0x93, 0x02, 0x35, 0x22, 0x30, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6c,
-	0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x2f,
-	0x7b, 0x6b, 0x65, 0x79, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x6e, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61,
+	0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x2f, 0x7b, 0x6b, 0x65, 0x79, 0x7d,
+	0x3a, 0x01, 0x2a, 0x12, 0x82, 0x01, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x52,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x14, 0x2e,
+	0x6f, 0x72, 0x64, 0x61, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x43, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x3d, 0x3a, 0x01, 0x2a, 0x22, 0x38,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2f, 0x7b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x7d,
+	0x2f, 0x64, 0x61, 0x74, 0x61, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x7b, 0x6b, 0x65, 0x79, 0x7d,
+	0x2f, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x6e, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61,
 	0x74, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x2e, 0x6f,
 	0x72, 0x64, 0x61, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65,
 	0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x17, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x43, 0x6f, 0x6c,
@@ -231,33 +327,36 @@ func file_orda_grpc_proto_rawDescGZIP() []byte {
 	return file_orda_grpc_proto_rawDescData
 }
 
-var file_orda_grpc_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_orda_grpc_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_orda_grpc_proto_goTypes = []interface{}{
 	(*PatchMessage)(nil),      // 0: orda.PatchMessage
-	(*EncodingMessage)(nil),   // 1: orda.EncodingMessage
-	(TypeOfDatatype)(0),       // 2: orda.TypeOfDatatype
-	(*Operation)(nil),         // 3: orda.Operation
-	(*PushPullMessage)(nil),   // 4: orda.PushPullMessage
-	(*ClientMessage)(nil),     // 5: orda.ClientMessage
-	(*CollectionMessage)(nil), // 6: orda.CollectionMessage
+	(*RestoreMessage)(nil),    // 1: orda.RestoreMessage
+	(*EncodingMessage)(nil),   // 2: orda.EncodingMessage
+	(TypeOfDatatype)(0),       // 3: orda.TypeOfDatatype
+	(*Operation)(nil),         // 4: orda.Operation
+	(*PushPullMessage)(nil),   // 5: orda.PushPullMessage
+	(*ClientMessage)(nil),     // 6: orda.ClientMessage
+	(*CollectionMessage)(nil), // 7: orda.CollectionMessage
 }
 var file_orda_grpc_proto_depIdxs = []int32{
-	2, // 0: orda.EncodingMessage.type:type_name -> orda.TypeOfDatatype
-	3, // 1: orda.EncodingMessage.op:type_name -> orda.Operation
-	4, // 2: orda.OrdaService.ProcessPushPull:input_type -> orda.PushPullMessage
-	5, // 3: orda.OrdaService.ProcessClient:input_type -> orda.ClientMessage
+	3, // 0: orda.EncodingMessage.type:type_name -> orda.TypeOfDatatype
+	4, // 1: orda.EncodingMessage.op:type_name -> orda.Operation
+	5, // 2: orda.OrdaService.ProcessPushPull:input_type -> orda.PushPullMessage
+	6, // 3: orda.OrdaService.ProcessClient:input_type -> orda.ClientMessage
 	0, // 4: orda.OrdaService.PatchDocument:input_type -> orda.PatchMessage
-	6, // 5: orda.OrdaService.CreateCollection:input_type -> orda.CollectionMessage
-	6, // 6: orda.OrdaService.ResetCollection:input_type -> orda.CollectionMessage
-	1, // 7: orda.OrdaService.TestEncodingOperation:input_type -> orda.EncodingMessage
-	4, // 8: orda.OrdaService.ProcessPushPull:output_type -> orda.PushPullMessage
-	5, // 9: orda.OrdaService.ProcessClient:output_type -> orda.ClientMessage
-	0, // 10: orda.OrdaService.PatchDocument:output_type -> orda.PatchMessage
-	6, // 11: orda.OrdaService.CreateCollection:output_type -> orda.CollectionMessage
-	6, // 12: orda.OrdaService.ResetCollection:output_type -> orda.CollectionMessage
-	1, // 13: orda.OrdaService.TestEncodingOperation:output_type -> orda.EncodingMessage
-	8, // [8:14] is the sub-list for method output_type
-	2, // [2:8] is the sub-list for method input_type
+	1, // 5: orda.OrdaService.RestoreDatatype:input_type -> orda.RestoreMessage
+	7, // 6: orda.OrdaService.CreateCollection:input_type -> orda.CollectionMessage
+	7, // 7: orda.OrdaService.ResetCollection:input_type -> orda.CollectionMessage
+	2, // 8: orda.OrdaService.TestEncodingOperation:input_type -> orda.EncodingMessage
+	5, // 9: orda.OrdaService.ProcessPushPull:output_type -> orda.PushPullMessage
+	6, // 10: orda.OrdaService.ProcessClient:output_type -> orda.ClientMessage
+	0, // 11: orda.OrdaService.PatchDocument:output_type -> orda.PatchMessage
+	1, // 12: orda.OrdaService.RestoreDatatype:output_type -> orda.RestoreMessage
+	7, // 13: orda.OrdaService.CreateCollection:output_type -> orda.CollectionMessage
+	7, // 14: orda.OrdaService.ResetCollection:output_type -> orda.CollectionMessage
+	2, // 15: orda.OrdaService.TestEncodingOperation:output_type -> orda.EncodingMessage
+	9, // [9:16] is the sub-list for method output_type
+	2, // [2:9] is the sub-list for method input_type
 	2, // [2:2] is the sub-list for extension type_name
 	2, // [2:2] is the sub-list for extension extendee
 	0, // [0:2] is the sub-list for field type_name
@@ -284,6 +383,18 @@ func file_orda_grpc_proto_init() {
 			}
 		}
 		file_orda_grpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_orda_grpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*EncodingMessage); i {
 			case 0:
 				return &v.state
@@ -302,7 +413,7 @@ func file_orda_grpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_orda_grpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -331,6 +442,7 @@ type OrdaServiceClient interface {
 	ProcessPushPull(ctx context.Context, in *PushPullMessage, opts ...grpc.CallOption) (*PushPullMessage, error)
 	ProcessClient(ctx context.Context, in *ClientMessage, opts ...grpc.CallOption) (*ClientMessage, error)
 	PatchDocument(ctx context.Context, in *PatchMessage, opts ...grpc.CallOption) (*PatchMessage, error)
+	RestoreDatatype(ctx context.Context, in *RestoreMessage, opts ...grpc.CallOption) (*RestoreMessage, error)
 	CreateCollection(ctx context.Context, in *CollectionMessage, opts ...grpc.CallOption) (*CollectionMessage, error)
 	ResetCollection(ctx context.Context, in *CollectionMessage, opts ...grpc.CallOption) (*CollectionMessage, error)
 	TestEncodingOperation(ctx context.Context, in *EncodingMessage, opts ...grpc.CallOption) (*EncodingMessage, error)
@@ -371,6 +483,15 @@ func (c *ordaServiceClient) PatchDocument(ctx context.Context, in *PatchMessage,
 	return out, nil
 }
 
+func (c *ordaServiceClient) RestoreDatatype(ctx context.Context, in *RestoreMessage, opts ...grpc.CallOption) (*RestoreMessage, error) {
+	out := new(RestoreMessage)
+	err := c.cc.Invoke(ctx, "/orda.OrdaService/RestoreDatatype", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ordaServiceClient) CreateCollection(ctx context.Context, in *CollectionMessage, opts ...grpc.CallOption) (*CollectionMessage, error) {
 	out := new(CollectionMessage)
 	err := c.cc.Invoke(ctx, "/orda.OrdaService/CreateCollection", in, out, opts...)
@@ -403,6 +524,7 @@ type OrdaServiceServer interface {
 	ProcessPushPull(context.Context, *PushPullMessage) (*PushPullMessage, error)
 	ProcessClient(context.Context, *ClientMessage) (*ClientMessage, error)
 	PatchDocument(context.Context, *PatchMessage) (*PatchMessage, error)
+	RestoreDatatype(context.Context, *RestoreMessage) (*RestoreMessage, error)
 	CreateCollection(context.Context, *CollectionMessage) (*CollectionMessage, error)
 	ResetCollection(context.Context, *CollectionMessage) (*CollectionMessage, error)
 	TestEncodingOperation(context.Context, *EncodingMessage) (*EncodingMessage, error)
@@ -421,6 +543,9 @@ func (*UnimplementedOrdaServiceServer) ProcessClient(context.Context, *ClientMes
 func (*UnimplementedOrdaServiceServer) PatchDocument(context.Context, *PatchMessage) (*PatchMessage, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PatchDocument not implemented")
 }
+func (*UnimplementedOrdaServiceServer) RestoreDatatype(context.Context, *RestoreMessage) (*RestoreMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreDatatype not implemented")
+}
 func (*UnimplementedOrdaServiceServer) CreateCollection(context.Context, *CollectionMessage) (*CollectionMessage, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateCollection not implemented")
 }
@@ -489,6 +614,24 @@ func _OrdaService_PatchDocument_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrdaService_RestoreDatatype_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdaServiceServer).RestoreDatatype(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orda.OrdaService/RestoreDatatype",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdaServiceServer).RestoreDatatype(ctx, req.(*RestoreMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrdaService_CreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CollectionMessage)
 	if err := dec(in); err != nil {
@@ -559,6 +702,10 @@ var _OrdaService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PatchDocument",
 			Handler:    _OrdaService_PatchDocument_Handler,
 		},
+		{
+			MethodName: "RestoreDatatype",
+			Handler:    _OrdaService_RestoreDatatype_Handler,
+		},
 		{
 			MethodName: "CreateCollection",
 			Handler:    _OrdaService_CreateCollection_Handler,
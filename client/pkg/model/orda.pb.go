@@ -317,13 +317,21 @@ type PushPullPack struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DUID       string         `protobuf:"bytes,1,opt,name=DUID,proto3" json:"DUID,omitempty"`
-	Key        string         `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Option     uint32         `protobuf:"fixed32,3,opt,name=option,proto3" json:"option,omitempty"`
-	CheckPoint *CheckPoint    `protobuf:"bytes,4,opt,name=checkPoint,proto3" json:"checkPoint,omitempty"`
-	Era        uint32         `protobuf:"varint,5,opt,name=era,proto3" json:"era,omitempty"`
-	Type       TypeOfDatatype `protobuf:"varint,6,opt,name=type,proto3,enum=orda.TypeOfDatatype" json:"type,omitempty"`
-	Operations []*Operation   `protobuf:"bytes,7,rep,name=operations,proto3" json:"operations,omitempty"`
+	DUID          string         `protobuf:"bytes,1,opt,name=DUID,proto3" json:"DUID,omitempty"`
+	Key           string         `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Option        uint32         `protobuf:"fixed32,3,opt,name=option,proto3" json:"option,omitempty"`
+	CheckPoint    *CheckPoint    `protobuf:"bytes,4,opt,name=checkPoint,proto3" json:"checkPoint,omitempty"`
+	Era           uint32         `protobuf:"varint,5,opt,name=era,proto3" json:"era,omitempty"`
+	Type          TypeOfDatatype `protobuf:"varint,6,opt,name=type,proto3,enum=orda.TypeOfDatatype" json:"type,omitempty"`
+	Operations    []*Operation   `protobuf:"bytes,7,rep,name=operations,proto3" json:"operations,omitempty"`
+	SnapshotChunk *SnapshotChunk `protobuf:"bytes,8,opt,name=snapshotChunk,proto3" json:"snapshotChunk,omitempty"`
+	Path          string         `protobuf:"bytes,9,opt,name=path,proto3" json:"path,omitempty"`
+	// the sseq which the client had seen when it made the oldest operation not acknowledged yet
+	SeenSseq uint64 `protobuf:"varint,10,opt,name=seenSseq,proto3" json:"seenSseq,omitempty"`
+	// the timestamp of the oldest operation not acknowledged yet, or of the next operation of the client
+	Lowest *Timestamp `protobuf:"bytes,11,opt,name=lowest,proto3" json:"lowest,omitempty"`
+	// the causal stability; every operation to be pulled later has a greater Lamport
+	Stable *Timestamp `protobuf:"bytes,12,opt,name=stable,proto3" json:"stable,omitempty"`
 }
 
 func (x *PushPullPack) Reset() {
@@ -407,6 +415,112 @@ func (x *PushPullPack) GetOperations() []*Operation {
 	return nil
 }
 
+func (x *PushPullPack) GetSnapshotChunk() *SnapshotChunk {
+	if x != nil {
+		return x.SnapshotChunk
+	}
+	return nil
+}
+
+func (x *PushPullPack) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PushPullPack) GetSeenSseq() uint64 {
+	if x != nil {
+		return x.SeenSseq
+	}
+	return 0
+}
+
+func (x *PushPullPack) GetLowest() *Timestamp {
+	if x != nil {
+		return x.Lowest
+	}
+	return nil
+}
+
+func (x *PushPullPack) GetStable() *Timestamp {
+	if x != nil {
+		return x.Stable
+	}
+	return nil
+}
+
+type SnapshotChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sseq   uint64 `protobuf:"varint,1,opt,name=sseq,proto3" json:"sseq,omitempty"`
+	Offset uint64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Total  uint64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Data   []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *SnapshotChunk) Reset() {
+	*x = SnapshotChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_orda_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotChunk) ProtoMessage() {}
+
+func (x *SnapshotChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_orda_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotChunk.ProtoReflect.Descriptor instead.
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return file_orda_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SnapshotChunk) GetSseq() uint64 {
+	if x != nil {
+		return x.Sseq
+	}
+	return 0
+}
+
+func (x *SnapshotChunk) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SnapshotChunk) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SnapshotChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 type CheckPoint struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -421,7 +535,7 @@ type CheckPoint struct {
 func (x *CheckPoint) Reset() {
 	*x = CheckPoint{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_proto_msgTypes[5]
+		mi := &file_orda_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -434,7 +548,7 @@ func (x *CheckPoint) String() string {
 func (*CheckPoint) ProtoMessage() {}
 
 func (x *CheckPoint) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_proto_msgTypes[5]
+	mi := &file_orda_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -447,7 +561,7 @@ func (x *CheckPoint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckPoint.ProtoReflect.Descriptor instead.
 func (*CheckPoint) Descriptor() ([]byte, []int) {
-	return file_orda_proto_rawDescGZIP(), []int{5}
+	return file_orda_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CheckPoint) GetSseq() uint64 {
@@ -477,7 +591,7 @@ type Notification struct {
 func (x *Notification) Reset() {
 	*x = Notification{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_proto_msgTypes[6]
+		mi := &file_orda_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -490,7 +604,7 @@ func (x *Notification) String() string {
 func (*Notification) ProtoMessage() {}
 
 func (x *Notification) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_proto_msgTypes[6]
+	mi := &file_orda_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -503,7 +617,7 @@ func (x *Notification) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Notification.ProtoReflect.Descriptor instead.
 func (*Notification) Descriptor() ([]byte, []int) {
-	return file_orda_proto_rawDescGZIP(), []int{6}
+	return file_orda_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Notification) GetCUID() string {
@@ -541,7 +655,7 @@ type DatatypeMeta struct {
 func (x *DatatypeMeta) Reset() {
 	*x = DatatypeMeta{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_proto_msgTypes[7]
+		mi := &file_orda_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -554,7 +668,7 @@ func (x *DatatypeMeta) String() string {
 func (*DatatypeMeta) ProtoMessage() {}
 
 func (x *DatatypeMeta) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_proto_msgTypes[7]
+	mi := &file_orda_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -567,7 +681,7 @@ func (x *DatatypeMeta) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatatypeMeta.ProtoReflect.Descriptor instead.
 func (*DatatypeMeta) Descriptor() ([]byte, []int) {
-	return file_orda_proto_rawDescGZIP(), []int{7}
+	return file_orda_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DatatypeMeta) GetKey() string {
@@ -606,12 +720,14 @@ type Header struct {
 	Version string      `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
 	Agent   string      `protobuf:"bytes,2,opt,name=agent,proto3" json:"agent,omitempty"`
 	Type    RequestType `protobuf:"varint,3,opt,name=type,proto3,enum=orda.RequestType" json:"type,omitempty"`
+	Seq     uint32      `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	Nonce   string      `protobuf:"bytes,5,opt,name=nonce,proto3" json:"nonce,omitempty"`
 }
 
 func (x *Header) Reset() {
 	*x = Header{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_proto_msgTypes[8]
+		mi := &file_orda_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -624,7 +740,7 @@ func (x *Header) String() string {
 func (*Header) ProtoMessage() {}
 
 func (x *Header) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_proto_msgTypes[8]
+	mi := &file_orda_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -637,7 +753,7 @@ func (x *Header) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Header.ProtoReflect.Descriptor instead.
 func (*Header) Descriptor() ([]byte, []int) {
-	return file_orda_proto_rawDescGZIP(), []int{8}
+	return file_orda_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Header) GetVersion() string {
@@ -661,23 +777,38 @@ func (x *Header) GetType() RequestType {
 	return RequestType_CLIENTS
 }
 
+func (x *Header) GetSeq() uint32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Header) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
 type ClientMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Header      *Header    `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	Collection  string     `protobuf:"bytes,2,opt,name=collection,proto3" json:"collection,omitempty"`
-	Cuid        string     `protobuf:"bytes,3,opt,name=cuid,proto3" json:"cuid,omitempty"`
-	ClientAlias string     `protobuf:"bytes,4,opt,name=clientAlias,proto3" json:"clientAlias,omitempty"`
-	ClientType  ClientType `protobuf:"varint,5,opt,name=clientType,proto3,enum=orda.ClientType" json:"clientType,omitempty"`
-	SyncType    SyncType   `protobuf:"varint,6,opt,name=syncType,proto3,enum=orda.SyncType" json:"syncType,omitempty"`
+	Header       *Header    `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Collection   string     `protobuf:"bytes,2,opt,name=collection,proto3" json:"collection,omitempty"`
+	Cuid         string     `protobuf:"bytes,3,opt,name=cuid,proto3" json:"cuid,omitempty"`
+	ClientAlias  string     `protobuf:"bytes,4,opt,name=clientAlias,proto3" json:"clientAlias,omitempty"`
+	ClientType   ClientType `protobuf:"varint,5,opt,name=clientType,proto3,enum=orda.ClientType" json:"clientType,omitempty"`
+	SyncType     SyncType   `protobuf:"varint,6,opt,name=syncType,proto3,enum=orda.SyncType" json:"syncType,omitempty"`
+	Capabilities uint32     `protobuf:"varint,7,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (x *ClientMessage) Reset() {
 	*x = ClientMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_proto_msgTypes[9]
+		mi := &file_orda_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -690,7 +821,7 @@ func (x *ClientMessage) String() string {
 func (*ClientMessage) ProtoMessage() {}
 
 func (x *ClientMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_proto_msgTypes[9]
+	mi := &file_orda_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -703,7 +834,7 @@ func (x *ClientMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClientMessage.ProtoReflect.Descriptor instead.
 func (*ClientMessage) Descriptor() ([]byte, []int) {
-	return file_orda_proto_rawDescGZIP(), []int{9}
+	return file_orda_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ClientMessage) GetHeader() *Header {
@@ -748,6 +879,13 @@ func (x *ClientMessage) GetSyncType() SyncType {
 	return SyncType_LOCAL_ONLY
 }
 
+func (x *ClientMessage) GetCapabilities() uint32 {
+	if x != nil {
+		return x.Capabilities
+	}
+	return 0
+}
+
 type PushPullMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -762,7 +900,7 @@ type PushPullMessage struct {
 func (x *PushPullMessage) Reset() {
 	*x = PushPullMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_proto_msgTypes[10]
+		mi := &file_orda_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -775,7 +913,7 @@ func (x *PushPullMessage) String() string {
 func (*PushPullMessage) ProtoMessage() {}
 
 func (x *PushPullMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_proto_msgTypes[10]
+	mi := &file_orda_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -788,7 +926,7 @@ func (x *PushPullMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PushPullMessage.ProtoReflect.Descriptor instead.
 func (*PushPullMessage) Descriptor() ([]byte, []int) {
-	return file_orda_proto_rawDescGZIP(), []int{10}
+	return file_orda_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *PushPullMessage) GetHeader() *Header {
@@ -830,7 +968,7 @@ type CollectionMessage struct {
 func (x *CollectionMessage) Reset() {
 	*x = CollectionMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_orda_proto_msgTypes[11]
+		mi := &file_orda_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -843,7 +981,7 @@ func (x *CollectionMessage) String() string {
 func (*CollectionMessage) ProtoMessage() {}
 
 func (x *CollectionMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_orda_proto_msgTypes[11]
+	mi := &file_orda_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -856,7 +994,7 @@ func (x *CollectionMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CollectionMessage.ProtoReflect.Descriptor instead.
 func (*CollectionMessage) Descriptor() ([]byte, []int) {
-	return file_orda_proto_rawDescGZIP(), []int{11}
+	return file_orda_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *CollectionMessage) GetCollection() string {
@@ -902,7 +1040,7 @@ var file_orda_proto_rawDesc = []byte{
 	0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x4f, 0x66,
 	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6f, 0x70, 0x54, 0x79, 0x70,
 	0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0xeb, 0x01, 0x0a, 0x0c, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75,
+	0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0xac, 0x03, 0x0a, 0x0c, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75,
 	0x6c, 0x6c, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x55, 0x49, 0x44, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x44, 0x55, 0x49, 0x44, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
 	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06,
@@ -917,61 +1055,85 @@ var file_orda_proto_rawDesc = []byte{
 	0x70, 0x65, 0x12, 0x2f, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
 	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x4f, 0x70,
 	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x22, 0x3c, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x6f, 0x69, 0x6e,
-	0x74, 0x12, 0x16, 0x0a, 0x04, 0x73, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42,
-	0x02, 0x30, 0x01, 0x52, 0x04, 0x73, 0x73, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x04, 0x63, 0x73, 0x65,
-	0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x04, 0x63, 0x73, 0x65,
-	0x71, 0x22, 0x4e, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x55, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x43, 0x55, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x55, 0x49, 0x44, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x44, 0x55, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x04, 0x73, 0x73, 0x65,
-	0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x04, 0x73, 0x73, 0x65,
-	0x71, 0x22, 0x89, 0x01, 0x0a, 0x0c, 0x44, 0x61, 0x74, 0x61, 0x74, 0x79, 0x70, 0x65, 0x4d, 0x65,
-	0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x55, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x44, 0x55, 0x49, 0x44, 0x12, 0x25, 0x0a, 0x04, 0x6f, 0x70, 0x49, 0x44,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x4f, 0x70,
-	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x52, 0x04, 0x6f, 0x70, 0x49, 0x44, 0x12,
-	0x2c, 0x0a, 0x06, 0x74, 0x79, 0x70, 0x65, 0x4f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x14, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x4f, 0x66, 0x44, 0x61, 0x74,
-	0x61, 0x74, 0x79, 0x70, 0x65, 0x52, 0x06, 0x74, 0x79, 0x70, 0x65, 0x4f, 0x66, 0x22, 0x5f, 0x0a,
-	0x06, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0xe9,
-	0x01, 0x0a, 0x0d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x12, 0x24, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0c, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x06,
-	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6c, 0x6c,
-	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x75, 0x69, 0x64, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x75, 0x69, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c,
-	0x69, 0x65, 0x6e, 0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x30, 0x0a, 0x0a,
-	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x10, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2a,
-	0x0a, 0x08, 0x73, 0x79, 0x6e, 0x63, 0x54, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x0e, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x08, 0x73, 0x79, 0x6e, 0x63, 0x54, 0x79, 0x70, 0x65, 0x22, 0xa5, 0x01, 0x0a, 0x0f, 0x50,
-	0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x24,
-	0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
-	0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x06, 0x68, 0x65,
-	0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x63, 0x75, 0x69, 0x64, 0x12, 0x38, 0x0a, 0x0d, 0x50, 0x75, 0x73, 0x68,
-	0x50, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x12, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x50,
-	0x61, 0x63, 0x6b, 0x52, 0x0d, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x63,
-	0x6b, 0x73, 0x22, 0x33, 0x0a, 0x11, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6c,
-	0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x12, 0x5a, 0x10, 0x63, 0x6c, 0x69, 0x65, 0x6e,
-	0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x6f, 0x6e, 0x73, 0x12, 0x39, 0x0a, 0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6f, 0x72, 0x64,
+	0x61, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52,
+	0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x1e, 0x0a, 0x08, 0x73, 0x65, 0x65, 0x6e, 0x53, 0x73, 0x65, 0x71, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x08, 0x73, 0x65, 0x65, 0x6e, 0x53, 0x73,
+	0x65, 0x71, 0x12, 0x27, 0x0a, 0x06, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x06, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6f, 0x72,
+	0x64, 0x61, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x22, 0x71, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x16, 0x0a, 0x04, 0x73, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x04, 0x73, 0x73, 0x65, 0x71, 0x12, 0x1a, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30,
+	0x01, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x05, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x3c, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x04, 0x73, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x04, 0x73, 0x73, 0x65, 0x71, 0x12, 0x16, 0x0a,
+	0x04, 0x63, 0x73, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52,
+	0x04, 0x63, 0x73, 0x65, 0x71, 0x22, 0x4e, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x55, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x43, 0x55, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x55, 0x49,
+	0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x44, 0x55, 0x49, 0x44, 0x12, 0x16, 0x0a,
+	0x04, 0x73, 0x73, 0x65, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52,
+	0x04, 0x73, 0x73, 0x65, 0x71, 0x22, 0x89, 0x01, 0x0a, 0x0c, 0x44, 0x61, 0x74, 0x61, 0x74, 0x79,
+	0x70, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x55, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x44, 0x55, 0x49, 0x44, 0x12, 0x25, 0x0a, 0x04,
+	0x6f, 0x70, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6f, 0x72, 0x64,
+	0x61, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x52, 0x04, 0x6f,
+	0x70, 0x49, 0x44, 0x12, 0x2c, 0x0a, 0x06, 0x74, 0x79, 0x70, 0x65, 0x4f, 0x66, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x4f,
+	0x66, 0x44, 0x61, 0x74, 0x61, 0x74, 0x79, 0x70, 0x65, 0x52, 0x06, 0x74, 0x79, 0x70, 0x65, 0x4f,
+	0x66, 0x22, 0x87, 0x01, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6f, 0x72, 0x64,
+	0x61, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x8d, 0x02, 0x0a, 0x0d,
+	0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x24, 0x0a,
+	0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x6f, 0x72, 0x64, 0x61, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x06, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x63, 0x75, 0x69, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e,
+	0x6f, 0x72, 0x64, 0x61, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2a, 0x0a, 0x08, 0x73,
+	0x79, 0x6e, 0x63, 0x54, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e,
+	0x6f, 0x72, 0x64, 0x61, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x73,
+	0x79, 0x6e, 0x63, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x63,
+	0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xa5, 0x01, 0x0a, 0x0f,
+	0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x24, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x06, 0x68,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x75, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x75, 0x69, 0x64, 0x12, 0x38, 0x0a, 0x0d, 0x50, 0x75, 0x73,
+	0x68, 0x50, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x6f, 0x72, 0x64, 0x61, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c,
+	0x50, 0x61, 0x63, 0x6b, 0x52, 0x0d, 0x50, 0x75, 0x73, 0x68, 0x50, 0x75, 0x6c, 0x6c, 0x50, 0x61,
+	0x63, 0x6b, 0x73, 0x22, 0x33, 0x0a, 0x11, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x12, 0x5a, 0x10, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -986,47 +1148,51 @@ func file_orda_proto_rawDescGZIP() []byte {
 	return file_orda_proto_rawDescData
 }
 
-var file_orda_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_orda_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_orda_proto_goTypes = []interface{}{
 	(*Client)(nil),            // 0: orda.Client
 	(*Timestamp)(nil),         // 1: orda.Timestamp
 	(*OperationID)(nil),       // 2: orda.OperationID
 	(*Operation)(nil),         // 3: orda.Operation
 	(*PushPullPack)(nil),      // 4: orda.PushPullPack
-	(*CheckPoint)(nil),        // 5: orda.CheckPoint
-	(*Notification)(nil),      // 6: orda.Notification
-	(*DatatypeMeta)(nil),      // 7: orda.DatatypeMeta
-	(*Header)(nil),            // 8: orda.Header
-	(*ClientMessage)(nil),     // 9: orda.ClientMessage
-	(*PushPullMessage)(nil),   // 10: orda.PushPullMessage
-	(*CollectionMessage)(nil), // 11: orda.CollectionMessage
-	(ClientType)(0),           // 12: orda.ClientType
-	(SyncType)(0),             // 13: orda.SyncType
-	(TypeOfOperation)(0),      // 14: orda.TypeOfOperation
-	(TypeOfDatatype)(0),       // 15: orda.TypeOfDatatype
-	(RequestType)(0),          // 16: orda.RequestType
+	(*SnapshotChunk)(nil),     // 5: orda.SnapshotChunk
+	(*CheckPoint)(nil),        // 6: orda.CheckPoint
+	(*Notification)(nil),      // 7: orda.Notification
+	(*DatatypeMeta)(nil),      // 8: orda.DatatypeMeta
+	(*Header)(nil),            // 9: orda.Header
+	(*ClientMessage)(nil),     // 10: orda.ClientMessage
+	(*PushPullMessage)(nil),   // 11: orda.PushPullMessage
+	(*CollectionMessage)(nil), // 12: orda.CollectionMessage
+	(ClientType)(0),           // 13: orda.ClientType
+	(SyncType)(0),             // 14: orda.SyncType
+	(TypeOfOperation)(0),      // 15: orda.TypeOfOperation
+	(TypeOfDatatype)(0),       // 16: orda.TypeOfDatatype
+	(RequestType)(0),          // 17: orda.RequestType
 }
 var file_orda_proto_depIdxs = []int32{
-	12, // 0: orda.Client.type:type_name -> orda.ClientType
-	13, // 1: orda.Client.syncType:type_name -> orda.SyncType
+	13, // 0: orda.Client.type:type_name -> orda.ClientType
+	14, // 1: orda.Client.syncType:type_name -> orda.SyncType
 	2,  // 2: orda.Operation.ID:type_name -> orda.OperationID
-	14, // 3: orda.Operation.opType:type_name -> orda.TypeOfOperation
-	5,  // 4: orda.PushPullPack.checkPoint:type_name -> orda.CheckPoint
-	15, // 5: orda.PushPullPack.type:type_name -> orda.TypeOfDatatype
+	15, // 3: orda.Operation.opType:type_name -> orda.TypeOfOperation
+	6,  // 4: orda.PushPullPack.checkPoint:type_name -> orda.CheckPoint
+	16, // 5: orda.PushPullPack.type:type_name -> orda.TypeOfDatatype
 	3,  // 6: orda.PushPullPack.operations:type_name -> orda.Operation
-	2,  // 7: orda.DatatypeMeta.opID:type_name -> orda.OperationID
-	15, // 8: orda.DatatypeMeta.typeOf:type_name -> orda.TypeOfDatatype
-	16, // 9: orda.Header.type:type_name -> orda.RequestType
-	8,  // 10: orda.ClientMessage.header:type_name -> orda.Header
-	12, // 11: orda.ClientMessage.clientType:type_name -> orda.ClientType
-	13, // 12: orda.ClientMessage.syncType:type_name -> orda.SyncType
-	8,  // 13: orda.PushPullMessage.header:type_name -> orda.Header
-	4,  // 14: orda.PushPullMessage.PushPullPacks:type_name -> orda.PushPullPack
-	15, // [15:15] is the sub-list for method output_type
-	15, // [15:15] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	5,  // 7: orda.PushPullPack.snapshotChunk:type_name -> orda.SnapshotChunk
+	1,  // 8: orda.PushPullPack.lowest:type_name -> orda.Timestamp
+	1,  // 9: orda.PushPullPack.stable:type_name -> orda.Timestamp
+	2,  // 10: orda.DatatypeMeta.opID:type_name -> orda.OperationID
+	16, // 11: orda.DatatypeMeta.typeOf:type_name -> orda.TypeOfDatatype
+	17, // 12: orda.Header.type:type_name -> orda.RequestType
+	9,  // 13: orda.ClientMessage.header:type_name -> orda.Header
+	13, // 14: orda.ClientMessage.clientType:type_name -> orda.ClientType
+	14, // 15: orda.ClientMessage.syncType:type_name -> orda.SyncType
+	9,  // 16: orda.PushPullMessage.header:type_name -> orda.Header
+	4,  // 17: orda.PushPullMessage.PushPullPacks:type_name -> orda.PushPullPack
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_orda_proto_init() }
@@ -1097,7 +1263,7 @@ func file_orda_proto_init() {
 			}
 		}
 		file_orda_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CheckPoint); i {
+			switch v := v.(*SnapshotChunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1109,7 +1275,7 @@ func file_orda_proto_init() {
 			}
 		}
 		file_orda_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Notification); i {
+			switch v := v.(*CheckPoint); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1121,7 +1287,7 @@ func file_orda_proto_init() {
 			}
 		}
 		file_orda_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DatatypeMeta); i {
+			switch v := v.(*Notification); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1133,7 +1299,7 @@ func file_orda_proto_init() {
 			}
 		}
 		file_orda_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Header); i {
+			switch v := v.(*DatatypeMeta); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1145,7 +1311,7 @@ func file_orda_proto_init() {
 			}
 		}
 		file_orda_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ClientMessage); i {
+			switch v := v.(*Header); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1157,7 +1323,7 @@ func file_orda_proto_init() {
 			}
 		}
 		file_orda_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PushPullMessage); i {
+			switch v := v.(*ClientMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1169,6 +1335,18 @@ func file_orda_proto_init() {
 			}
 		}
 		file_orda_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushPullMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_orda_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CollectionMessage); i {
 			case 0:
 				return &v.state
@@ -1187,7 +1365,7 @@ func file_orda_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_orda_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -16,9 +16,10 @@ const (
 	PushPullBitSnapshot    PushPullPackOption = 0x10
 	PushPullBitError       PushPullPackOption = 0x20
 	PushPullBitReadOnly    PushPullPackOption = 0x40
+	PushPullBitMore        PushPullPackOption = 0x80
 )
 
-var pushPullBitString = []string{"cr", "sb", "un", "de", "sn", "er", "ro"}
+var pushPullBitString = []string{"cr", "sb", "un", "de", "sn", "er", "ro", "mo"}
 
 // PushPullPackOption denotes an option implied in a PushPullPack.
 type PushPullPackOption uint32
@@ -81,6 +82,12 @@ func (its *PushPullPackOption) SetReadOnlyBit() *PushPullPackOption {
 	return its
 }
 
+// SetMoreBit sets MoreBit, which means that more operations are available to pull.
+func (its *PushPullPackOption) SetMoreBit() *PushPullPackOption {
+	*its |= PushPullBitMore
+	return its
+}
+
 // HasCreateBit examines CreateBit.
 func (its *PushPullPackOption) HasCreateBit() bool {
 	return (*its & PushPullBitCreate) == PushPullBitCreate
@@ -116,6 +123,11 @@ func (its *PushPullPackOption) HasReadOnly() bool {
 	return (*its & PushPullBitReadOnly) == PushPullBitReadOnly
 }
 
+// HasMoreBit examines MoreBit.
+func (its *PushPullPackOption) HasMoreBit() bool {
+	return (*its & PushPullBitMore) == PushPullBitMore
+}
+
 // GetPushPullPackOption returns PushPullOption.
 func (its *PushPullPack) GetPushPullPackOption() *PushPullPackOption {
 	var option = (*PushPullPackOption)(&its.Option)
@@ -143,13 +155,18 @@ func (its *PushPullPack) GetResponsePushPullPack() *PushPullPack {
 func (its *PushPullPack) ToString(isFull bool) string {
 	var option = PushPullPackOption(its.Option)
 	var opList = OpList(its.Operations)
+	var chunk string
+	if c := its.SnapshotChunk; c != nil {
+		chunk = fmt.Sprintf(" CHUNK(%d:%d+%d/%d)", c.Sseq, c.Offset, len(c.Data), c.Total)
+	}
 	return fmt.Sprintf(
-		"%s %s(%s) %s CP%s OP(%d){%v}",
+		"%s %s(%s) %s CP%s%s OP(%d){%v}",
 		its.Type,
 		its.Key,
 		its.DUID,
 		option.String(),
 		its.CheckPoint.ToString(),
+		chunk,
 		len(its.Operations),
 		opList.ToString(isFull))
 }
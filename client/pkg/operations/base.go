@@ -59,6 +59,19 @@ func (its *baseOperation) String() string {
 	return fmt.Sprintf("%s(%s|%+v)", its.Type, its.ID.ToString(), body)
 }
 
+func (its *baseOperation) getBase() *baseOperation {
+	return its
+}
+
+// ToBinaryModelOperation returns a model.Operation whose body is encoded in binary.
+func (its *baseOperation) ToBinaryModelOperation() *model.Operation {
+	return &model.Operation{
+		ID:     its.ID,
+		OpType: its.Type,
+		Body:   marshalBinaryBody(its.Body),
+	}
+}
+
 func (its *baseOperation) ToModelOperation() *model.Operation {
 	return &model.Operation{
 		ID:     its.ID,
@@ -0,0 +1,155 @@
+package operations
+
+import (
+	"github.com/orda-io/orda/client/pkg/model"
+	"math"
+)
+
+// Coalesce merges the redundant operations which have not been pushed yet, so that other replicas reach the same
+// state with fewer operations. Consecutive increases of a Counter are summed up into the last one, a put of a Map
+// overwritten by a later put on the same key is dropped, and an insertion into a List cancels out with the later
+// deletion of all its values. The operations of a transaction are kept intact, and the operations left are returned
+// in order with their IDs unchanged.
+func Coalesce(ops []*model.Operation) []*model.Operation {
+	ops = append([]*model.Operation{}, ops...)
+	inTx := make([]bool, len(ops))
+	for i := 0; i < len(ops); i++ {
+		if ops[i].OpType != model.TypeOfOperation_TRANSACTION {
+			continue
+		}
+		numOfOps := int(ModelToOperation(ops[i]).(*TransactionOperation).GetNumOfOps())
+		for j := i; j < i+numOfOps && j < len(ops); j++ {
+			inTx[j] = true
+		}
+	}
+	dropped := make([]bool, len(ops))
+	coalesceIncreases(ops, inTx, dropped)
+	coalescePuts(ops, inTx, dropped)
+	cancelInsertions(ops, inTx, dropped)
+
+	coalesced := make([]*model.Operation, 0, len(ops))
+	for i, op := range ops {
+		if !dropped[i] {
+			coalesced = append(coalesced, op)
+		}
+	}
+	return coalesced
+}
+
+// coalesceIncreases sums up the consecutive increases into the last one unless the sum overflows.
+func coalesceIncreases(ops []*model.Operation, inTx, dropped []bool) {
+	isIncrease := func(i int) bool {
+		return !inTx[i] && ops[i].OpType == model.TypeOfOperation_COUNTER_INCREASE
+	}
+	for i := 1; i < len(ops); i++ {
+		if !isIncrease(i-1) || !isIncrease(i) {
+			continue
+		}
+		prev := ModelToOperation(ops[i-1]).(*IncreaseOperation).GetBody()
+		this := ModelToOperation(ops[i]).(*IncreaseOperation).GetBody()
+		sum := int64(prev) + int64(this)
+		if sum > math.MaxInt32 || sum < math.MinInt32 {
+			continue
+		}
+		merged := NewIncreaseOperation(int32(sum))
+		merged.SetID(ops[i].ID)
+		if IsBinaryBody(ops[i].Body) {
+			ops[i] = merged.ToBinaryModelOperation()
+		} else {
+			ops[i] = merged.ToModelOperation()
+		}
+		dropped[i-1] = true
+	}
+}
+
+// coalescePuts drops the puts overwritten by a later put on the same key. A removal of the key or a transaction in
+// between keeps the put, because it is affected by the put.
+func coalescePuts(ops []*model.Operation, inTx, dropped []bool) {
+	overwritten := make(map[string]bool)
+	for i := len(ops) - 1; i >= 0; i-- {
+		if inTx[i] {
+			overwritten = make(map[string]bool)
+			continue
+		}
+		switch ops[i].OpType {
+		case model.TypeOfOperation_MAP_PUT:
+			key := ModelToOperation(ops[i]).(*PutOperation).GetBody().Key
+			if overwritten[key] {
+				dropped[i] = true
+			}
+			overwritten[key] = true
+		case model.TypeOfOperation_MAP_REMOVE:
+			delete(overwritten, ModelToOperation(ops[i]).(*RemoveOperation).GetBody().Key)
+		default:
+			overwritten = make(map[string]bool)
+		}
+	}
+}
+
+// insertionKey identifies the values inserted by an operation, regardless of their delimiters.
+type insertionKey struct {
+	era     uint32
+	lamport uint64
+	cuid    string
+}
+
+func newInsertionKey(ts *model.Timestamp) insertionKey {
+	return insertionKey{era: ts.GetEra(), lamport: ts.GetLamport(), cuid: ts.GetCUID()}
+}
+
+// cancelInsertions drops an insertion and the deletion of all its values, if no other operation refers to them.
+// Since no other replica has seen the values, they are not referred to by any operation of other replicas.
+func cancelInsertions(ops []*model.Operation, inTx, dropped []bool) {
+	refs := make(map[insertionKey]int)
+	insertions := make(map[insertionKey]int)
+	for i, op := range ops {
+		switch cast := ModelToOperation(op).(type) {
+		case *InsertOperation:
+			refs[newInsertionKey(cast.GetBody().T)]++
+			if !inTx[i] {
+				insertions[newInsertionKey(op.ID.GetTimestamp())] = i
+			}
+		case *DeleteOperation:
+			for _, t := range cast.GetBody().T {
+				refs[newInsertionKey(t)]++
+			}
+		case *UpdateOperation:
+			for _, t := range cast.GetBody().T {
+				refs[newInsertionKey(t)]++
+			}
+		}
+	}
+	for j, op := range ops {
+		if inTx[j] || op.OpType != model.TypeOfOperation_LIST_DELETE {
+			continue
+		}
+		targets := ModelToOperation(op).(*DeleteOperation).GetBody().T
+		if len(targets) == 0 {
+			continue
+		}
+		key := newInsertionKey(targets[0])
+		i, ok := insertions[key]
+		if !ok || i > j || refs[key] != len(targets) {
+			continue
+		}
+		if !deletesAll(targets, key, len(ModelToOperation(ops[i]).(*InsertOperation).GetBody().V)) {
+			continue
+		}
+		dropped[i], dropped[j] = true, true
+	}
+}
+
+// deletesAll examines if the targets of a deletion are all the values of an insertion.
+func deletesAll(targets []*model.Timestamp, key insertionKey, numOfValues int) bool {
+	if len(targets) != numOfValues {
+		return false
+	}
+	delimiters := make(map[uint32]bool)
+	for _, t := range targets {
+		if newInsertionKey(t) != key || int(t.Delimiter) >= numOfValues || delimiters[t.Delimiter] {
+			return false
+		}
+		delimiters[t.Delimiter] = true
+	}
+	return true
+}
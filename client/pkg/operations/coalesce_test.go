@@ -0,0 +1,76 @@
+package operations
+
+import (
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCoalescingOperations(t *testing.T) {
+	issue := func(opID *model.OperationID, ops ...iface.Operation) []*model.Operation {
+		var modelOps []*model.Operation
+		for _, op := range ops {
+			op.SetID(opID.Next())
+			modelOps = append(modelOps, op.ToModelOperation())
+		}
+		return modelOps
+	}
+
+	t.Run("Can sum up consecutive increases", func(t *testing.T) {
+		opID := model.NewOperationIDWithCUID(types.NewUID())
+		txOp := NewTransactionOperation("tag")
+		txOp.SetNumOfOps(3)
+		ops := issue(opID,
+			NewIncreaseOperation(1), NewIncreaseOperation(2), NewIncreaseOperation(3),
+			txOp, NewIncreaseOperation(4), NewIncreaseOperation(5),
+			NewIncreaseOperation(6), NewIncreaseOperation(2147483647),
+		)
+		coalesced := Coalesce(ops)
+		require.Len(t, coalesced, 6)
+		require.Equal(t, ops[2].ID, coalesced[0].ID)
+		require.Equal(t, int32(6), ModelToOperation(coalesced[0]).(*IncreaseOperation).GetBody())
+		require.Equal(t, ops[3:7], coalesced[1:5])
+		require.Equal(t, ops[7], coalesced[5])
+		// the operations given are not modified
+		require.Equal(t, int32(3), ModelToOperation(ops[2]).(*IncreaseOperation).GetBody())
+	})
+
+	t.Run("Can drop overwritten puts", func(t *testing.T) {
+		opID := model.NewOperationIDWithCUID(types.NewUID())
+		ops := issue(opID,
+			NewPutOperation("a", 1), NewPutOperation("b", 2), NewPutOperation("a", 3),
+			NewPutOperation("b", 4), NewRemoveOperation("b"), NewPutOperation("b", 5),
+			NewPutOperation("a", 6),
+		)
+		coalesced := Coalesce(ops)
+		require.Equal(t, []*model.Operation{ops[3], ops[4], ops[5], ops[6]}, coalesced)
+	})
+
+	t.Run("Can cancel out insertions with deletions", func(t *testing.T) {
+		opID := model.NewOperationIDWithCUID(types.NewUID())
+		head := &model.Timestamp{}
+		ins1 := NewInsertOperation(0, []interface{}{"x", "y"})
+		ins1.GetBody().T = head
+		ops := issue(opID, ins1)
+		ts1 := ops[0].ID.GetTimestamp()
+
+		ins2 := NewInsertOperation(0, []interface{}{"z"})
+		ins2.GetBody().T = head
+		ops = append(ops, issue(opID, ins2)...)
+		ts2 := ops[1].ID.GetTimestamp()
+
+		// z is referred to by the insertion of w
+		ins3 := NewInsertOperation(1, []interface{}{"w"})
+		ins3.GetBody().T = ts2
+		del1 := NewDeleteOperation(0, 2)
+		del1.GetBody().T = []*model.Timestamp{ts1.GetAndNextDelimiter(), ts1.GetAndNextDelimiter()}
+		del2 := NewDeleteOperation(0, 1)
+		del2.GetBody().T = []*model.Timestamp{ts2}
+		ops = append(ops, issue(opID, ins3, del1, del2)...)
+
+		coalesced := Coalesce(ops)
+		require.Equal(t, []*model.Operation{ops[1], ops[2], ops[4]}, coalesced)
+	})
+}
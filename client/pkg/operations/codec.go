@@ -0,0 +1,139 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/internal/codec"
+)
+
+// IsBinaryBody examines if the body of an operation is encoded in binary.
+func IsBinaryBody(body []byte) bool {
+	return codec.IsBinary(body)
+}
+
+// encodeBinaryBody encodes a body of an operation in binary, which leads with codec.Marker.
+func encodeBinaryBody(c interface{}) ([]byte, error) {
+	w := codec.NewWriter()
+	var err error
+	switch b := c.(type) {
+	case *errorBody:
+		w.PutUvarint(uint64(b.Code))
+		w.PutString(b.Msg)
+	case *TransactionBody:
+		w.PutString(b.Tag)
+		w.PutVarint(int64(b.NumOfOps))
+	case *increaseBody:
+		w.PutVarint(int64(b.Delta))
+	case *PutBody:
+		w.PutString(b.Key)
+		err = w.PutValue(b.Value)
+	case *RemoveBody:
+		w.PutString(b.Key)
+	case *InsertBody:
+		w.PutTimestamp(b.T)
+		err = w.PutValues(b.V)
+	case *DeleteBody:
+		w.PutTimestamps(b.T)
+	case *UpdateBody:
+		w.PutTimestamps(b.T)
+		err = w.PutValues(b.V)
+	case *DocPutInObjBody:
+		w.PutTimestamp(b.P)
+		w.PutString(b.K)
+		err = w.PutValue(b.V)
+	case *DocRemoveInObjectBody:
+		w.PutTimestamp(b.P)
+		w.PutString(b.K)
+	case *DocInsertToArrayBody:
+		w.PutTimestamp(b.P)
+		w.PutTimestamp(b.T)
+		err = w.PutValues(b.V)
+	case *DocDeleteInArrayBody:
+		w.PutTimestamp(b.P)
+		w.PutTimestamps(b.T)
+	case *DocUpdateInArrayBody:
+		w.PutTimestamp(b.P)
+		w.PutTimestamps(b.T)
+		err = w.PutValues(b.V)
+	default:
+		return nil, fmt.Errorf("unsupported body in binary: %T", c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// decodeBinaryBody decodes a body encoded by encodeBinaryBody into c.
+func decodeBinaryBody(body []byte, c interface{}) error {
+	r, err := codec.NewReader(body)
+	if err != nil {
+		return err
+	}
+	var u uint64
+	var i int64
+	switch b := c.(type) {
+	case *errorBody:
+		if u, err = r.ReadUvarint(); err == nil {
+			b.Code = errors.ErrorCode(u)
+			b.Msg, err = r.ReadString()
+		}
+	case *TransactionBody:
+		if b.Tag, err = r.ReadString(); err == nil {
+			i, err = r.ReadVarint()
+			b.NumOfOps = int32(i)
+		}
+	case *increaseBody:
+		i, err = r.ReadVarint()
+		b.Delta = int32(i)
+	case *PutBody:
+		if b.Key, err = r.ReadString(); err == nil {
+			b.Value, err = r.ReadValue()
+		}
+	case *RemoveBody:
+		b.Key, err = r.ReadString()
+	case *InsertBody:
+		if b.T, err = r.ReadTimestamp(); err == nil {
+			b.V, err = r.ReadValues()
+		}
+	case *DeleteBody:
+		b.T, err = r.ReadTimestamps()
+	case *UpdateBody:
+		if b.T, err = r.ReadTimestamps(); err == nil {
+			b.V, err = r.ReadValues()
+		}
+	case *DocPutInObjBody:
+		if b.P, err = r.ReadTimestamp(); err == nil {
+			if b.K, err = r.ReadString(); err == nil {
+				b.V, err = r.ReadValue()
+			}
+		}
+	case *DocRemoveInObjectBody:
+		if b.P, err = r.ReadTimestamp(); err == nil {
+			b.K, err = r.ReadString()
+		}
+	case *DocInsertToArrayBody:
+		if b.P, err = r.ReadTimestamp(); err == nil {
+			if b.T, err = r.ReadTimestamp(); err == nil {
+				b.V, err = r.ReadValues()
+			}
+		}
+	case *DocDeleteInArrayBody:
+		if b.P, err = r.ReadTimestamp(); err == nil {
+			b.T, err = r.ReadTimestamps()
+		}
+	case *DocUpdateInArrayBody:
+		if b.P, err = r.ReadTimestamp(); err == nil {
+			if b.T, err = r.ReadTimestamps(); err == nil {
+				b.V, err = r.ReadValues()
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported body in binary: %T", c)
+	}
+	if err != nil {
+		return err
+	}
+	return r.Done()
+}
@@ -80,6 +80,13 @@ func unmarshalBody(b []byte, c interface{}) interface{} {
 	case []byte:
 		return b
 	}
+	if IsBinaryBody(b) {
+		if err := decodeBinaryBody(b, c); err != nil {
+			log.Logger.Errorf("%v", b)
+			panic(err) // TODO: this should ne handled
+		}
+		return c
+	}
 	if err := json.Unmarshal(b, c); err != nil {
 		log.Logger.Errorf("%v", string(b))
 		panic(err) // TODO: this should ne handled
@@ -100,3 +107,33 @@ func marshalBody(c interface{}) []byte {
 	}
 	return j
 }
+
+func marshalBinaryBody(c interface{}) []byte {
+	switch cast := c.(type) {
+	case string:
+		return []byte(cast)
+	case []byte:
+		return cast
+	}
+	b, err := encodeBinaryBody(c)
+	if err != nil {
+		panic(err) // TODO: this should ne handled
+	}
+	return b
+}
+
+// EncodeBody returns a model.Operation whose body is encoded in binary or in JSON. If the body is already encoded
+// so, or is a snapshot, the operation itself is returned.
+func EncodeBody(op *model.Operation, inBinary bool) *model.Operation {
+	if IsBinaryBody(op.Body) == inBinary {
+		return op
+	}
+	base := ModelToOperation(op).(interface{ getBase() *baseOperation }).getBase()
+	if _, ok := base.Body.([]byte); ok {
+		return op
+	}
+	if inBinary {
+		return base.ToBinaryModelOperation()
+	}
+	return base.ToModelOperation()
+}
@@ -2,8 +2,11 @@ package operations
 
 import (
 	"encoding/json"
+	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/types"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -27,4 +30,63 @@ func TestConvertingOperations(t *testing.T) {
 		log.Logger.Infof("%v", sOp2)
 
 	})
+	t.Run("Can encode operations in binary", func(t *testing.T) {
+		cuid := types.NewUID()
+		ts1 := &model.Timestamp{Era: 1, Lamport: 11, CUID: cuid, Delimiter: 1}
+		ts2 := &model.Timestamp{Era: 1, Lamport: 1234567, CUID: cuid}
+		values := []interface{}{nil, true, 3, -4.5, "x", []interface{}{1, "y"}, map[string]interface{}{"b": 1, "a": false}}
+
+		insOp := NewInsertOperation(0, values)
+		insOp.GetBody().T = ts1
+		delOp := NewDeleteOperation(0, 2)
+		delOp.GetBody().T = []*model.Timestamp{ts1, ts2}
+		updOp := NewUpdateOperation(0, values[:2])
+		updOp.GetBody().T = []*model.Timestamp{ts1, ts2}
+		docInsOp := NewDocInsertToArrayOperation(ts1, 0, values)
+		docInsOp.GetBody().T = ts2
+		docDelOp := NewDocDeleteInArrayOperation(ts1, 0, 1)
+		docDelOp.GetBody().T = []*model.Timestamp{ts2}
+		docUpdOp := NewDocUpdateInArrayOperation(ts1, 0, values[:1])
+		docUpdOp.GetBody().T = []*model.Timestamp{ts2}
+		txOp := NewTransactionOperation("tag")
+		txOp.SetNumOfOps(3)
+
+		opList := []interface {
+			SetID(opID *model.OperationID)
+			ToModelOperation() *model.Operation
+		}{
+			NewErrorOperationWithCodeAndMsg(errors.PushPullMissingOps, "missing"),
+			txOp,
+			NewIncreaseOperation(-7),
+			NewPutOperation("k", map[string]interface{}{"a": []interface{}{1.5, "z"}}),
+			NewPutOperation("k", struct{ A int }{A: 1}),
+			NewRemoveOperation("k"),
+			insOp, delOp, updOp,
+			NewDocPutInObjOperation(ts1, "k", values),
+			NewDocRemoveInObjOperation(ts1, "k"),
+			docInsOp, docDelOp, docUpdOp,
+		}
+		for _, op := range opList {
+			op.SetID(model.NewOperationIDWithCUID(cuid))
+			jsonOp := op.ToModelOperation()
+			binOp := EncodeBody(jsonOp, true)
+			require.True(t, IsBinaryBody(binOp.Body))
+			require.False(t, IsBinaryBody(jsonOp.Body))
+			require.Less(t, len(binOp.Body), len(jsonOp.Body))
+			require.Equal(t, binOp, EncodeBody(binOp, true))
+
+			decoded := ModelToOperation(binOp)
+			require.Equal(t, jsonOp.OpType, decoded.GetType())
+			require.Equal(t, jsonOp.ID, decoded.GetID())
+			require.JSONEq(t, string(jsonOp.Body), string(EncodeBody(binOp, false).Body))
+			log.Logger.Infof("%v: %d bytes in JSON, %d bytes in binary", decoded, len(jsonOp.Body), len(binOp.Body))
+		}
+
+		snapOp := NewSnapshotOperation(model.TypeOfDatatype_COUNTER, []byte("{}")).ToModelOperation()
+		require.Equal(t, snapOp, EncodeBody(snapOp, true))
+
+		require.Panics(t, func() {
+			ModelToOperation(&model.Operation{OpType: model.TypeOfOperation_MAP_PUT, Body: []byte{0, 200}})
+		})
+	})
 }
@@ -10,13 +10,19 @@ import (
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/types"
+	"sync"
 )
 
-// Client is a client of Orda which manages connections and data
+// Client is a client of Orda which manages connections and data. A Client and the datatypes it creates are safe for
+// concurrent use by multiple goroutines: Connect, Sync and Close are serialized, subscribing to the same key
+// concurrently returns the same datatype, and each operation or read of a datatype is atomic with respect to the
+// synchronization. A transaction holds the datatype during the function, so the function should use the datatype
+// given to it rather than the one which begins the transaction. Once closed by any goroutine, the Client fails to sync.
 type Client interface {
 	Connect() error
 	Close() error
 	Sync() error
+	WaitForSync(ctx gocontext.Context) error
 	IsConnected() bool
 	CreateDatatype(key string, typeOf model.TypeOfDatatype, handlers *Handlers) Datatype
 
@@ -35,6 +41,7 @@ type Client interface {
 	CreateDocument(key string, handlers *Handlers) Document
 	SubscribeOrCreateDocument(key string, handlers *Handlers) Document
 	SubscribeDocument(key string, handlers *Handlers) Document
+	SubscribeDocumentPath(key string, path string, handlers *Handlers) Document
 }
 
 type clientState uint8
@@ -45,6 +52,8 @@ const (
 )
 
 type clientImpl struct {
+	mutex           sync.Mutex // guards state, and serializes Connect, Sync and Close
+	subscribeMutex  sync.Mutex // serializes subscribing to datatypes
 	state           clientState
 	conf            *ClientConfig
 	ctx             *context.ClientContext
@@ -79,6 +88,8 @@ func NewClient(conf *ClientConfig, alias string) Client {
 }
 
 func (its *clientImpl) IsConnected() bool {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
 	return its.state == connected
 }
 
@@ -97,6 +108,8 @@ func (its *clientImpl) CreateDatatype(key string, typeOf model.TypeOfDatatype, h
 }
 
 func (its *clientImpl) Connect() (err error) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
 	defer func() {
 		if err == nil {
 			its.state = connected
@@ -105,11 +118,18 @@ func (its *clientImpl) Connect() (err error) {
 	if err = its.syncManager.Connect(); err != nil {
 		return errors.ClientConnect.New(its.ctx.L(), err.Error())
 	}
-	err = its.syncManager.ExchangeClientRequestResponse()
+	if err = its.syncManager.ExchangeClientRequestResponse(); err != nil {
+		_ = its.syncManager.Close()
+	}
 	return
 }
 
 func (its *clientImpl) Close() error {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	if its.state != connected {
+		return nil
+	}
 	its.state = notConnected
 	its.ctx.L().Infof("close client")
 	return its.syncManager.Close()
@@ -129,8 +149,18 @@ func (its *clientImpl) SubscribeOrCreateDocument(key string, handlers *Handlers)
 	return its.subscribeOrCreateDocument(key, model.StateOfDatatype_DUE_TO_SUBSCRIBE_CREATE, handlers)
 }
 
+// SubscribeDocumentPath subscribes to the subtree of a Document at the path, which consists of the keys of JSON objects.
+// It receives only the snapshot and the operations affecting the subtree, and cannot modify out of it.
+func (its *clientImpl) SubscribeDocumentPath(key string, path string, handlers *Handlers) Document {
+	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_DOCUMENT, model.StateOfDatatype_DUE_TO_SUBSCRIBE, handlers, path)
+	if datatype != nil {
+		return datatype.(Document)
+	}
+	return nil
+}
+
 func (its *clientImpl) subscribeOrCreateDocument(key string, state model.StateOfDatatype, handlers *Handlers) Document {
-	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_DOCUMENT, state, handlers)
+	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_DOCUMENT, state, handlers, "")
 	if datatype != nil {
 		return datatype.(Document)
 	}
@@ -152,7 +182,7 @@ func (its *clientImpl) SubscribeOrCreateList(key string, handlers *Handlers) Lis
 }
 
 func (its *clientImpl) subscribeOrCreateList(key string, state model.StateOfDatatype, handlers *Handlers) List {
-	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_LIST, state, handlers)
+	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_LIST, state, handlers, "")
 	if datatype != nil {
 		return datatype.(List)
 	}
@@ -174,7 +204,7 @@ func (its *clientImpl) SubscribeOrCreateMap(key string, handlers *Handlers) Map
 }
 
 func (its *clientImpl) subscribeOrCreateMap(key string, state model.StateOfDatatype, handlers *Handlers) Map {
-	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_MAP, state, handlers)
+	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_MAP, state, handlers, "")
 	if datatype != nil {
 		return datatype.(Map)
 	}
@@ -196,7 +226,7 @@ func (its *clientImpl) SubscribeOrCreateCounter(key string, handlers *Handlers)
 }
 
 func (its *clientImpl) subscribeOrCreateCounter(key string, state model.StateOfDatatype, handlers *Handlers) Counter {
-	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_COUNTER, state, handlers)
+	datatype := its.subscribeOrCreateDatatype(key, model.TypeOfDatatype_COUNTER, state, handlers, "")
 	if datatype != nil {
 		return datatype.(Counter)
 	}
@@ -208,8 +238,11 @@ func (its *clientImpl) subscribeOrCreateDatatype(
 	typeOf model.TypeOfDatatype,
 	state model.StateOfDatatype,
 	handler *Handlers,
+	path string,
 ) iface.Datatype {
 	// TODO: this would be better go into datatypeManager
+	its.subscribeMutex.Lock()
+	defer its.subscribeMutex.Unlock()
 	if its.datatypeManager != nil {
 		data, err := its.datatypeManager.ExistDatatype(key, typeOf)
 		if err != nil && handler != nil {
@@ -234,10 +267,14 @@ func (its *clientImpl) subscribeOrCreateDatatype(
 		impl, err = newList(base, its.datatypeManager, handler)
 	case model.TypeOfDatatype_DOCUMENT:
 		impl, err = newDocument(base, its.datatypeManager, handler)
+		impl.(*document).SetPath(path)
 	}
 	if err != nil {
 		errs = errs.Append(err)
 	}
+	if its.conf.SyncType != model.SyncType_LOCAL_ONLY {
+		impl.(iface.Datatype).SetMaxUnpushed(its.conf.getMaxUnpushedOperations())
+	}
 	datatype = impl.(iface.Datatype)
 
 	if its.datatypeManager != nil {
@@ -257,8 +294,27 @@ func (its *clientImpl) SetLogger(logger *log.OrdaLog) {
 }
 
 func (its *clientImpl) Sync() error {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
 	if its.state == connected {
 		return its.datatypeManager.SyncAll()
 	}
 	return errors.ClientSync.New(its.ctx.L(), "not connected")
 }
+
+// WaitForSync blocks until the local operations of all the datatypes issued before are acknowledged by the server, or
+// the context is done. In the MANUALLY mode, it syncs the datatypes by itself.
+func (its *clientImpl) WaitForSync(ctx gocontext.Context) error {
+	if !its.IsConnected() {
+		return errors.ClientSync.New(its.ctx.L(), "not connected")
+	}
+	if its.conf.SyncType == model.SyncType_MANUALLY {
+		if err := its.Sync(); err != nil {
+			return err
+		}
+	}
+	if err := its.datatypeManager.Flush(ctx); err != nil {
+		return err
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package orda
 
 import (
+	"github.com/orda-io/orda/client/pkg/constants"
 	"github.com/orda-io/orda/client/pkg/model"
 )
 
@@ -10,6 +11,9 @@ type ClientConfig struct {
 	NotificationAddr string
 	CollectionName   string
 	SyncType         model.SyncType
+	// MaxUnpushedOperations limits the local operations not pushed yet for each datatype;
+	// constants.DefaultMaxUnpushedOperations if 0, and unlimited if negative.
+	MaxUnpushedOperations int
 }
 
 // NewLocalClientConfig makes a new local client which do not synchronize with OrdaServer
@@ -21,3 +25,13 @@ func NewLocalClientConfig(collectionName string) *ClientConfig {
 		SyncType:         model.SyncType_LOCAL_ONLY,
 	}
 }
+
+func (its *ClientConfig) getMaxUnpushedOperations() int {
+	if its.MaxUnpushedOperations == 0 {
+		return constants.DefaultMaxUnpushedOperations
+	}
+	if its.MaxUnpushedOperations < 0 {
+		return 0
+	}
+	return its.MaxUnpushedOperations
+}
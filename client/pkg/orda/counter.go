@@ -73,6 +73,7 @@ func (its *counter) ResetSnapshot() {
 }
 
 func (its *counter) Get() int32 {
+	defer its.rLock()()
 	return its.snapshot().Value
 }
 
@@ -85,8 +86,10 @@ func (its *counter) snapshot() *counterSnapshot {
 }
 
 func (its *counter) IncreaseBy(delta int32) (int32, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	op := operations.NewIncreaseOperation(delta)
-	ret, err := its.SentenceInTx(its.TxCtx, op, true)
+	ret, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return its.snapshot().Value, err
 	}
@@ -94,6 +97,7 @@ func (its *counter) IncreaseBy(delta int32) (int32, errors.OrdaError) {
 }
 
 func (its *counter) ToJSON() interface{} {
+	defer its.rLock()()
 	return struct {
 		Counter interface{}
 	}{
@@ -1,18 +1,24 @@
 package orda
 
 import (
+	gocontext "context"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/internal/datatypes"
 	"github.com/orda-io/orda/client/pkg/model"
 )
 
-// Datatype is an Orda Datatype which provides common interfaces.
+// Datatype is an Orda Datatype which provides common interfaces. A Datatype is safe for concurrent use by multiple
+// goroutines: each operation or read is atomic, and so is a transaction as a whole. The handlers are called in
+// another goroutine after a push-pull is applied, so they can use the datatype, which may have changed since.
 type Datatype interface {
 	GetType() model.TypeOfDatatype
 	GetState() model.StateOfDatatype
 	GetKey() string // @baseDatatype
 	ToJSON() interface{}
+	// Flush blocks until the local operations issued before are acknowledged by the server, or the context is done.
+	// In the MANUALLY mode, they are pushed when the Client syncs.
+	Flush(ctx gocontext.Context) errors.OrdaError
 }
 
 type datatype struct {
@@ -50,6 +56,34 @@ func (its *datatype) cloneDatatype(txCtx *datatypes.TransactionContext) *datatyp
 	}
 }
 
+// lock begins the transaction which locks the datatype for a local operation, unless the datatype is in a
+// transaction already. It returns the context in which the operation is executed, and the function to end it.
+func (its *datatype) lock() (*datatypes.TransactionContext, func()) {
+	txCtx := its.BeginTransaction(datatypes.NotUserTransactionTag, its.TxCtx, false)
+	if txCtx == nil {
+		return its.TxCtx, func() {}
+	}
+	return txCtx, func() {
+		if err := its.EndTransaction(txCtx, false, true); err != nil {
+			// do nothing
+		}
+	}
+}
+
+// rLock locks the datatype for reading unless the datatype is in a transaction, and returns the function to unlock.
+func (its *datatype) rLock() func() {
+	if its.TxCtx != nil {
+		return func() {}
+	}
+	return its.RLock()
+}
+
+// GetState returns the state of the datatype.
+func (its *datatype) GetState() model.StateOfDatatype {
+	defer its.rLock()()
+	return its.WiredDatatype.GetState()
+}
+
 func (its *datatype) HandleStateChange(old, new model.StateOfDatatype) {
 	if its.handlers != nil && its.handlers.stateChangeHandler != nil {
 		its.handlers.stateChangeHandler(its.Datatype, old, new)
@@ -68,17 +102,25 @@ func (its *datatype) HandleRemoteOperations(operations []interface{}) {
 	}
 }
 
+func (its *datatype) HandleSnapshotProgress(received, total uint64) {
+	if its.handlers != nil && its.handlers.snapshotProgressHandler != nil {
+		its.handlers.snapshotProgressHandler(its.Datatype, received, total)
+	}
+}
+
 // SubscribeOrCreate enables a datatype to subscribe and create itself.
 func (its *datatype) SubscribeOrCreate(state model.StateOfDatatype) errors.OrdaError {
 	if state == model.StateOfDatatype_DUE_TO_SUBSCRIBE {
 		its.DeliverTransaction(nil)
 		return nil
 	}
+	txCtx, end := its.lock()
+	defer end()
 	snapOp, err := its.CreateSnapshotOperation()
 	if err != nil {
 		return errors.DatatypeSubscribe.New(its.L(), err.Error())
 	}
-	_, err = its.SentenceInTx(its.TxCtx, snapOp, true)
+	_, err = its.SentenceInTx(txCtx, snapOp, true)
 	if err != nil {
 		return errors.DatatypeSubscribe.New(its.L(), err.Error())
 	}
@@ -11,7 +11,6 @@ import (
 	"github.com/orda-io/orda/client/pkg/utils"
 	"github.com/wI2L/jsondiff"
 	"strconv"
-	"strings"
 )
 
 // Document is an Orda datatype which provides document (JSON-like) interfaces.
@@ -85,7 +84,9 @@ func (its *document) ToJSONBytes() []byte {
 
 func (its *document) Patch(patches ...jsondiff.Operation) errors.OrdaError {
 	if len(patches) == 1 {
-		return its.patchEach(patches[0])
+		txCtx, end := its.lock()
+		defer end()
+		return its.inTx(txCtx).patchEach(patches[0])
 	}
 	tag := fmt.Sprintf("%d patches-%s", len(patches), utils.HashSum(patches))
 	if err := its.Transaction(tag, func(doc DocumentInTx) error {
@@ -106,9 +107,9 @@ func (its *document) Patch(patches ...jsondiff.Operation) errors.OrdaError {
 }
 
 func (its *document) GetByPath(path string) (Document, errors.OrdaError) {
-	path = strings.Trim(path, "/")
-	paths := strings.Split(path, "/")
-	if len(paths) == 1 && paths[0] == "" {
+	defer its.rLock()()
+	paths := splitPath(path)
+	if len(paths) == 0 {
 		return its, nil
 	}
 	target, err := its.snapshot().getTargetByPaths(paths)
@@ -191,14 +192,18 @@ func (its *document) patchEach(op jsondiff.Operation) errors.OrdaError {
 
 func (its *document) Transaction(tag string, userFunc func(document DocumentInTx) error) error {
 	return its.DoTransaction(tag, its.TxCtx, func(txCtx *datatypes.TransactionContext) error {
-		clone := &document{
-			datatype:         its.cloneDatatype(txCtx),
-			SnapshotDatatype: its.SnapshotDatatype,
-		}
-		return userFunc(clone)
+		return userFunc(its.inTx(txCtx))
 	})
 }
 
+// inTx returns the Document working in the transaction of the context.
+func (its *document) inTx(txCtx *datatypes.TransactionContext) *document {
+	return &document{
+		datatype:         its.cloneDatatype(txCtx),
+		SnapshotDatatype: its.SnapshotDatatype,
+	}
+}
+
 func (its *document) snapshot() jsonType {
 	return its.GetSnapshot().(jsonType)
 }
@@ -208,10 +213,12 @@ func (its *document) ResetSnapshot() {
 }
 
 func (its *document) ToJSON() interface{} {
+	defer its.rLock()()
 	return its.snapshot().ToJSON()
 }
 
 func (its *document) GetValue() interface{} {
+	defer its.rLock()()
 	return its.snapshot().ToJSON()
 }
 
@@ -266,11 +273,13 @@ func (its *document) ExecuteRemote(op interface{}) (interface{}, errors.OrdaErro
 
 // PutToObject associates a new value with the given key, and returns the old value as a Document
 func (its *document) PutToObject(key string, value interface{}) (Document, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.assertLocalOp("PutToObject", TypeJSONObject, false); err != nil {
 		return nil, err
 	}
 	op := operations.NewDocPutInObjOperation(its.snapshot().getCreateTime(), key, value)
-	removed, err := its.SentenceInTx(its.TxCtx, op, true)
+	removed, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return nil, err
 	}
@@ -282,11 +291,13 @@ func (its *document) PutToObject(key string, value interface{}) (Document, error
 
 // DeleteInObject removes the value associated with the given key, and returns the removed value as a Document.
 func (its *document) DeleteInObject(key string) (Document, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.assertLocalOp("DeleteInObject", TypeJSONObject, false); err != nil {
 		return nil, err
 	}
 	op := operations.NewDocRemoveInObjOperation(its.snapshot().getCreateTime(), key)
-	removed, err := its.SentenceInTx(its.TxCtx, op, true)
+	removed, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return nil, err
 	}
@@ -295,6 +306,7 @@ func (its *document) DeleteInObject(key string) (Document, errors.OrdaError) {
 
 // GetFromObject returns the child associated with the given key as a Document.
 func (its *document) GetFromObject(key string) (Document, errors.OrdaError) {
+	defer its.rLock()()
 	if err := its.assertLocalOp("GetFromObject", TypeJSONObject, true); err != nil {
 		return nil, err
 	}
@@ -316,6 +328,7 @@ func (its *document) GetFromArray(pos int) (Document, errors.OrdaError) {
 }
 
 func (its *document) GetManyFromArray(pos int, numOfNodes int) ([]Document, errors.OrdaError) {
+	defer its.rLock()()
 	if err := its.assertLocalOp("GetManyFromArray", TypeJSONArray, true); err != nil {
 		return nil, err
 	}
@@ -330,6 +343,8 @@ func (its *document) GetManyFromArray(pos int, numOfNodes int) ([]Document, erro
 // InsertToArray inserts given values at the next of the given position.
 // It returns the current JSONArray Document.
 func (its *document) InsertToArray(pos int, values ...interface{}) (Document, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.assertLocalOp("InsertToArray", TypeJSONArray, false); err != nil {
 		return its, err
 	}
@@ -338,7 +353,7 @@ func (its *document) InsertToArray(pos int, values ...interface{}) (Document, er
 		return its, err
 	}
 	op := operations.NewDocInsertToArrayOperation(its.snapshot().getCreateTime(), pos, values)
-	if _, err := its.SentenceInTx(its.TxCtx, op, true); err != nil {
+	if _, err := its.SentenceInTx(txCtx, op, true); err != nil {
 		return its, err
 	}
 	return its, nil
@@ -358,6 +373,8 @@ func (its *document) DeleteInArray(pos int) (Document, errors.OrdaError) {
 
 // DeleteManyInArray deletes values of the given range, and returns the deleted Documents.
 func (its *document) DeleteManyInArray(pos int, numOfNodes int) ([]Document, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.assertLocalOp("DeleteManyInArray", TypeJSONArray, false); err != nil {
 		return nil, err
 	}
@@ -366,7 +383,7 @@ func (its *document) DeleteManyInArray(pos int, numOfNodes int) ([]Document, err
 		return nil, err
 	}
 	op := operations.NewDocDeleteInArrayOperation(its.snapshot().getCreateTime(), pos, numOfNodes)
-	delJSONTypes, err := its.SentenceInTx(its.TxCtx, op, true)
+	delJSONTypes, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return nil, err
 	}
@@ -375,6 +392,8 @@ func (its *document) DeleteManyInArray(pos int, numOfNodes int) ([]Document, err
 
 // UpdateManyInArray updates the child from the given position, and returns the previous child Documents
 func (its *document) UpdateManyInArray(pos int, values ...interface{}) ([]Document, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.assertLocalOp("UpdateManyInArray", TypeJSONArray, false); err != nil {
 		return nil, err
 	}
@@ -383,7 +402,7 @@ func (its *document) UpdateManyInArray(pos int, values ...interface{}) ([]Docume
 		return nil, err
 	}
 	op := operations.NewDocUpdateInArrayOperation(its.snapshot().getCreateTime(), pos, values)
-	oldOnes, err := its.SentenceInTx(its.TxCtx, op, true)
+	oldOnes, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return nil, err
 	}
@@ -391,17 +410,21 @@ func (its *document) UpdateManyInArray(pos int, values ...interface{}) ([]Docume
 }
 
 func (its *document) GetTypeOfJSON() TypeOfJSON {
+	defer its.rLock()()
 	return its.snapshot().getType()
 }
 
 func (its *document) IsGarbage() bool {
+	defer its.rLock()()
 	return its.snapshot().isGarbage()
 }
 
 func (its *document) GetParentDocument() Document {
+	defer its.rLock()()
 	return its.toDocument(its.snapshot().getParent())
 }
 func (its *document) GetRootDocument() Document {
+	defer its.rLock()()
 	if its.snapshot().getRoot() == its.snapshot() {
 		return its
 	}
@@ -413,6 +436,7 @@ func (its *document) Equal(o Document) bool {
 	if its.datatype != other.datatype {
 		return false
 	}
+	defer its.rLock()()
 	if its.snapshot() != other.snapshot() {
 		return false
 	}
@@ -433,11 +457,23 @@ func (its *document) toDocument(child jsonType) Document {
 	}
 }
 func (its *document) assertLocalOp(opName string, ofJSON TypeOfJSON, workOnGarbage bool) errors.OrdaError {
-	if its.GetTypeOfJSON() != ofJSON {
+	if its.snapshot().getType() != ofJSON {
 		return errors.DatatypeInvalidParent.New(its.L(), opName, " is not allowed to ")
 	}
 	if !workOnGarbage && its.snapshot().isGarbage() {
 		return errors.DatatypeNoOp.New(its.L(), "already deleted from the root Document")
 	}
+	if !workOnGarbage && !its.isInPath() {
+		return errors.DatatypeOutOfPath.New(its.L(), opName, its.GetPath())
+	}
 	return nil
 }
+
+// isInPath examines if the Document is in the subtree to which it subscribes partially.
+func (its *document) isInPath() bool {
+	keys := splitPath(its.GetPath())
+	if len(keys) == 0 {
+		return true
+	}
+	return newDocumentPath(its.snapshot().getRoot(), keys).contains(its.snapshot())
+}
@@ -0,0 +1,193 @@
+package orda
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/operations"
+)
+
+// splitPath splits the path of a Document into its segments; the path of the root has no segment.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// documentPath is the subtree of a Document at a path, which consists of the keys of JSON objects.
+type documentPath struct {
+	keys      []string
+	ancestors []*jsonObject // the JSON objects from the root to the parent of the subtree
+	subtree   jsonType      // nil if the path does not exist
+}
+
+func newDocumentPath(root *jsonObject, keys []string) *documentPath {
+	its := &documentPath{keys: keys}
+	var node jsonType = root
+	for _, key := range keys {
+		obj, ok := node.(*jsonObject)
+		if !ok {
+			return its
+		}
+		its.ancestors = append(its.ancestors, obj)
+		if node = obj.getAsJSONType(key); node == nil {
+			return its
+		}
+	}
+	its.subtree = node
+	return its
+}
+
+// contains examines if the node is the subtree or one of its descendants, including tombstones.
+func (its *documentPath) contains(node jsonType) bool {
+	if its.subtree == nil {
+		return false
+	}
+	for ; node != nil; node = node.getParent() {
+		if node.getCreateTime().Compare(its.subtree.getCreateTime()) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTime examines if the node created at the timestamp is contained in the subtree.
+func (its *documentPath) containsTime(root jsonType, ts *model.Timestamp) bool {
+	node, ok := root.findJSONType(ts)
+	return ok && its.contains(node)
+}
+
+// isOnPath examines if the key of the JSON object created at the parent timestamp is on the path.
+func (its *documentPath) isOnPath(parent *model.Timestamp, key string) bool {
+	for i, ancestor := range its.ancestors {
+		if ancestor.getCreateTime().Compare(parent) == 0 {
+			return its.keys[i] == key
+		}
+	}
+	return false
+}
+
+// affects examines if the operation changes the subtree, including replacing or removing it on the path.
+func (its *documentPath) affects(root jsonType, op iface.Operation) bool {
+	switch cast := op.(type) {
+	case *operations.DocPutInObjOperation:
+		return its.isOnPath(cast.GetBody().P, cast.GetBody().K) || its.containsTime(root, cast.GetBody().P)
+	case *operations.DocRemoveInObjOperation:
+		return its.isOnPath(cast.GetBody().P, cast.GetBody().K) || its.containsTime(root, cast.GetBody().P)
+	case *operations.DocInsertToArrayOperation:
+		return its.containsTime(root, cast.GetBody().P)
+	case *operations.DocDeleteInArrayOperation:
+		return its.containsTime(root, cast.GetBody().P)
+	case *operations.DocUpdateInArrayOperation:
+		return its.containsTime(root, cast.GetBody().P)
+	case *operations.SnapshotOperation:
+		// the snapshot of the whole Document is replaced with that of the subtree
+		return false
+	}
+	return true
+}
+
+// DocumentPathFilter selects the snapshot and the operations of a Document which affect the subtree at a path,
+// so that a client subscribing to the path receives only them.
+type DocumentPathFilter struct {
+	root *jsonObject
+	path *documentPath
+}
+
+// NewDocumentPathFilter creates a DocumentPathFilter of the path, which consists of the keys of JSON objects.
+// The Document should reflect the operations to filter, so that their targets can be resolved.
+func NewDocumentPathFilter(doc Document, path string) (*DocumentPathFilter, errors.OrdaError) {
+	d, ok := doc.(*document)
+	if !ok {
+		return nil, errors.DatatypeIllegalParameters.New(nil, "not a document")
+	}
+	keys := splitPath(path)
+	if len(keys) == 0 {
+		return nil, errors.DatatypeIllegalParameters.New(d.L(), "empty path")
+	}
+	root := d.snapshot().getRoot()
+	return &DocumentPathFilter{root: root, path: newDocumentPath(root, keys)}, nil
+}
+
+// MarshalSnapshot returns the snapshot of the Document pruned to the subtree, where the ancestors of the subtree
+// have only the keys on the path.
+func (its *DocumentPathFilter) MarshalSnapshot(inBinary bool) ([]byte, errors.OrdaError) {
+	included := make(map[string]bool)
+	var nodes []jsonType
+	for _, ancestor := range its.path.ancestors {
+		nodes = append(nodes, ancestor)
+	}
+	for _, node := range its.root.getCommon().NodeMap {
+		if its.path.contains(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	for _, node := range nodes {
+		included[node.getCreateTime().Hash()] = true
+	}
+	marshaled := newMarshaledDocument()
+	for i, node := range nodes {
+		mjt := node.marshal()
+		if i < len(its.path.ancestors) {
+			mjt.O = &marshaledJSONObject{M: make(map[string]*model.Timestamp)}
+			key := its.path.keys[i]
+			if child := its.path.ancestors[i].getAsJSONType(key); child != nil && included[child.getCreateTime().Hash()] {
+				mjt.O.M[key] = child.getCreateTime()
+				if !child.isTomb() {
+					mjt.O.S = 1
+				}
+			}
+		}
+		marshaled.NodeMap = append(marshaled.NodeMap, mjt)
+	}
+	var snap []byte
+	var err error
+	if inBinary {
+		snap, err = marshaled.marshalBinary()
+	} else {
+		snap, err = json.Marshal(marshaled)
+	}
+	if err != nil {
+		return nil, errors.DatatypeMarshal.New(its.root.getLogger(), err.Error())
+	}
+	return snap, nil
+}
+
+// FilterOperations returns the operations which affect the subtree. The transactions keep only such operations,
+// and are dropped if none remains.
+func (its *DocumentPathFilter) FilterOperations(ops []*model.Operation) []*model.Operation {
+	var filtered []*model.Operation
+	for i := 0; i < len(ops); {
+		op := operations.ModelToOperation(ops[i])
+		txOp, ok := op.(*operations.TransactionOperation)
+		if !ok {
+			if its.path.affects(its.root, op) {
+				filtered = append(filtered, ops[i])
+			}
+			i++
+			continue
+		}
+		end := i + int(txOp.GetNumOfOps())
+		if end > len(ops) || end <= i {
+			end = len(ops)
+		}
+		var inTx []*model.Operation
+		for _, modelOp := range ops[i+1 : end] {
+			if its.path.affects(its.root, operations.ModelToOperation(modelOp)) {
+				inTx = append(inTx, modelOp)
+			}
+		}
+		if len(inTx) > 0 {
+			txOp.SetNumOfOps(len(inTx) + 1)
+			filtered = append(filtered, txOp.ToModelOperation())
+			filtered = append(filtered, inTx...)
+		}
+		i = end
+	}
+	return filtered
+}
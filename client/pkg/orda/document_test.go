@@ -2,6 +2,7 @@ package orda
 
 import (
 	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/testonly"
@@ -370,4 +371,37 @@ func TestDocument(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, elem2.GetValue(), "v3")
 	})
+
+	t.Run("Can purge stable tombstones of Document", func(t *testing.T) {
+		tw := testonly.NewTestWire(true)
+		root, _ := newDocument(testonly.NewBase(t.Name(), model.TypeOfDatatype_DOCUMENT), tw, nil)
+		_, err := root.PatchByJSON(`{"o1":{"e1":1,"o2":{"e2":2}},"a1":[{"e3":3},4,5],"e4":6}`)
+		require.NoError(t, err)
+		_, err = root.DeleteInObject("o1")
+		require.NoError(t, err)
+		array, err := root.GetFromObject("a1")
+		require.NoError(t, err)
+		_, err = array.UpdateManyInArray(0, 7) // {"e3":3} is detached
+		require.NoError(t, err)
+		_, err = array.DeleteInArray(1)
+		require.NoError(t, err)
+		_, err = root.PutToObject("e4", 8)
+		require.NoError(t, err)
+		jsonBefore := testonly.Marshal(t, root.ToJSON())
+		common := root.(*document).snapshot().getCommon()
+		require.Len(t, common.Cemetery, 3)
+
+		stable := &model.Timestamp{Lamport: root.(*document).GetOpID().Lamport + 1}
+		snap := root.(*document).GetSnapshot().(iface.PurgeableSnapshot)
+		require.Equal(t, 3, snap.PurgeTombstones(stable))
+		require.Empty(t, common.Cemetery)
+		require.Len(t, common.NodeMap, 5) // root, a1, 7, 5, 8
+		require.Equal(t, jsonBefore, testonly.Marshal(t, root.ToJSON()))
+
+		clone, _ := newDocument(testonly.NewBase(t.Name(), model.TypeOfDatatype_DOCUMENT), tw, nil)
+		snapshot, oErr := root.(*document).MarshalSnapshot(false)
+		require.NoError(t, oErr)
+		require.NoError(t, clone.(*document).ApplySnapshot(snapshot))
+		require.Equal(t, jsonBefore, testonly.Marshal(t, clone.ToJSON()))
+	})
 }
@@ -2,6 +2,7 @@ package orda
 
 import (
 	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/internal/codec"
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/operations"
@@ -37,3 +38,106 @@ func TestOperationEncoding(t *testing.T) {
 		require.Equal(t, testonly.Marshal(t, gOp1), testonly.Marshal(t, gOp2))
 	})
 }
+
+func TestSnapshotEncoding(t *testing.T) {
+	// encodeSnapshot examines that the snapshot encoded in binary is smaller than in JSON, and
+	// that both of them are decoded into the same snapshot.
+	encodeSnapshot := func(t *testing.T, typeOf model.TypeOfDatatype, datatype iface.Datatype, newOne func() iface.Datatype) {
+		inBinary, err := datatype.MarshalSnapshot(true)
+		require.NoError(t, err)
+		inJSON, err := datatype.MarshalSnapshot(false)
+		require.NoError(t, err)
+		require.True(t, codec.IsBinary(inBinary))
+		require.False(t, codec.IsBinary(inJSON))
+		require.Less(t, len(inBinary), len(inJSON))
+		log.Logger.Infof("%v snapshot: %d bytes in JSON, %d bytes in binary", typeOf, len(inJSON), len(inBinary))
+
+		for _, snap := range [][]byte{inBinary, inJSON} {
+			decoded := newOne()
+			_, err = decoded.ExecuteRemote(operations.NewSnapshotOperation(typeOf, snap))
+			require.NoError(t, err)
+			require.Equal(t, testonly.Marshal(t, datatype.ToJSON()), testonly.Marshal(t, decoded.ToJSON()))
+			reencoded, err := decoded.MarshalSnapshot(true)
+			require.NoError(t, err)
+			require.Equal(t, len(inBinary), len(reencoded))
+			if typeOf != model.TypeOfDatatype_DOCUMENT { // the nodes of a document are not ordered
+				require.Equal(t, inBinary, reencoded)
+			}
+		}
+	}
+
+	t.Run("Can encode counter snapshot in binary", func(t *testing.T) {
+		base := testonly.NewBase("key1", model.TypeOfDatatype_COUNTER)
+		counter1, _ := newCounter(base, nil, nil)
+		_, _ = counter1.IncreaseBy(-1024)
+		encodeSnapshot(t, model.TypeOfDatatype_COUNTER, counter1.(iface.Datatype), func() iface.Datatype {
+			counter2, _ := newCounter(base, nil, nil)
+			return counter2.(iface.Datatype)
+		})
+	})
+
+	t.Run("Can encode map snapshot in binary", func(t *testing.T) {
+		base := testonly.NewBase("key1", model.TypeOfDatatype_MAP)
+		map1, _ := newMap(base, nil, nil)
+		_, _ = map1.Put("k1", "v1")
+		_, _ = map1.Put("k2", 3.14)
+		_, _ = map1.Put("k3", map[string]interface{}{"a": []interface{}{1, true, nil}})
+		_, _ = map1.Remove("k1")
+		encodeSnapshot(t, model.TypeOfDatatype_MAP, map1.(iface.Datatype), func() iface.Datatype {
+			map2, _ := newMap(base, nil, nil)
+			return map2.(iface.Datatype)
+		})
+	})
+
+	t.Run("Can encode list snapshot in binary", func(t *testing.T) {
+		base := testonly.NewBase("key1", model.TypeOfDatatype_LIST)
+		list1, _ := newList(base, nil, nil)
+		var values []interface{}
+		for i := 0; i < 10000; i++ {
+			values = append(values, i)
+		}
+		_, _ = list1.InsertMany(0, values...)
+		for i := 0; i < 100; i++ {
+			_, _ = list1.Insert(list1.Size(), "x")
+		}
+		_, _ = list1.DeleteMany(10, 5)
+		_, _ = list1.Update(20, "updated")
+		_, _ = list1.Insert(30, "inserted")
+
+		inBinary, err := list1.(iface.Datatype).MarshalSnapshot(true)
+		require.NoError(t, err)
+		inJSON, err := list1.(iface.Datatype).MarshalSnapshot(false)
+		require.NoError(t, err)
+		require.Less(t, len(inBinary)*5, len(inJSON))
+
+		encodeSnapshot(t, model.TypeOfDatatype_LIST, list1.(iface.Datatype), func() iface.Datatype {
+			list2, _ := newList(base, nil, nil)
+			return list2.(iface.Datatype)
+		})
+	})
+
+	t.Run("Can encode document snapshot in binary", func(t *testing.T) {
+		base := testonly.NewBase("key1", model.TypeOfDatatype_DOCUMENT)
+		doc1, _ := newDocument(base, nil, nil)
+		_, _ = doc1.PutToObject("k1", "v1")
+		_, _ = doc1.PutToObject("k2", map[string]interface{}{"a": 1, "b": []interface{}{1, 2, 3}})
+		_, _ = doc1.PutToObject("k3", []interface{}{"x", "y", map[string]interface{}{"c": true}})
+		_, _ = doc1.DeleteInObject("k1")
+		arr, err := doc1.GetFromObject("k3")
+		require.NoError(t, err)
+		_, _ = arr.InsertToArray(1, "z", 4.5)
+		_, _ = arr.DeleteInArray(0)
+		encodeSnapshot(t, model.TypeOfDatatype_DOCUMENT, doc1.(iface.Datatype), func() iface.Datatype {
+			doc2, _ := newDocument(base, nil, nil)
+			return doc2.(iface.Datatype)
+		})
+	})
+
+	t.Run("Can reject unsupported version of snapshot", func(t *testing.T) {
+		base := testonly.NewBase("key1", model.TypeOfDatatype_COUNTER)
+		counter1, _ := newCounter(base, nil, nil)
+		_, err := counter1.(iface.Datatype).ExecuteRemote(
+			operations.NewSnapshotOperation(model.TypeOfDatatype_COUNTER, []byte{codec.Marker, codec.SnapshotVersion + 1, 2}))
+		require.Error(t, err)
+	})
+}
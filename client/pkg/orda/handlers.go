@@ -7,9 +7,10 @@ import (
 
 // Handlers defines a set of handlers which can handles the events related to Datatype
 type Handlers struct {
-	stateChangeHandler     func(dt Datatype, old model.StateOfDatatype, new model.StateOfDatatype)
-	remoteOperationHandler func(dt Datatype, opList []interface{})
-	errorHandler           func(dt Datatype, errs ...errors.OrdaError)
+	stateChangeHandler      func(dt Datatype, old model.StateOfDatatype, new model.StateOfDatatype)
+	remoteOperationHandler  func(dt Datatype, opList []interface{})
+	errorHandler            func(dt Datatype, errs ...errors.OrdaError)
+	snapshotProgressHandler func(dt Datatype, received uint64, total uint64)
 }
 
 // NewHandlers creates a set of handlers for a datatype.
@@ -39,3 +40,9 @@ func (its *Handlers) SetHandlers(
 		its.errorHandler = errorHandler
 	}
 }
+
+// SetSnapshotProgressHandler sets the handler which is called whenever a chunk of a snapshot is pulled
+// with the number of received bytes out of the total. It is called during the synchronization, so it should return quickly.
+func (its *Handlers) SetSnapshotProgressHandler(handler func(dt Datatype, received uint64, total uint64)) {
+	its.snapshotProgressHandler = handler
+}
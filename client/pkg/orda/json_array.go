@@ -99,7 +99,7 @@ func (its *jsonArray) updateLocal(
 ) ([]*model.Timestamp, []jsonType, errors.OrdaError) {
 	var updatedTargets []*model.Timestamp
 	var oldJSONTypes []jsonType
-	target := its.retrieve(pos + 1)
+	target, offset := its.retrieve(pos + 1)
 	for _, v := range values {
 		/*
 			In the list, orderedType.K is used to resolve the order conflicts, and to find targets by remote operations.
@@ -107,14 +107,14 @@ func (its *jsonArray) updateLocal(
 			In addition, the old jsonType except jsonElement should be accessible by some remote operations as a parent.
 			Thus, they are added to Cemetery.
 		*/
-		oldOne := target.getTimedType().(jsonType)
-		updatedTargets = append(updatedTargets, target.getOrderTime())
+		oldOne := target.getTimedTypeAt(offset).(jsonType)
+		updatedTargets = append(updatedTargets, target.getOrderTimeAt(offset))
 		newOne := its.createJSONType(its, v, ts) // ts's delimiter might increase.
-		target.setTimedType(newOne)
+		target.setTimedTypeAt(offset, newOne)
 		its.addToNodeMap(newOne)
 		its.funeral(oldOne, newOne.getTime())
 		oldJSONTypes = append(oldJSONTypes, oldOne)
-		target = target.getNextLive()
+		target, offset = nextLive(target, offset)
 	}
 	return updatedTargets, oldJSONTypes, nil
 }
@@ -130,12 +130,12 @@ func (its *jsonArray) updateRemote(
 		newOne := its.createJSONType(its, values[i], ts)
 		its.addToNodeMap(newOne)
 		// thisTS := ts.GetAndNextDelimiter()
-		if node, ok := its.Map[t.Hash()]; ok {
+		if node, offset, ok := its.findRun(t); ok {
 			var deleted, updated jsonType
-			oldOne := node.getTimedType().(jsonType)
-			if !node.isTomb() {
-				if node.getTime().Compare(newOne.getCreateTime()) < 0 {
-					node.setTimedType(newOne)
+			oldOne := node.getTimedTypeAt(offset).(jsonType)
+			if !oldOne.isTomb() {
+				if oldOne.getTime().Compare(newOne.getCreateTime()) < 0 {
+					node.setTimedTypeAt(offset, newOne)
 					deleted = oldOne
 					updated = newOne
 				} else {
@@ -188,42 +188,29 @@ func (its *jsonArray) equal(o jsonType) bool {
 	if its.size != ja.size {
 		return false
 	}
-	for k, v1 := range its.Map {
-		v2 := ja.Map[k]
-		if (v1 == nil && v2 != nil) || (v1 != nil && v2 == nil) {
-			return false
-		}
-		if v1 == nil && v2 == nil { // cannot happen
-			return false
-		}
-		ov1, ov2 := v1.(orderedType), v2.(orderedType)
-		if ov1.getOrderTime().Compare(ov2.getOrderTime()) != 0 {
-			return false
-		}
-		if ov1.getOrderTime().Compare(model.OldestTimestamp()) == 0 &&
-			ov1.getOrderTime().Compare(ov2.getOrderTime()) == 0 {
-			return true
-		}
-		jt1, jt2 := ov1.getTimedType().(jsonType), ov2.getTimedType().(jsonType)
-		if jt1.getCreateTime().Compare(jt2.getCreateTime()) != 0 {
-			return false
-		}
-		if (ov1.getPrev() == nil && ov2.getPrev() != nil) || (ov1.getPrev() != nil && ov2.getPrev() == nil) {
+	// the timedTypes are compared in order, since their runs can be split differently.
+	type ordered struct {
+		o  *model.Timestamp
+		jt jsonType
+	}
+	var ordered1, ordered2 []ordered
+	its.forEachTimedType(func(o *model.Timestamp, tt timedType) {
+		ordered1 = append(ordered1, ordered{o, tt.(jsonType)})
+	})
+	ja.forEachTimedType(func(o *model.Timestamp, tt timedType) {
+		ordered2 = append(ordered2, ordered{o, tt.(jsonType)})
+	})
+	if len(ordered1) != len(ordered2) {
+		return false
+	}
+	for i, o1 := range ordered1 {
+		o2 := ordered2[i]
+		if o1.o.Compare(o2.o) != 0 || o1.o.Delimiter != o2.o.Delimiter {
 			return false
 		}
-		if ov1.getPrev() != nil && ov2.getPrev() != nil {
-			if ov1.getPrev().getOrderTime().Compare(ov2.getPrev().getOrderTime()) != 0 {
-				return false
-			}
-		}
-		if (ov1.getNext() == nil && ov2.getNext() != nil) || (ov1.getNext() != nil && ov2.getNext() == nil) {
+		if o1.jt.getCreateTime().Compare(o2.jt.getCreateTime()) != 0 {
 			return false
 		}
-		if ov1.getNext() != nil && ov2.getNext() != nil {
-			if ov1.getNext().getOrderTime().Compare(ov2.getNext().getOrderTime()) != 0 {
-				return false
-			}
-		}
 	}
 	return true
 }
@@ -245,20 +232,16 @@ func (its *jsonArray) equal(o jsonType) bool {
 // ToJSON returns an interface type that contains all live objects.
 func (its *jsonArray) ToJSON() interface{} {
 	var list = make([]interface{}, 0)
-	n := its.listSnapshot.head.getNextLive()
-	for n != nil {
-		if !n.isTomb() {
-			switch cast := n.getTimedType().(type) {
-			case *jsonObject:
-				list = append(list, cast.ToJSON())
-			case *jsonElement:
-				list = append(list, cast.getValue())
-			case *jsonArray:
-				list = append(list, cast.ToJSON())
-			}
+	its.listSnapshot.forEachLive(func(tt timedType) {
+		switch cast := tt.(type) {
+		case *jsonObject:
+			list = append(list, cast.ToJSON())
+		case *jsonElement:
+			list = append(list, cast.getValue())
+		case *jsonArray:
+			list = append(list, cast.ToJSON())
 		}
-		n = n.getNextLive()
-	}
+	})
 	return list
 }
 
@@ -268,19 +251,16 @@ func (its *jsonArray) marshal() *marshaledJSONType {
 	marshaledJA := &marshaledJSONArray{
 		S: its.listSnapshot.size,
 	}
-	n := its.listSnapshot.head.getNext() // NOT store head
-	for n != nil {
-		jt := n.getTimedType().(jsonType)
+	its.listSnapshot.forEachTimedType(func(o *model.Timestamp, tt timedType) { // NOT store head
+		jt := tt.(jsonType)
 		var mot marshaledOrderedType
-		if n.getOrderTime() == jt.getCreateTime() {
-			mot = [2]*model.Timestamp{n.getOrderTime()}
+		if c := jt.getCreateTime(); o == c || (o.Compare(c) == 0 && o.Delimiter == c.Delimiter) {
+			mot = [2]*model.Timestamp{o}
 		} else {
-			mot = [2]*model.Timestamp{n.getOrderTime(), jt.getCreateTime()}
+			mot = [2]*model.Timestamp{o, c}
 		}
-
 		marshaledJA.N = append(marshaledJA.N, mot)
-		n = n.getNext()
-	}
+	})
 	marshal.A = marshaledJA
 	return marshal
 }
@@ -288,7 +268,7 @@ func (its *jsonArray) marshal() *marshaledJSONType {
 func (its *jsonArray) unmarshal(marshaled *marshaledJSONType, assistant *unmarshalAssistant) {
 	marshaledJA := marshaled.A
 	its.listSnapshot = newListSnapshot(assistant.common.BaseDatatype)
-	prev := its.listSnapshot.head
+	last := its.listSnapshot.head
 	for _, mot := range marshaledJA.N {
 		o := mot[0]
 		c := mot[1]
@@ -296,14 +276,7 @@ func (its *jsonArray) unmarshal(marshaled *marshaledJSONType, assistant *unmarsh
 			c = o
 		}
 		timedType, _ := its.findJSONType(c)
-		node := &orderedNode{
-			timedType: timedType,
-			O:         assistant.unifyTimestamp(o),
-		}
-		its.Map[node.getOrderTime().Hash()] = node
-		prev.insertNext(node)
-		prev = node
-
+		last = its.appendInOrder(last, timedType, assistant.unifyTimestamp(o))
 	}
 	its.size = marshaled.A.S
 }
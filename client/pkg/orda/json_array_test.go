@@ -5,6 +5,7 @@ import (
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/testonly"
 	"github.com/orda-io/orda/client/pkg/types"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -345,3 +346,49 @@ func TestJSONArray(t *testing.T) {
 		jsonObjectMarshalTest(t, root)
 	})
 }
+
+// newBenchmarkJSONArray creates a jsonArray of numOfBenchmarkNodes live elements interleaved with as many tombstones.
+func newBenchmarkJSONArray(b *testing.B) (*jsonObject, *jsonArray, *model.OperationID) {
+	opID := model.NewOperationID()
+	root := newJSONObject(testonly.NewBase(b.Name(), model.TypeOfDatatype_DOCUMENT), nil, model.OldestTimestamp())
+	values := make([]interface{}, 2*numOfBenchmarkNodes)
+	for i := range values {
+		values[i] = i
+	}
+	root.putCommon("K1", values, opID.Next().GetTimestamp())
+	array := root.getChildAsJSONArray("K1")
+	for pos := 0; pos < numOfBenchmarkNodes; pos++ {
+		_, _, _ = root.DeleteLocalInArray(array.getCreateTime(), pos, 1, opID.Next().GetTimestamp())
+	}
+	b.ResetTimer()
+	return root, array, opID
+}
+
+func BenchmarkJSONArrayGet(b *testing.B) {
+	_, array, _ := newBenchmarkJSONArray(b)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		array.getJSONType(r.Intn(array.size))
+	}
+}
+
+func BenchmarkJSONArrayInsert(b *testing.B) {
+	root, array, opID := newBenchmarkJSONArray(b)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		_, _, _ = root.InsertLocalInArray(array.getCreateTime(), r.Intn(array.size+1), opID.Next().GetTimestamp(), i)
+	}
+}
+
+func BenchmarkJSONArrayDelete(b *testing.B) {
+	root, array, opID := newBenchmarkJSONArray(b)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		if array.size == 0 {
+			b.StopTimer()
+			root, array, opID = newBenchmarkJSONArray(b)
+			b.StartTimer()
+		}
+		_, _, _ = root.DeleteLocalInArray(array.getCreateTime(), r.Intn(array.size), 1, opID.Next().GetTimestamp())
+	}
+}
@@ -170,14 +170,83 @@ func (its *jsonObject) equal(o jsonType) bool {
 
 // ///////////////////// methods of iface.Snapshot ///////////////////////////////////////
 
+// PurgeTombstones removes the tombstones deleted before the stable timestamp from the Document, together with the
+// jsonTypes under them. The jsonTypes in the Cemetery detached by put or update are also removed.
+func (its *jsonObject) PurgeTombstones(stable *model.Timestamp) int {
+	kept := make(map[jsonType]bool)
+	purged := purgeJSONType(its, stable, kept)
+	for _, tomb := range its.getCommon().Cemetery {
+		if !kept[tomb] && isStable(tomb.getDeleteTime(), stable) {
+			bury(tomb)
+			purged++
+		}
+	}
+	return purged
+}
+
+// purgeJSONType purges the tombstones under the jsonType, and marks the jsonTypes kept in their parents.
+func purgeJSONType(jt jsonType, stable *model.Timestamp, kept map[jsonType]bool) int {
+	kept[jt] = true
+	purged := 0
+	switch cast := jt.(type) {
+	case *jsonObject:
+		for k, tt := range cast.Map {
+			child, ok := tt.(jsonType)
+			if !ok {
+				continue
+			}
+			if child.isTomb() && isStable(child.getDeleteTime(), stable) {
+				delete(cast.Map, k)
+				bury(child)
+				purged++
+				continue
+			}
+			purged += purgeJSONType(child, stable, kept)
+		}
+	case *jsonArray:
+		purged += cast.purge(stable, func(tt timedType) {
+			bury(tt.(jsonType))
+		})
+		cast.forEachTimedType(func(_ *model.Timestamp, tt timedType) {
+			purged += purgeJSONType(tt.(jsonType), stable, kept)
+		})
+	}
+	return purged
+}
+
+// bury removes the jsonType and the ones under it from the NodeMap and the Cemetery.
+func bury(jt jsonType) {
+	jt.removeFromNodeMap(jt)
+	cemetery := jt.getCommon().Cemetery
+	if d := jt.getDeleteTime(); d != nil && cemetery[d.Hash()] == jt {
+		delete(cemetery, d.Hash())
+	}
+	switch cast := jt.(type) {
+	case *jsonObject:
+		for _, tt := range cast.Map {
+			if child, ok := tt.(jsonType); ok {
+				bury(child)
+			}
+		}
+	case *jsonArray:
+		cast.forEachTimedType(func(_ *model.Timestamp, tt timedType) {
+			bury(tt.(jsonType))
+		})
+	}
+}
+
 // MarshalJSON returns marshaledDocument.
 func (its *jsonObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(its.marshalDocument())
+}
+
+func (its *jsonObject) marshalDocument() *marshaledDocument {
 	marshalDoc := newMarshaledDocument()
 	for _, v := range its.getCommon().NodeMap {
 		marshaled := v.marshal()
 		marshalDoc.NodeMap = append(marshalDoc.NodeMap, marshaled)
 	}
-	return json.Marshal(marshalDoc)
+	return marshalDoc
 }
 
 func (its *jsonObject) UnmarshalJSON(bytes []byte) error {
@@ -186,7 +255,11 @@ func (its *jsonObject) UnmarshalJSON(bytes []byte) error {
 	if err := json.Unmarshal(bytes, &forUnmarshal); err != nil {
 		return errors.DatatypeMarshal.New(its.getLogger(), err.Error())
 	}
+	its.unmarshalDocument(&forUnmarshal)
+	return nil
+}
 
+func (its *jsonObject) unmarshalDocument(forUnmarshal *marshaledDocument) {
 	assistant := &unmarshalAssistant{
 		tsMap: make(map[string]*model.Timestamp),
 		common: &jsonCommon{
@@ -225,7 +298,6 @@ func (its *jsonObject) UnmarshalJSON(bytes []byte) error {
 			its.addToCemetery(jt)
 		}
 	}
-	return nil
 }
 
 func (its *jsonObject) marshal() *marshaledJSONType {
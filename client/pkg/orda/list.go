@@ -9,6 +9,7 @@ import (
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/operations"
 	"github.com/orda-io/orda/client/pkg/types"
+	"sort"
 	"strings"
 )
 
@@ -63,6 +64,7 @@ func (its *list) ResetSnapshot() {
 }
 
 func (its *list) ToJSON() interface{} {
+	defer its.rLock()()
 	return struct {
 		List []interface{}
 	}{
@@ -107,6 +109,7 @@ func (its *list) ExecuteRemote(op interface{}) (interface{}, errors.OrdaError) {
 }
 
 func (its *list) Size() int {
+	defer its.rLock()()
 	return its.snapshot().Size()
 }
 
@@ -115,6 +118,8 @@ func (its *list) Insert(pos int, value interface{}) (interface{}, errors.OrdaErr
 }
 
 func (its *list) InsertMany(pos int, values ...interface{}) (interface{}, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.snapshot().validateInsertPosition(pos); err != nil {
 		return nil, err
 	}
@@ -123,7 +128,7 @@ func (its *list) InsertMany(pos int, values ...interface{}) (interface{}, errors
 		return nil, errors.DatatypeIllegalParameters.New(its.L(), err2.Error())
 	}
 	op := operations.NewInsertOperation(pos, jsonValues)
-	ret, err := its.SentenceInTx(its.TxCtx, op, true)
+	ret, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +136,8 @@ func (its *list) InsertMany(pos int, values ...interface{}) (interface{}, errors
 }
 
 func (its *list) Update(pos int, values ...interface{}) ([]interface{}, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.snapshot().validateGetRange(pos, len(values)); err != nil {
 		return nil, err
 	}
@@ -139,7 +146,7 @@ func (its *list) Update(pos int, values ...interface{}) ([]interface{}, errors.O
 		return nil, errors.DatatypeIllegalParameters.New(its.L(), err2.Error())
 	}
 	op := operations.NewUpdateOperation(pos, jsonValues)
-	ret, err := its.SentenceInTx(its.TxCtx, op, true)
+	ret, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return nil, err
 	}
@@ -154,11 +161,13 @@ func (its *list) Delete(pos int) (interface{}, errors.OrdaError) {
 
 // DeleteMany deletes the nodes at index pos in sequence.
 func (its *list) DeleteMany(pos int, numOfNode int) ([]interface{}, errors.OrdaError) {
+	txCtx, end := its.lock()
+	defer end()
 	if err := its.snapshot().validateGetRange(pos, numOfNode); err != nil {
 		return nil, err
 	}
 	op := operations.NewDeleteOperation(pos, numOfNode)
-	ret, err := its.SentenceInTx(its.TxCtx, op, true)
+	ret, err := its.SentenceInTx(txCtx, op, true)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +179,7 @@ func (its *list) DeleteMany(pos int, numOfNode int) ([]interface{}, errors.OrdaE
 }
 
 func (its *list) Get(pos int) (interface{}, errors.OrdaError) {
+	defer its.rLock()()
 	if err := its.snapshot().validateGetPosition(pos); err != nil {
 		return nil, err
 	}
@@ -177,6 +187,7 @@ func (its *list) Get(pos int) (interface{}, errors.OrdaError) {
 }
 
 func (its *list) GetMany(pos int, numOfNodes int) ([]interface{}, errors.OrdaError) {
+	defer its.rLock()()
 	if err := its.snapshot().validateGetRange(pos, numOfNodes); err != nil {
 		return nil, err
 	}
@@ -189,21 +200,120 @@ func (its *list) GetMany(pos int, numOfNodes int) ([]interface{}, errors.OrdaErr
 
 type listSnapshot struct {
 	iface.BaseDatatype
-	head orderedType
-	size int
-	Map  map[string]orderedType
+	head  orderedType
+	size  int
+	runs  map[string][]orderedType // the runs of each operation sorted by their order timestamps
+	index *orderedIndex
 }
 
 func newListSnapshot(base iface.BaseDatatype) *listSnapshot {
 	head := newHead()
-	m := make(map[string]orderedType)
-	m[head.hash()] = head
-	return &listSnapshot{
+	its := &listSnapshot{
 		BaseDatatype: base,
 		head:         head,
-		Map:          m,
+		runs:         make(map[string][]orderedType),
 		size:         0,
+		index:        newOrderedIndex(head),
 	}
+	its.addRun(head)
+	return its
+}
+
+// newRuns makes the runs of the timedTypes, where a run continues while their timestamps succeed by the delimiter.
+func newRuns(tts []timedType) []orderedType {
+	var runs []orderedType
+	var last orderedType
+	for i, tt := range tts {
+		if last != nil && succeeds(tts[i-1].getTime(), tt.getTime()) {
+			last.appendTimedType(tt)
+			continue
+		}
+		last = &orderedNode{
+			timedType: tt,
+			O:         tt.getTime(),
+		}
+		runs = append(runs, last)
+	}
+	return runs
+}
+
+func (its *listSnapshot) addRun(node orderedType) {
+	key := runKey(node.getOrderTime())
+	runs := its.runs[key]
+	delimiter := node.getOrderTime().Delimiter
+	i := sort.Search(len(runs), func(i int) bool {
+		return runs[i].getOrderTime().Delimiter > delimiter
+	})
+	runs = append(runs, nil)
+	copy(runs[i+1:], runs[i:])
+	runs[i] = node
+	its.runs[key] = runs
+}
+
+// findRun returns the run including the order timestamp, and the offset of it in the run.
+func (its *listSnapshot) findRun(ts *model.Timestamp) (orderedType, int, bool) {
+	runs := its.runs[runKey(ts)]
+	i := sort.Search(len(runs), func(i int) bool {
+		return runs[i].getOrderTime().Delimiter > ts.Delimiter
+	}) - 1
+	if i < 0 {
+		return nil, 0, false
+	}
+	offset := int(ts.Delimiter - runs[i].getOrderTime().Delimiter)
+	if offset >= runs[i].length() {
+		return nil, 0, false
+	}
+	return runs[i], offset, true
+}
+
+// insertNodeNext links the node next to the target, and adds it to the runs and the index.
+func (its *listSnapshot) insertNodeNext(target orderedType, node orderedType) {
+	target.insertNext(node)
+	its.addRun(node)
+	its.index.insertNext(target, node)
+}
+
+// splitNode splits the run at the offset, and returns the latter part.
+func (its *listSnapshot) splitNode(node orderedType, offset int) orderedType {
+	latter := node.split(offset)
+	its.addRun(latter)
+	its.index.refresh(node)
+	its.index.insertNext(node, latter)
+	return latter
+}
+
+// cutNode splits the run if needed, and returns the run of numOfNodes timedTypes from the offset.
+func (its *listSnapshot) cutNode(node orderedType, offset int, numOfNodes int) orderedType {
+	if offset > 0 {
+		node = its.splitNode(node, offset)
+	}
+	if node.length() > numOfNodes {
+		its.splitNode(node, numOfNodes)
+	}
+	return node
+}
+
+// appendInOrder links the timedType of the order timestamp after the last node, which is extended if it can be.
+func (its *listSnapshot) appendInOrder(last orderedType, tt timedType, o *model.Timestamp) orderedType {
+	if last != its.head && last.isTomb() == tt.isTomb() && succeeds(last.getOrderTimeAt(last.length()-1), o) {
+		last.appendTimedType(tt)
+		its.index.refresh(last)
+		return last
+	}
+	node := &orderedNode{
+		timedType: tt,
+		O:         o,
+	}
+	its.insertNodeNext(last, node)
+	return node
+}
+
+// nextLive returns the live timedType following the one at the offset of the node.
+func nextLive(node orderedType, offset int) (orderedType, int) {
+	if offset+1 < node.length() && !node.isTomb() {
+		return node, offset + 1
+	}
+	return node.getNextLive(), 0
 }
 
 func (its *listSnapshot) insertRemote(
@@ -222,26 +332,29 @@ func (its *listSnapshot) insertRemoteWithTimedTypes(
 	pos *model.Timestamp,
 	tts ...timedType,
 ) errors.OrdaError {
-	if target, ok := its.Map[pos.Hash()]; ok {
-		// A -> T -> B, target: T, N: new one
-		for _, tt := range tts {
-			nextTarget := target.getNext()                                                 // nextTarget: B
-			for nextTarget != nil && nextTarget.getOrderTime().Compare(tt.getTime()) > 0 { // B is newer, go to next.
-				target = target.getNext()
-				nextTarget = nextTarget.getNext()
-			}
-			newNode := &orderedNode{ // N
-				timedType: tt,
-				O:         tt.getTime(),
-			}
-			target.insertNext(newNode) // T <--> N <--> B
-			its.Map[newNode.hash()] = newNode
-			its.size++
-			target = newNode // N => T
-		}
+	target, offset, ok := its.findRun(pos)
+	if !ok {
+		return errors.DatatypeNoTarget.New(its.L(), pos.Hash())
+	}
+	if len(tts) == 0 {
 		return nil
 	}
-	return errors.DatatypeNoTarget.New(its.L(), pos.Hash())
+	if offset+1 < target.length() { // the run is split only when inserted inside
+		its.splitNode(target, offset+1)
+	}
+	// A -> T -> B, target: T, N: new one
+	// since the timedTypes of a run have the same lamport, it is enough to compare the first ones
+	nextTarget := target.getNext()                                                     // nextTarget: B
+	for nextTarget != nil && nextTarget.getOrderTime().Compare(tts[0].getTime()) > 0 { // B is newer, go to next.
+		target = nextTarget
+		nextTarget = nextTarget.getNext()
+	}
+	for _, newNode := range newRuns(tts) { // N
+		its.insertNodeNext(target, newNode) // T <--> N <--> B
+		its.size += newNode.length()
+		target = newNode // N => T
+	}
+	return nil
 }
 
 func (its *listSnapshot) insertLocal(
@@ -260,19 +373,19 @@ func (its *listSnapshot) insertLocalWithTimedTypes(
 	pos int,
 	tts ...timedType,
 ) (*model.Timestamp, []interface{}) {
-	target := its.findOrderedTypeForInsert(pos)
+	target, offset := its.findOrderedTypeForInsert(pos)
+	targetTs := target.getOrderTimeAt(offset)
+	if offset+1 < target.length() {
+		its.splitNode(target, offset+1)
+	}
+	for _, newNode := range newRuns(tts) {
+		its.insertNodeNext(target, newNode)
+		its.size += newNode.length()
+		target = newNode
+	}
 	var inserted []interface{}
-	targetTs := target.getOrderTime()
 	for _, tt := range tts {
-		newNode := &orderedNode{
-			timedType: tt,
-			O:         tt.getTime(),
-		}
-		target.insertNext(newNode)
-		its.Map[newNode.hash()] = newNode
 		inserted = append(inserted, tt.getValue())
-		its.size++
-		target = newNode
 	}
 	return targetTs, inserted
 }
@@ -282,15 +395,16 @@ func (its *listSnapshot) updateLocal(
 	ts *model.Timestamp,
 	values []interface{},
 ) ([]*model.Timestamp, []interface{}, errors.OrdaError) {
-	target := its.findOrderedType(pos)
+	target, offset := its.findOrderedType(pos)
 	var updatedValues []interface{}
 	var updatedTargets []*model.Timestamp
 	for _, v := range values {
-		updatedTargets = append(updatedTargets, target.getOrderTime())
-		updatedValues = append(updatedValues, target.getValue())
-		target.setValue(v)
-		target.setTime(ts.GetAndNextDelimiter())
-		target = target.getNextLive()
+		tt := target.getTimedTypeAt(offset)
+		updatedTargets = append(updatedTargets, target.getOrderTimeAt(offset))
+		updatedValues = append(updatedValues, tt.getValue())
+		tt.setValue(v)
+		tt.setTime(ts.GetAndNextDelimiter())
+		target, offset = nextLive(target, offset)
 	}
 	return updatedTargets, updatedValues, nil
 }
@@ -304,15 +418,16 @@ func (its *listSnapshot) updateRemote(
 	errs := &errors.MultipleOrdaErrors{}
 	for i, t := range targets {
 		thisTS := ts.GetAndNextDelimiter()
-		if node, ok := its.Map[t.Hash()]; ok {
+		if node, offset, ok := its.findRun(t); ok {
+			tt := node.getTimedTypeAt(offset)
 			// tombstone is not recovered.
-			if node.isTomb() {
+			if tt.isTomb() {
 				continue
 			}
-			if node.getTime() == nil || node.getTime().Compare(thisTS) < 0 {
-				updated = append(updated, node.getValue())
-				node.setValue(values[i])
-				node.setTime(thisTS)
+			if tt.getTime() == nil || tt.getTime().Compare(thisTS) < 0 {
+				updated = append(updated, tt.getValue())
+				tt.setValue(values[i])
+				tt.setTime(thisTS)
 			}
 		} else {
 			_ = errs.Append(errors.DatatypeNoTarget.New(its.L(), t.ToString()))
@@ -326,18 +441,25 @@ func (its *listSnapshot) deleteLocal(
 	numOfNodes int,
 	ts *model.Timestamp,
 ) ([]*model.Timestamp, []timedType, []types.JSONValue) {
-	target := its.findOrderedType(pos)
+	target, offset := its.findOrderedType(pos)
 	var delTargets []*model.Timestamp
 	var delTimedTypes []timedType
 	var delValues []types.JSONValue
-	for i := 0; i < numOfNodes; i++ {
-		delTimedTypes = append(delTimedTypes, target.getTimedType())
-		delTargets = append(delTargets, target.getOrderTime())
-		delValues = append(delValues, target.getValue())
-		// targets should be deleted with different timestamps because they can be inserted into Cemetery in Document
-		target.makeTomb(ts.GetAndNextDelimiter())
-		its.size--
-		target = target.getNextLive()
+	for numOfNodes > 0 {
+		// the deleted part of a run is split from it, so that all the timedTypes of a run are live or tombstones.
+		target = its.cutNode(target, offset, numOfNodes)
+		for i := 0; i < target.length(); i++ {
+			tt := target.getTimedTypeAt(i)
+			delTimedTypes = append(delTimedTypes, tt)
+			delTargets = append(delTargets, target.getOrderTimeAt(i))
+			delValues = append(delValues, tt.getValue())
+			// targets should be deleted with different timestamps because they can be inserted into Cemetery in Document
+			tt.makeTomb(ts.GetAndNextDelimiter())
+		}
+		its.index.refresh(target)
+		its.size -= target.length()
+		numOfNodes -= target.length()
+		target, offset = target.getNextLive(), 0
 	}
 	return delTargets, delTimedTypes, delValues
 }
@@ -348,26 +470,98 @@ func (its *listSnapshot) deleteRemote(
 ) ([]timedType, errors.OrdaError) {
 	errs := &errors.MultipleOrdaErrors{}
 	var deleted []timedType
-	for _, t := range targets {
-		thisTS := ts.GetAndNextDelimiter()
-		if node, ok := its.Map[t.Hash()]; ok {
-			if !node.isTomb() { // if not tombstone
-				// A node should be deleted even if it has been updated by any update operation(s).
-				node.makeTomb(thisTS)
-				deleted = append(deleted, node.getTimedType())
-				its.size--
-			} else { // if tombstone,
-				if node.getTime().Compare(thisTS) < 0 {
-					node.makeTomb(thisTS)
-				}
+	for i := 0; i < len(targets); {
+		node, offset, ok := its.findRun(targets[i])
+		if !ok {
+			_ = errs.Append(errors.DatatypeNoTarget.New(its.L(), targets[i].ToString()))
+			ts.GetAndNextDelimiter()
+			i++
+			continue
+		}
+		if node.isTomb() { // if tombstone,
+			if thisTS := ts.GetAndNextDelimiter(); node.getTimedTypeAt(offset).getTime().Compare(thisTS) < 0 {
+				node.getTimedTypeAt(offset).makeTomb(thisTS)
 			}
-		} else {
-			_ = errs.Append(errors.DatatypeNoTarget.New(its.L(), t.ToString()))
+			i++
+			continue
+		}
+		// the successive targets in the run are deleted together.
+		n := 1
+		for i+n < len(targets) && offset+n < node.length() && succeeds(targets[i+n-1], targets[i+n]) {
+			n++
+		}
+		node = its.cutNode(node, offset, n)
+		for j := 0; j < n; j++ {
+			// A node should be deleted even if it has been updated by any update operation(s).
+			tt := node.getTimedTypeAt(j)
+			tt.makeTomb(ts.GetAndNextDelimiter())
+			deleted = append(deleted, tt)
 		}
+		its.index.refresh(node)
+		its.size -= n
+		i += n
 	}
 	return deleted, errs.Return()
 }
 
+// PurgeTombstones removes the tombstones deleted before the stable timestamp.
+func (its *listSnapshot) PurgeTombstones(stable *model.Timestamp) int {
+	return its.purge(stable, nil)
+}
+
+// purge unlinks the runs of tombstones deleted before the stable timestamp, and calls bury with each of them.
+// A run is kept if the node following it is not older than the stable timestamp; otherwise, an insert to come
+// could skip the node instead of stopping at the run, because it is newer than the run and older than the node.
+func (its *listSnapshot) purge(stable *model.Timestamp, bury func(tt timedType)) int {
+	tail := its.head
+	for n := tail.getNext(); n != nil; n = n.getNext() {
+		tail = n
+	}
+	purged := 0
+	followedByStable := true
+	for n := tail; n != its.head; n = n.getPrev() {
+		if !followedByStable || !n.isTomb() || !isStableRun(n, stable) {
+			followedByStable = n.getOrderTime().Compare(stable) < 0
+			continue
+		}
+		prev, next := n.getPrev(), n.getNext()
+		prev.setNext(next)
+		if next != nil {
+			next.setPrev(prev)
+		}
+		for i := 0; i < n.length(); i++ {
+			if bury != nil {
+				bury(n.getTimedTypeAt(i))
+			}
+		}
+		purged += n.length()
+	}
+	if purged > 0 {
+		its.reindex()
+	}
+	return purged
+}
+
+func isStableRun(n orderedType, stable *model.Timestamp) bool {
+	for i := 0; i < n.length(); i++ {
+		if !isStable(n.getTimedTypeAt(i).getTime(), stable) {
+			return false
+		}
+	}
+	return true
+}
+
+// reindex rebuilds the runs and the index from the linked nodes.
+func (its *listSnapshot) reindex() {
+	its.runs = make(map[string][]orderedType)
+	its.index = newOrderedIndex(its.head)
+	its.addRun(its.head)
+	for prev, n := its.head, its.head.getNext(); n != nil; prev, n = n, n.getNext() {
+		its.addRun(n)
+		its.index.insertNext(prev, n)
+	}
+}
+
 // //////////////////////////////////////////////////////////////////////
 // For getting / finding / retrieving
 // //////////////////////////////////////////////////////////////////////
@@ -379,15 +573,12 @@ func (its *listSnapshot) deleteRemote(
 // pos : 1 => n1
 // pos : 2 => n2
 // pos : 3 => n3
-func (its *listSnapshot) retrieve(pos int) orderedType {
-	ret := its.head
-	for i := 1; i <= pos; i++ {
-		ret = ret.getNextLive()
-		if ret == nil {
-			return nil
-		}
+// It returns the run and the offset in it, which are found through the index in O(log n), regardless of tombstones.
+func (its *listSnapshot) retrieve(pos int) (orderedType, int) {
+	if pos == 0 {
+		return its.head, 0
 	}
-	return ret
+	return its.index.findLive(pos)
 }
 
 func (its *listSnapshot) validateGetRange(pos int, numOfNodes int) errors.OrdaError {
@@ -425,26 +616,26 @@ func (its *listSnapshot) validateGetPosition(pos int) errors.OrdaError {
 	return nil
 }
 
-func (its *listSnapshot) findOrderedType(pos int) orderedType {
+func (its *listSnapshot) findOrderedType(pos int) (orderedType, int) {
 	return its.retrieve(pos + 1)
 }
 
 // findOrderedTypeForInsert finds a place related to Insert
-func (its *listSnapshot) findOrderedTypeForInsert(pos int) orderedType {
+func (its *listSnapshot) findOrderedTypeForInsert(pos int) (orderedType, int) {
 	return its.retrieve(pos)
 }
 
 func (its *listSnapshot) findTimedType(pos int) timedType {
-	o := its.findOrderedType(pos)
-	return o.getTimedType()
+	o, offset := its.findOrderedType(pos)
+	return o.getTimedTypeAt(offset)
 }
 
 func (its *listSnapshot) findManyTimedTypes(pos int, numOfNodes int) []timedType {
-	target := its.findOrderedType(pos)
+	target, offset := its.findOrderedType(pos)
 	var ret []timedType
 	for i := 1; i <= numOfNodes; i++ {
-		ret = append(ret, target.getTimedType())
-		target = target.getNextLive()
+		ret = append(ret, target.getTimedTypeAt(offset))
+		target, offset = nextLive(target, offset)
 	}
 	return ret
 }
@@ -455,36 +646,49 @@ func (its *listSnapshot) findValue(pos int) interface{} {
 }
 
 func (its *listSnapshot) findManyValues(pos int, numOfNodes int) []interface{} {
-	target := its.findOrderedType(pos)
+	target, offset := its.findOrderedType(pos)
 	var ret []interface{}
 	for i := 1; i <= numOfNodes; i++ {
-		ret = append(ret, target.getValue())
-		target = target.getNextLive()
+		ret = append(ret, target.getTimedTypeAt(offset).getValue())
+		target, offset = nextLive(target, offset)
 	}
 	return ret
 }
 
+// forEachTimedType calls f with every timedType including tombstones in order, and its order timestamp.
+func (its *listSnapshot) forEachTimedType(f func(o *model.Timestamp, tt timedType)) {
+	for n := its.head.getNext(); n != nil; n = n.getNext() {
+		for i := 0; i < n.length(); i++ {
+			f(n.getOrderTimeAt(i), n.getTimedTypeAt(i))
+		}
+	}
+}
+
+// forEachLive calls f with every live timedType in order.
+func (its *listSnapshot) forEachLive(f func(tt timedType)) {
+	for n := its.head.getNextLive(); n != nil; n = n.getNextLive() {
+		for i := 0; i < n.length(); i++ {
+			f(n.getTimedTypeAt(i))
+		}
+	}
+}
+
 func (its *listSnapshot) String() string {
 	sb := strings.Builder{}
 	_, _ = fmt.Fprintf(&sb, "(SIZE:%d) HEAD =>", its.size)
-	n := its.head.getNext()
-	for n != nil {
-		sb.WriteString(n.String())
-		n = n.getNext()
-		if n != nil {
-			sb.WriteString(" => ")
-		}
-	}
+	var nodes []string
+	its.forEachTimedType(func(o *model.Timestamp, tt timedType) {
+		nodes = append(nodes, tt.String())
+	})
+	sb.WriteString(strings.Join(nodes, " => "))
 	return sb.String()
 }
 
 func (its *listSnapshot) ToJSON() interface{} {
 	var l = make([]interface{}, 0)
-	n := its.head.getNextLive()
-	for n != nil {
-		l = append(l, n.getValue())
-		n = n.getNextLive()
-	}
+	its.forEachLive(func(tt timedType) {
+		l = append(l, tt.getValue())
+	})
 	return l
 }
 
@@ -507,16 +711,20 @@ type marshaledList struct {
 	Size  int
 }
 
-func (its *listSnapshot) MarshalJSON() ([]byte, error) {
-	forMarshal := marshaledList{
+func (its *listSnapshot) marshal() *marshaledList {
+	forMarshal := &marshaledList{
 		Size: its.size,
 	}
 	n := its.head.getNext()
 	for n != nil {
-		forMarshal.Nodes = append(forMarshal.Nodes, n.marshal())
+		forMarshal.Nodes = append(forMarshal.Nodes, n.marshal()...)
 		n = n.getNext()
 	}
-	return json.Marshal(forMarshal)
+	return forMarshal
+}
+
+func (its *listSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(its.marshal())
 }
 
 func (its *listSnapshot) UnmarshalJSON(bytes []byte) error {
@@ -525,38 +733,36 @@ func (its *listSnapshot) UnmarshalJSON(bytes []byte) error {
 	if err != nil {
 		return err
 	}
-	its.head = newHead()
-	its.size = forUnmarshal.Size
-	its.Map = make(map[string]orderedType)
-	its.Map[its.head.hash()] = its.head
-
-	prev := its.head
-	if forUnmarshal.Nodes != nil {
-		for _, n := range forUnmarshal.Nodes {
-			node := n.unmarshalAsNode()
-			prev.insertNext(node)
-			prev = node
-			its.Map[node.getOrderTime().Hash()] = node
-		}
-	}
+	its.unmarshal(&forUnmarshal)
 	return nil
 }
 
-func (its *marshaledNode) unmarshalAsNode() orderedType {
-	return &orderedNode{
-		timedType: newTimedNode(its.V, its.T),
-		O:         its.O,
-		next:      nil,
-		prev:      nil,
+// unmarshal restores the nodes, which are stored in runs again.
+func (its *listSnapshot) unmarshal(forUnmarshal *marshaledList) {
+	its.head = newHead()
+	its.size = forUnmarshal.Size
+	its.runs = make(map[string][]orderedType)
+	its.index = newOrderedIndex(its.head)
+	its.addRun(its.head)
+
+	last := its.head
+	for _, n := range forUnmarshal.Nodes {
+		last = its.appendInOrder(last, newTimedNode(n.V, n.T), n.O)
 	}
 }
 
-func (its *orderedNode) marshal() *marshaledNode {
-	return &marshaledNode{
-		V: its.getValue(),
-		T: its.getTime(),
-		O: its.getOrderTime(),
+// marshal returns the marshaledNodes of all the timedTypes of the run.
+func (its *orderedNode) marshal() []*marshaledNode {
+	var marshaled []*marshaledNode
+	for i := 0; i < its.length(); i++ {
+		tt := its.getTimedTypeAt(i)
+		marshaled = append(marshaled, &marshaledNode{
+			V: tt.getValue(),
+			T: tt.getTime(),
+			O: its.getOrderTimeAt(i),
+		})
 	}
+	return marshaled
 }
 
 func (its *orderedNode) UnmarshalJSON(bytes []byte) error {
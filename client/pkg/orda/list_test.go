@@ -7,6 +7,7 @@ import (
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/testonly"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -17,11 +18,12 @@ func initList(t *testing.T, list *listSnapshot, opID *model.OperationID) {
 	target, _ := list.insertLocal(0, ts.Clone(), "x", "y")
 	require.Equal(t, list.head.getOrderTime(), target)
 
-	o1 := list.findOrderedType(0)
-	o2 := list.findOrderedType(1)
-	require.Equal(t, ts.GetAndNextDelimiter(), o1.getOrderTime())
-	require.Equal(t, ts.GetAndNextDelimiter(), o2.getOrderTime())
-	err := list.insertRemote(o2.getOrderTime(), ts.GetAndNextDelimiter(), "a", "b")
+	o1, offset1 := list.findOrderedType(0)
+	o2, offset2 := list.findOrderedType(1)
+	require.Equal(t, ts.GetAndNextDelimiter(), o1.getOrderTimeAt(offset1))
+	require.Equal(t, ts.GetAndNextDelimiter(), o2.getOrderTimeAt(offset2))
+	require.Equal(t, o1, o2) // inserted in a run
+	err := list.insertRemote(o2.getOrderTimeAt(offset2), ts.GetAndNextDelimiter(), "a", "b")
 	require.NoError(t, err)
 	log.Logger.Infof("%v", testonly.Marshal(t, list.ToJSON()))
 }
@@ -48,25 +50,26 @@ func listMarshalTest(t *testing.T, original *listSnapshot) {
 	require.NoError(t, err2)
 	log.Logger.Infof("%v", string(snap2))
 	require.Equal(t, string(snap1), string(snap2))
-	require.Equal(t, len(original.Map), len(clone.Map))
+	require.Equal(t, original.size, clone.size)
 
-	e1 := original.head
-	e2 := clone.head
-
-	for e1 != nil && e2 != nil {
-		require.Equal(t, e1.getValue(), e2.getValue())
-		require.Equal(t, e1.getOrderTime(), e2.getOrderTime())
-		require.Equal(t, e1.getTime(), e2.getTime())
-		if e1.getPrev() != nil && e2.getPrev() != nil {
-			require.Equal(t, e1.getPrev().getOrderTime(), e2.getPrev().getOrderTime())
-		}
-		if e1.getNext() != nil && e2.getNext() != nil {
-			require.Equal(t, e1.getNext().getOrderTime(), e2.getNext().getOrderTime())
-		}
-		e1 = e1.getNext()
-		e2 = e2.getNext()
+	// the runs can be joined by unmarshalling, so the timedTypes are compared in order
+	var o1, o2 []*model.Timestamp
+	var tt1, tt2 []timedType
+	original.forEachTimedType(func(o *model.Timestamp, tt timedType) {
+		o1, tt1 = append(o1, o), append(tt1, tt)
+	})
+	clone.forEachTimedType(func(o *model.Timestamp, tt timedType) {
+		o2, tt2 = append(o2, o), append(tt2, tt)
+	})
+	require.Equal(t, o1, o2)
+	require.Equal(t, len(tt1), len(tt2))
+	for i := range tt1 {
+		require.Equal(t, tt1[i].getValue(), tt2[i].getValue())
+		require.Equal(t, tt1[i].getTime(), tt2[i].getTime())
+	}
+	for pos := 0; pos < clone.size; pos++ {
+		require.Equal(t, original.findValue(pos), clone.findValue(pos))
 	}
-
 }
 
 func TestList(t *testing.T) {
@@ -115,7 +118,7 @@ func TestList(t *testing.T) {
 		initList(t, list, opID)
 
 		// deleteLocal the first "x"
-		e1 := list.findOrderedType(0)
+		e1, _ := list.findOrderedType(0)
 		ts, _, v := list.deleteLocal(0, 1, opID.Next().GetTimestamp())
 		require.Equal(t, "x", v[0])
 		require.Equal(t, ts[0], e1.getOrderTime())
@@ -159,7 +162,7 @@ func TestList(t *testing.T) {
 		require.Equal(t, []interface{}{"x", "y", "a"}, updV)
 		log.Logger.Infof("%v", testonly.Marshal(t, list.ToJSON()))
 
-		e1 := list.findOrderedType(0)
+		e1, _ := list.findOrderedType(0)
 		ts1 := e1.getTime()
 		require.NotEqual(t, e1.getOrderTime(), e1.getTime())
 
@@ -306,6 +309,120 @@ func TestList(t *testing.T) {
 		require.Equal(t, snap1, snap2)
 	})
 
+	t.Run("Can find positions through the index", func(t *testing.T) {
+		opID := model.NewOperationID()
+		base := testonly.NewBase(t.Name(), model.TypeOfDatatype_LIST)
+		list := newListSnapshot(base)
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			switch pos := r.Intn(list.size + 1); {
+			case list.size > 0 && i%3 == 0:
+				list.deleteLocal(pos%list.size, 1, opID.Next().GetTimestamp())
+			case i%5 == 0:
+				node, offset := list.retrieve(pos)
+				target := node.getOrderTimeAt(offset)
+				require.NoError(t, list.insertRemote(target, opID.Next().GetTimestamp(), fmt.Sprint(i), fmt.Sprint(i+1)))
+			default:
+				list.insertLocal(pos, opID.Next().GetTimestamp(), fmt.Sprint(i))
+			}
+		}
+		// the live timedTypes found through the index are the same as those by walking the linked nodes
+		var live []timedType
+		list.forEachLive(func(tt timedType) {
+			live = append(live, tt)
+		})
+		require.Len(t, live, list.size)
+		for pos, tt := range live {
+			require.Equal(t, tt, list.findTimedType(pos))
+		}
+		node, _ := list.retrieve(list.size + 1)
+		require.Nil(t, node)
+		listIntegrityTest(t, list)
+		listMarshalTest(t, list)
+	})
+
+	t.Run("Can store bulk inserts in runs", func(t *testing.T) {
+		opID := model.NewOperationID()
+		base := testonly.NewBase(t.Name(), model.TypeOfDatatype_LIST)
+		list := newListSnapshot(base)
+		numOfRuns := func() int {
+			n := 0
+			for node := list.head.getNext(); node != nil; node = node.getNext() {
+				n++
+			}
+			return n
+		}
+		values := make([]interface{}, 1000)
+		for i := range values {
+			values[i] = fmt.Sprint(i)
+		}
+		list.insertLocal(0, opID.Next().GetTimestamp(), values...)
+		require.Equal(t, 1, numOfRuns())
+
+		// reading and updating do not split the run
+		updated, _, err := list.updateLocal(10, opID.Next().GetTimestamp(), []interface{}{"u10", "u11"})
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"u10", "u11"}, list.findManyValues(10, 2))
+		require.Equal(t, 1, numOfRuns())
+
+		// a remote insert inside the run splits it
+		require.NoError(t, list.insertRemote(updated[1], opID.Next().GetTimestamp(), "r1", "r2"))
+		require.Equal(t, 3, numOfRuns())
+		require.Equal(t, []interface{}{"u11", "r1", "r2", "12"}, list.findManyValues(11, 4))
+
+		// a remote delete inside the run splits it, where the successive targets are deleted together
+		node, offset := list.findOrderedType(500)
+		targets := []*model.Timestamp{node.getOrderTimeAt(offset), node.getOrderTimeAt(offset + 1)}
+		deleted, errs := list.deleteRemote(targets, opID.Next().GetTimestamp())
+		require.NoError(t, errs)
+		require.Len(t, deleted, 2)
+		require.Equal(t, 5, numOfRuns())
+		require.Equal(t, 1000, list.Size())
+		require.Equal(t, []interface{}{"497", "500"}, list.findManyValues(499, 2))
+
+		// a local delete across runs splits only the ends
+		_, _, delValues := list.deleteLocal(5, 10, opID.Next().GetTimestamp())
+		require.Len(t, delValues, 10)
+		require.Equal(t, 7, numOfRuns())
+		require.Equal(t, 990, list.Size())
+
+		listIntegrityTest(t, list)
+		listMarshalTest(t, list)
+	})
+
+	t.Run("Can purge stable tombstones", func(t *testing.T) {
+		opID := model.NewOperationID()
+		base := testonly.NewBase(t.Name(), model.TypeOfDatatype_LIST)
+		list := newListSnapshot(base)
+		list.insertLocal(0, opID.Next().GetTimestamp(), "a", "b", "c")
+		node, offset := list.findOrderedType(1)
+		b := node.getOrderTimeAt(offset)
+		list.deleteLocal(1, 1, opID.Next().GetTimestamp())
+		list.deleteLocal(0, 1, opID.Next().GetTimestamp())
+		// z is inserted after the tombstone b by a client which has not seen the deletion of b
+		z := &model.Timestamp{Lamport: 10, CUID: "other"}
+		require.NoError(t, list.insertRemote(b, z, "z"))
+		require.Equal(t, []interface{}{"z", "c"}, list.ToJSON())
+
+		// the tombstone a is purged, but b is kept because z following it is not stable
+		require.Equal(t, 1, list.PurgeTombstones(&model.Timestamp{Lamport: 5}))
+		_, _, ok := list.findRun(b)
+		require.True(t, ok)
+		require.Equal(t, []interface{}{"z", "c"}, list.ToJSON())
+
+		require.Equal(t, 1, list.PurgeTombstones(&model.Timestamp{Lamport: 11}))
+		_, _, ok = list.findRun(b)
+		require.False(t, ok)
+		require.Equal(t, []interface{}{"z", "c"}, list.ToJSON())
+		require.Equal(t, "c", list.findValue(1))
+
+		// an insert to come, newer than the stable timestamp, is placed as if the tombstones remained
+		require.NoError(t, list.insertRemote(list.head.getOrderTime(), &model.Timestamp{Lamport: 12, CUID: "other"}, "y"))
+		require.Equal(t, []interface{}{"y", "z", "c"}, list.ToJSON())
+		listIntegrityTest(t, list)
+		listMarshalTest(t, list)
+	})
+
 	t.Run("Can run transactions", func(t *testing.T) {
 		tw := testonly.NewTestWire(true)
 		list1, _ := newList(testonly.NewBase("key1", model.TypeOfDatatype_LIST), tw, nil)
@@ -333,3 +450,50 @@ func TestList(t *testing.T) {
 		require.Equal(t, []interface{}{"a", "b"}, gets2)
 	})
 }
+
+const numOfBenchmarkNodes = 100000
+
+// newBenchmarkList creates a list of numOfBenchmarkNodes live nodes interleaved with as many tombstones.
+func newBenchmarkList(b *testing.B) (*listSnapshot, *model.OperationID) {
+	opID := model.NewOperationID()
+	list := newListSnapshot(testonly.NewBase(b.Name(), model.TypeOfDatatype_LIST))
+	values := make([]interface{}, 2*numOfBenchmarkNodes)
+	for i := range values {
+		values[i] = i
+	}
+	list.insertLocal(0, opID.Next().GetTimestamp(), values...)
+	for pos := 0; pos < numOfBenchmarkNodes; pos++ {
+		list.deleteLocal(pos, 1, opID.Next().GetTimestamp())
+	}
+	b.ResetTimer()
+	return list, opID
+}
+
+func BenchmarkListGet(b *testing.B) {
+	list, _ := newBenchmarkList(b)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		list.findValue(r.Intn(list.size))
+	}
+}
+
+func BenchmarkListInsert(b *testing.B) {
+	list, opID := newBenchmarkList(b)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		list.insertLocal(r.Intn(list.size+1), opID.Next().GetTimestamp(), i)
+	}
+}
+
+func BenchmarkListDelete(b *testing.B) {
+	list, opID := newBenchmarkList(b)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		if list.size == 0 {
+			b.StopTimer()
+			list, opID = newBenchmarkList(b)
+			b.StartTimer()
+		}
+		list.deleteLocal(r.Intn(list.size), 1, opID.Next().GetTimestamp())
+	}
+}
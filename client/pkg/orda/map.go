@@ -90,9 +90,15 @@ func (its *ordaMap) Put(key string, value interface{}) (interface{}, errors.Orda
 }
 
 func (its *ordaMap) Get(key string) interface{} {
+	defer its.rLock()()
 	return its.snapshot().get(key)
 }
 
+func (its *ordaMap) ToJSON() interface{} {
+	defer its.rLock()()
+	return its.SnapshotDatatype.ToJSON()
+}
+
 func (its *ordaMap) Remove(key string) (interface{}, errors.OrdaError) {
 	if key == "" {
 		return nil, errors.DatatypeIllegalParameters.New(its.L(), "empty key is not allowed")
@@ -102,6 +108,7 @@ func (its *ordaMap) Remove(key string) (interface{}, errors.OrdaError) {
 }
 
 func (its *ordaMap) Size() int {
+	defer its.rLock()()
 	return its.snapshot().size()
 }
 
@@ -235,6 +242,18 @@ func (its *mapSnapshot) removeRemoteWithTimedType(
 	return nil, nil, errors.DatatypeNoTarget.New(its.L(), key)
 }
 
+// PurgeTombstones removes the keys removed before the stable timestamp.
+func (its *mapSnapshot) PurgeTombstones(stable *model.Timestamp) int {
+	purged := 0
+	for k, tt := range its.Map {
+		if tt.isTomb() && isStable(tt.getTime(), stable) {
+			delete(its.Map, k)
+			purged++
+		}
+	}
+	return purged
+}
+
 func (its *mapSnapshot) size() int {
 	return its.Size
 }
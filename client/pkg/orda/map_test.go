@@ -126,4 +126,24 @@ func TestHashMap(t *testing.T) {
 		require.Equal(t, string(snap1), string(snap2))
 		require.Nil(t, clone.getFromMap("key1").getValue())
 	})
+
+	t.Run("Can purge stable tombstones of mapSnapshot", func(t *testing.T) {
+		opID := model.NewOperationID()
+		snap := newMapSnapshot(testonly.NewBase("test", model.TypeOfDatatype_MAP))
+		_, _ = snap.putCommon("key1", "v1", opID.Next().GetTimestamp())
+		_, _ = snap.putCommon("key2", "v2", opID.Next().GetTimestamp())
+		_, _ = snap.removeLocal("key1", opID.Next().GetTimestamp())
+		_, _ = snap.removeLocal("key2", opID.Next().GetTimestamp())
+
+		require.Equal(t, 1, snap.PurgeTombstones(&model.Timestamp{Lamport: 4}))
+		require.Nil(t, snap.getFromMap("key1"))
+		require.NotNil(t, snap.getFromMap("key2"))
+		require.Equal(t, 1, snap.PurgeTombstones(&model.Timestamp{Lamport: 5}))
+		require.Empty(t, snap.Map)
+
+		// a key purged can be put again
+		_, _ = snap.putCommon("key1", "v3", opID.Next().GetTimestamp())
+		require.Equal(t, `{"key1":"v3"}`, testonly.Marshal(t, snap.ToJSON()))
+		require.Equal(t, 1, snap.size())
+	})
 }
@@ -1,9 +1,14 @@
 package orda
 
 import (
+	"fmt"
+
 	"github.com/orda-io/orda/client/pkg/model"
 )
 
+// orderedType is a run of the timedTypes inserted in sequence by an operation. The methods of timedType are for
+// the first one, and the order timestamps of the following ones succeed that of the first by the delimiter.
+// All the timedTypes of a run are either live or tombstones.
 type orderedType interface {
 	timedType
 	getOrderTime() *model.Timestamp
@@ -14,17 +19,35 @@ type orderedType interface {
 	setNext(n orderedType)
 	insertNext(n orderedType)
 	getNextLive() orderedType
-	getTimedType() timedType
-	setTimedType(tt timedType)
+	length() int
+	getOrderTimeAt(offset int) *model.Timestamp
+	getTimedTypeAt(offset int) timedType
+	setTimedTypeAt(offset int, tt timedType)
+	appendTimedType(tt timedType)
+	split(offset int) orderedType
 	hash() string
-	marshal() *marshaledNode
+	marshal() []*marshaledNode
+	getTreeNode() *treeNode
 }
 
 type orderedNode struct {
 	timedType
 	O    *model.Timestamp
+	rest []timedType // the timedTypes following the first one in the run
 	prev orderedType
 	next orderedType
+	tree treeNode
+}
+
+// succeeds examines if the next timestamp is the one following the prev by the delimiter.
+func succeeds(prev, next *model.Timestamp) bool {
+	return prev.Era == next.Era && prev.Lamport == next.Lamport && prev.CUID == next.CUID &&
+		prev.Delimiter+1 == next.Delimiter
+}
+
+// runKey returns the key of the runs inserted by the operation of the timestamp.
+func runKey(ts *model.Timestamp) string {
+	return fmt.Sprintf("%d:%d:%s", ts.Era, ts.Lamport, ts.CUID)
 }
 
 func newHead() *orderedNode {
@@ -85,10 +108,50 @@ func (its *orderedNode) getNextLive() orderedType {
 	return nil
 }
 
-func (its *orderedNode) getTimedType() timedType {
-	return its.timedType
+func (its *orderedNode) getTreeNode() *treeNode {
+	return &its.tree
+}
+
+func (its *orderedNode) length() int {
+	return 1 + len(its.rest)
+}
+
+func (its *orderedNode) getOrderTimeAt(offset int) *model.Timestamp {
+	if offset == 0 {
+		return its.O
+	}
+	ts := its.O.Clone()
+	ts.Delimiter += uint32(offset)
+	return ts
 }
 
-func (its *orderedNode) setTimedType(tt timedType) {
-	its.timedType = tt
+func (its *orderedNode) getTimedTypeAt(offset int) timedType {
+	if offset == 0 {
+		return its.timedType
+	}
+	return its.rest[offset-1]
+}
+
+func (its *orderedNode) setTimedTypeAt(offset int, tt timedType) {
+	if offset == 0 {
+		its.timedType = tt
+		return
+	}
+	its.rest[offset-1] = tt
+}
+
+func (its *orderedNode) appendTimedType(tt timedType) {
+	its.rest = append(its.rest, tt)
+}
+
+// split cuts the run at the offset, and links the latter part next to it.
+func (its *orderedNode) split(offset int) orderedType {
+	latter := &orderedNode{
+		timedType: its.rest[offset-1],
+		O:         its.getOrderTimeAt(offset),
+		rest:      its.rest[offset:],
+	}
+	its.rest = its.rest[: offset-1 : offset-1]
+	its.insertNext(latter)
+	return latter
 }
@@ -0,0 +1,122 @@
+package orda
+
+import (
+	"math/rand"
+)
+
+// treeNode places an orderedType in an orderedIndex.
+type treeNode struct {
+	owner               orderedType
+	parent, left, right *treeNode
+	priority            uint32
+	live                int // the number of live timedTypes in the subtree
+}
+
+func (its *treeNode) liveOf() int {
+	if its == nil {
+		return 0
+	}
+	return its.live
+}
+
+func (its *treeNode) update() {
+	its.live = its.left.liveOf() + its.right.liveOf()
+	if !its.owner.isTomb() {
+		its.live += its.owner.length()
+	}
+}
+
+// orderedIndex is a treap of the orderedTypes of a list in their linked order, including tombstones, where each
+// subtree counts its live timedTypes. It finds the live timedType at a position, inserts a node next to another,
+// and reflects a new tombstone or split in O(log n) on average, while the order is still decided by the timestamps.
+type orderedIndex struct {
+	root *treeNode
+}
+
+func newOrderedIndex(head orderedType) *orderedIndex {
+	return &orderedIndex{root: newTreeNode(head)}
+}
+
+func newTreeNode(n orderedType) *treeNode {
+	tn := n.getTreeNode()
+	*tn = treeNode{owner: n, priority: rand.Uint32()}
+	tn.update()
+	return tn
+}
+
+// insertNext places n right after prev, which should be already in the index.
+func (its *orderedIndex) insertNext(prev orderedType, n orderedType) {
+	tn := newTreeNode(n)
+	p := prev.getTreeNode()
+	if p.right == nil {
+		p.right = tn
+	} else {
+		for p = p.right; p.left != nil; p = p.left {
+		}
+		p.left = tn
+	}
+	tn.parent = p
+	for x := p; x != nil; x = x.parent {
+		x.live += tn.live
+	}
+	for tn.parent != nil && tn.parent.priority < tn.priority {
+		its.rotateUp(tn)
+	}
+}
+
+// refresh reflects the change of n between live and tombstone, or of its length.
+func (its *orderedIndex) refresh(n orderedType) {
+	for x := n.getTreeNode(); x != nil; x = x.parent {
+		x.update()
+	}
+}
+
+// findLive returns the node and the offset in it of the pos-th live timedType counted from 1,
+// or nil if there are fewer live ones.
+func (its *orderedIndex) findLive(pos int) (orderedType, int) {
+	for x := its.root; x != nil; {
+		left := x.left.liveOf()
+		if pos <= left {
+			x = x.left
+			continue
+		}
+		pos -= left
+		if !x.owner.isTomb() {
+			if pos <= x.owner.length() {
+				return x.owner, pos - 1
+			}
+			pos -= x.owner.length()
+		}
+		x = x.right
+	}
+	return nil, 0
+}
+
+// rotateUp swaps x with its parent, keeping the linked order.
+func (its *orderedIndex) rotateUp(x *treeNode) {
+	p, g := x.parent, x.parent.parent
+	if x == p.left {
+		p.left = x.right
+		if x.right != nil {
+			x.right.parent = p
+		}
+		x.right = p
+	} else {
+		p.right = x.left
+		if x.left != nil {
+			x.left.parent = p
+		}
+		x.left = p
+	}
+	p.parent, x.parent = x, g
+	switch {
+	case g == nil:
+		its.root = x
+	case g.left == p:
+		g.left = x
+	default:
+		g.right = x
+	}
+	p.update()
+	x.update()
+}
@@ -0,0 +1,352 @@
+package orda
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/orda-io/orda/client/pkg/internal/codec"
+	"github.com/orda-io/orda/client/pkg/model"
+)
+
+// The snapshots are encoded in binary as follows, after codec.Marker and codec.SnapshotVersion:
+//   counter:  value
+//   map:      size, number of keys, and (key, T, V) sorted by key
+//   list:     size, ordered timestamps of the nodes, and their values
+//   document: number of jsonTypes, and (type, C, P, D) followed by E, (S, number of keys, (key, C)), or (S, ordered timestamps)
+// All timestamps are compact, and ordered timestamps are run-length encoded.
+
+// MarshalBinary returns the counterSnapshot encoded in binary.
+func (its *counterSnapshot) MarshalBinary() ([]byte, error) {
+	w := codec.NewSnapshotWriter()
+	w.PutVarint(int64(its.Value))
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary sets the counterSnapshot encoded by MarshalBinary.
+func (its *counterSnapshot) UnmarshalBinary(data []byte) error {
+	r, err := codec.NewSnapshotReader(data)
+	if err != nil {
+		return err
+	}
+	v, err := r.ReadVarint()
+	if err != nil {
+		return err
+	}
+	its.Value = int32(v)
+	return r.Done()
+}
+
+// MarshalBinary returns the mapSnapshot encoded in binary.
+func (its *mapSnapshot) MarshalBinary() ([]byte, error) {
+	w := codec.NewSnapshotWriter()
+	w.PutUvarint(uint64(its.Size))
+	keys := make([]string, 0, len(its.Map))
+	for k := range its.Map {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	w.PutUvarint(uint64(len(keys)))
+	for _, k := range keys {
+		tt := its.Map[k]
+		w.PutString(k)
+		w.PutCompactTimestamp(tt.getTime())
+		if err := w.PutValue(tt.getValue()); err != nil {
+			return nil, err
+		}
+	}
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary sets the mapSnapshot encoded by MarshalBinary.
+func (its *mapSnapshot) UnmarshalBinary(data []byte) error {
+	r, err := codec.NewSnapshotReader(data)
+	if err != nil {
+		return err
+	}
+	size, err := r.ReadUvarint()
+	if err != nil {
+		return err
+	}
+	n, err := r.ReadLength()
+	if err != nil {
+		return err
+	}
+	m := make(map[string]timedType, n)
+	for i := 0; i < n; i++ {
+		node := &timedNode{}
+		k, err := r.ReadString()
+		if err != nil {
+			return err
+		}
+		if node.T, err = r.ReadCompactTimestamp(); err != nil {
+			return err
+		}
+		if node.V, err = r.ReadValue(); err != nil {
+			return err
+		}
+		m[k] = node
+	}
+	its.Map, its.Size = m, int(size)
+	return r.Done()
+}
+
+// MarshalBinary returns the listSnapshot encoded in binary.
+func (its *listSnapshot) MarshalBinary() ([]byte, error) {
+	marshaled := its.marshal()
+	w := codec.NewSnapshotWriter()
+	w.PutUvarint(uint64(marshaled.Size))
+	ordered := make([]marshaledOrderedType, len(marshaled.Nodes))
+	values := make([]interface{}, len(marshaled.Nodes))
+	for i, n := range marshaled.Nodes {
+		ordered[i][0] = n.O
+		if n.T.Compare(n.O) != 0 {
+			ordered[i][1] = n.T
+		}
+		values[i] = n.V
+	}
+	putOrderedTimestamps(w, ordered)
+	if err := w.PutValues(values); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary sets the listSnapshot encoded by MarshalBinary.
+func (its *listSnapshot) UnmarshalBinary(data []byte) error {
+	r, err := codec.NewSnapshotReader(data)
+	if err != nil {
+		return err
+	}
+	size, err := r.ReadUvarint()
+	if err != nil {
+		return err
+	}
+	ordered, err := readOrderedTimestamps(r)
+	if err != nil {
+		return err
+	}
+	values, err := r.ReadValues()
+	if err != nil {
+		return err
+	}
+	if len(values) != len(ordered) {
+		return fmt.Errorf("%d values for %d nodes", len(values), len(ordered))
+	}
+	marshaled := &marshaledList{Size: int(size)}
+	for i, ot := range ordered {
+		t := ot[1]
+		if t == nil {
+			t = ot[0]
+		}
+		marshaled.Nodes = append(marshaled.Nodes, &marshaledNode{V: values[i], T: t, O: ot[0]})
+	}
+	if err := r.Done(); err != nil {
+		return err
+	}
+	its.unmarshal(marshaled)
+	return nil
+}
+
+// MarshalBinary returns the document encoded in binary.
+func (its *jsonObject) MarshalBinary() ([]byte, error) {
+	return its.marshalDocument().marshalBinary()
+}
+
+func (its *marshaledDocument) marshalBinary() ([]byte, error) {
+	w := codec.NewSnapshotWriter()
+	w.PutUvarint(uint64(len(its.NodeMap)))
+	for _, mjt := range its.NodeMap {
+		w.PutString(string(mjt.T))
+		w.PutCompactTimestamp(mjt.C)
+		w.PutCompactTimestamp(mjt.P)
+		w.PutCompactTimestamp(mjt.D)
+		switch mjt.T {
+		case marshalKeyJSONElement:
+			if err := w.PutValue(mjt.E); err != nil {
+				return nil, err
+			}
+		case marshalKeyJSONObject:
+			w.PutUvarint(uint64(mjt.O.S))
+			keys := make([]string, 0, len(mjt.O.M))
+			for k := range mjt.O.M {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			w.PutUvarint(uint64(len(keys)))
+			for _, k := range keys {
+				w.PutString(k)
+				w.PutCompactTimestamp(mjt.O.M[k])
+			}
+		case marshalKeyJSONArray:
+			w.PutUvarint(uint64(mjt.A.S))
+			putOrderedTimestamps(w, mjt.A.N)
+		}
+	}
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary sets the document encoded by MarshalBinary.
+func (its *jsonObject) UnmarshalBinary(data []byte) error {
+	r, err := codec.NewSnapshotReader(data)
+	if err != nil {
+		return err
+	}
+	n, err := r.ReadLength()
+	if err != nil {
+		return err
+	}
+	marshaled := newMarshaledDocument()
+	for i := 0; i < n; i++ {
+		mjt, err := readMarshaledJSONType(r)
+		if err != nil {
+			return err
+		}
+		marshaled.NodeMap = append(marshaled.NodeMap, mjt)
+	}
+	if err := r.Done(); err != nil {
+		return err
+	}
+	its.unmarshalDocument(marshaled)
+	return nil
+}
+
+func readMarshaledJSONType(r *codec.Reader) (*marshaledJSONType, error) {
+	typeOf, err := r.ReadString()
+	if err != nil {
+		return nil, err
+	}
+	mjt := &marshaledJSONType{T: marshalKeyJSONType(typeOf)}
+	if mjt.C, err = r.ReadCompactTimestamp(); err != nil {
+		return nil, err
+	}
+	if mjt.P, err = r.ReadCompactTimestamp(); err != nil {
+		return nil, err
+	}
+	if mjt.D, err = r.ReadCompactTimestamp(); err != nil {
+		return nil, err
+	}
+	switch mjt.T {
+	case marshalKeyJSONElement:
+		mjt.E, err = r.ReadValue()
+		return mjt, err
+	case marshalKeyJSONObject:
+		size, err := r.ReadUvarint()
+		if err != nil {
+			return nil, err
+		}
+		n, err := r.ReadLength()
+		if err != nil {
+			return nil, err
+		}
+		mjt.O = &marshaledJSONObject{M: make(map[string]*model.Timestamp, n), S: int(size)}
+		for i := 0; i < n; i++ {
+			k, err := r.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			if mjt.O.M[k], err = r.ReadCompactTimestamp(); err != nil {
+				return nil, err
+			}
+		}
+		return mjt, nil
+	case marshalKeyJSONArray:
+		size, err := r.ReadUvarint()
+		if err != nil {
+			return nil, err
+		}
+		mjt.A = &marshaledJSONArray{S: int(size)}
+		mjt.A.N, err = readOrderedTimestamps(r)
+		return mjt, err
+	}
+	return nil, fmt.Errorf("invalid type of jsonType: %s", typeOf)
+}
+
+// steps between the order timestamps of successive nodes in a run
+const (
+	stepDelimiter uint64 = iota // inserted by an operation with multiple values
+	stepLamport                 // inserted by successive operations of a client
+)
+
+// stepOf returns the step from the prev to the next timestamp, or false if the next does not succeed the prev.
+func stepOf(prev, next *model.Timestamp) (uint64, bool) {
+	if prev.Era != next.Era || prev.CUID != next.CUID {
+		return 0, false
+	}
+	if prev.Lamport == next.Lamport && prev.Delimiter+1 == next.Delimiter {
+		return stepDelimiter, true
+	}
+	if prev.Lamport+1 == next.Lamport && prev.Delimiter == next.Delimiter {
+		return stepLamport, true
+	}
+	return 0, false
+}
+
+// putOrderedTimestamps writes the timestamps of the ordered nodes in runs. Each run begins with a header of
+// its length, its step, and whether the second timestamp exists, which makes the run of a single node.
+// In a run, the order timestamps after the first one are derived from it by the step.
+func putOrderedTimestamps(w *codec.Writer, ordered []marshaledOrderedType) {
+	w.PutUvarint(uint64(len(ordered)))
+	for i := 0; i < len(ordered); {
+		first := ordered[i]
+		if first[1] != nil {
+			w.PutUvarint(1<<2 | 1)
+			w.PutCompactTimestamp(first[0])
+			w.PutCompactTimestamp(first[1])
+			i++
+			continue
+		}
+		n, step := 1, stepDelimiter
+		for j := i + 1; j < len(ordered) && ordered[j][1] == nil; j++ {
+			s, ok := stepOf(ordered[j-1][0], ordered[j][0])
+			if !ok || (n > 1 && s != step) {
+				break
+			}
+			n, step = n+1, s
+		}
+		w.PutUvarint(uint64(n)<<2 | step<<1)
+		w.PutCompactTimestamp(first[0])
+		i += n
+	}
+}
+
+func readOrderedTimestamps(r *codec.Reader) ([]marshaledOrderedType, error) {
+	total, err := r.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	var ordered []marshaledOrderedType
+	for uint64(len(ordered)) < total {
+		header, err := r.ReadUvarint()
+		if err != nil {
+			return nil, err
+		}
+		n, step, hasSecond := header>>2, (header>>1)&1, header&1 == 1
+		if n == 0 || uint64(len(ordered))+n > total {
+			return nil, fmt.Errorf("invalid run of %d nodes", n)
+		}
+		first, err := r.ReadCompactTimestamp()
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			return nil, fmt.Errorf("no timestamp for the run")
+		}
+		var second *model.Timestamp
+		if hasSecond {
+			if second, err = r.ReadCompactTimestamp(); err != nil {
+				return nil, err
+			}
+		}
+		ordered = append(ordered, marshaledOrderedType{first, second})
+		for i := uint64(1); i < n; i++ {
+			ts := &model.Timestamp{Era: first.Era, Lamport: first.Lamport, CUID: first.CUID, Delimiter: first.Delimiter}
+			if step == stepDelimiter {
+				ts.Delimiter += uint32(i)
+			} else {
+				ts.Lamport += i
+			}
+			ordered = append(ordered, marshaledOrderedType{ts})
+		}
+	}
+	return ordered, nil
+}
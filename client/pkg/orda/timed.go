@@ -19,6 +19,12 @@ type timedType interface {
 	String() string
 }
 
+// isStable examines if the deletion of the timestamp is causally stable, which means that every operation to come
+// has seen it. It compares only the Lamport, since the stable timestamp bounds the Lamports of the operations to come.
+func isStable(deleted *model.Timestamp, stable *model.Timestamp) bool {
+	return deleted.Lamport < stable.Lamport
+}
+
 type timedNode struct {
 	V types.JSONValue  `json:"v"`
 	T *model.Timestamp `json:"t"`
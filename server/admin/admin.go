@@ -6,18 +6,30 @@ import (
 	"time"
 )
 
-const ordaPatchAPICUID string = "!@#$OrdaPatchAPI"
+const (
+	ordaPatchAPICUID   string = "!@#$OrdaPatchAPI"
+	ordaRestoreAPICUID string = "!@#$OrdaRestoreAPI"
+)
 
 var administrators = map[string]string{
-	ordaPatchAPICUID: "ordaPatchAPI",
+	ordaPatchAPICUID:   "ordaPatchAPI",
+	ordaRestoreAPICUID: "ordaRestoreAPI",
 }
 
 // NewPatchClient creates a new patch client for each collection
 func NewPatchClient(collectionDoc *schema.CollectionDoc) *schema.ClientDoc {
+	return newAdminClient(ordaPatchAPICUID, collectionDoc)
+}
+
+// NewRestoreClient creates a new restore client for each collection
+func NewRestoreClient(collectionDoc *schema.CollectionDoc) *schema.ClientDoc {
+	return newAdminClient(ordaRestoreAPICUID, collectionDoc)
+}
 
-	alias := administrators[ordaPatchAPICUID]
+func newAdminClient(cuid string, collectionDoc *schema.CollectionDoc) *schema.ClientDoc {
+	alias := administrators[cuid]
 	return &schema.ClientDoc{
-		CUID:          ordaPatchAPICUID,
+		CUID:          cuid,
 		Alias:         alias,
 		CollectionNum: collectionDoc.Num,
 		Type:          int8(model.ClientType_VOLATILE),
@@ -0,0 +1,75 @@
+package boltdb
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// UpdateClient updates a clientDoc; if not exists, a new clientDoc is inserted.
+func (its *RepositoryBolt) UpdateClient(ctx iface.OrdaContext, client *schema.ClientDoc) errors.OrdaError {
+	stored := *client
+	stored.UpdatedAt = time.Now()
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		return put(ctx, tx.Bucket(bucketClients), []byte(client.CUID), &stored)
+	})
+}
+
+// DeleteClient deletes the specified client.
+func (its *RepositoryBolt) DeleteClient(ctx iface.OrdaContext, cuid string) errors.OrdaError {
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketClients)
+		if bucket.Get([]byte(cuid)) == nil {
+			ctx.L().Warnf("fail to find a client to delete: `%s`", cuid)
+			return nil
+		}
+		return deleteKey(ctx, bucket, []byte(cuid))
+	})
+}
+
+// GetClient returns a ClientDoc for the specified CUID.
+func (its *RepositoryBolt) GetClient(ctx iface.OrdaContext, cuid string) (*schema.ClientDoc, errors.OrdaError) {
+	var client *schema.ClientDoc
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		data := tx.Bucket(bucketClients).Get([]byte(cuid))
+		if data == nil {
+			return nil
+		}
+		client = &schema.ClientDoc{}
+		return decode(ctx, data, client)
+	}); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (its *RepositoryBolt) purgeAllCollectionClients(
+	ctx iface.OrdaContext,
+	tx *bbolt.Tx,
+	collectionNum int32,
+) errors.OrdaError {
+	bucket := tx.Bucket(bucketClients)
+	var deleting [][]byte
+	if err := bucket.ForEach(func(k, v []byte) error {
+		var client schema.ClientDoc
+		if err := decode(ctx, v, &client); err != nil {
+			return err
+		}
+		if client.CollectionNum == collectionNum {
+			deleting = append(deleting, append([]byte{}, k...))
+		}
+		return nil
+	}); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	for _, k := range deleting {
+		if err := deleteKey(ctx, bucket, k); err != nil {
+			return err
+		}
+	}
+	ctx.L().Infof("delete %d clients in collection#%d", len(deleting), collectionNum)
+	return nil
+}
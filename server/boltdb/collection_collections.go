@@ -0,0 +1,97 @@
+package boltdb
+
+import (
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// GetCollection gets a collectionDoc with the specified name.
+func (its *RepositoryBolt) GetCollection(ctx iface.OrdaContext, name string) (*schema.CollectionDoc, errors.OrdaError) {
+	var collection *schema.CollectionDoc
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		var err errors.OrdaError
+		collection, err = getCollection(ctx, tx, name)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func getCollection(ctx iface.OrdaContext, tx *bbolt.Tx, name string) (*schema.CollectionDoc, errors.OrdaError) {
+	data := tx.Bucket(bucketCollections).Get([]byte(name))
+	if data == nil {
+		return nil, nil
+	}
+	var collection schema.CollectionDoc
+	if err := decode(ctx, data, &collection); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// DeleteCollection deletes collections with the specified name.
+func (its *RepositoryBolt) DeleteCollection(ctx iface.OrdaContext, name string) errors.OrdaError {
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		return deleteKey(ctx, tx.Bucket(bucketCollections), []byte(name))
+	}); err != nil {
+		return err
+	}
+	ctx.L().Infof("Collection '%s' is successfully removed", name)
+	return nil
+}
+
+// InsertCollection inserts a document for the specified collection.
+func (its *RepositoryBolt) InsertCollection(
+	ctx iface.OrdaContext,
+	name string,
+) (collection *schema.CollectionDoc, err errors.OrdaError) {
+	if err = its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketCollections)
+		if bucket.Get([]byte(name)) != nil {
+			return errors.ServerDBQuery.New(ctx.L(), fmt.Sprintf("duplicate collection '%s'", name))
+		}
+		num, err := bucket.NextSequence()
+		if err != nil {
+			return errors.ServerDBQuery.New(ctx.L(), err.Error())
+		}
+		collection = &schema.CollectionDoc{
+			Name:      name,
+			Num:       int32(num),
+			CreatedAt: time.Now(),
+		}
+		return put(ctx, bucket, []byte(name), collection)
+	}); err != nil {
+		return nil, err
+	}
+	ctx.L().Infof("insert collection: %+v", collection)
+	return collection, nil
+}
+
+// PurgeCollection purges all the documents and the real collection related to the collection.
+func (its *RepositoryBolt) PurgeCollection(ctx iface.OrdaContext, name string) errors.OrdaError {
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		collectionDoc, err := getCollection(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+		if collectionDoc != nil {
+			ctx.L().Infof("purge collection#%d '%s'", collectionDoc.Num, name)
+			if err := its.purgeAllCollectionDatatypes(ctx, tx, collectionDoc.Num); err != nil {
+				return err
+			}
+			if err := its.purgeAllCollectionClients(ctx, tx, collectionDoc.Num); err != nil {
+				return err
+			}
+			if err := deleteKey(ctx, tx.Bucket(bucketCollections), []byte(name)); err != nil {
+				return err
+			}
+		}
+		return deleteBucket(ctx, tx.Bucket(bucketRealCollections), []byte(name))
+	})
+}
@@ -0,0 +1,193 @@
+package boltdb
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+func getDatatype(ctx iface.OrdaContext, tx *bbolt.Tx, duid []byte) (*schema.DatatypeDoc, errors.OrdaError) {
+	data := tx.Bucket(bucketDatatypes).Get(duid)
+	if data == nil {
+		return nil, nil
+	}
+	var datatype schema.DatatypeDoc
+	if err := decode(ctx, data, &datatype); err != nil {
+		return nil, err
+	}
+	return &datatype, nil
+}
+
+// GetDatatype retrieves a datatypeDoc from BoltDB
+func (its *RepositoryBolt) GetDatatype(ctx iface.OrdaContext, duid string) (*schema.DatatypeDoc, errors.OrdaError) {
+	var datatype *schema.DatatypeDoc
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		var err errors.OrdaError
+		datatype, err = getDatatype(ctx, tx, []byte(duid))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return datatype, nil
+}
+
+// GetDatatypeByKey gets a datatype with the specified key.
+func (its *RepositoryBolt) GetDatatypeByKey(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	key string,
+) (*schema.DatatypeDoc, errors.OrdaError) {
+	var datatype *schema.DatatypeDoc
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		duid := tx.Bucket(bucketDatatypeKeys).Get(datatypeKey(collectionNum, key))
+		if duid == nil {
+			return nil
+		}
+		var err errors.OrdaError
+		datatype, err = getDatatype(ctx, tx, duid)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return datatype, nil
+}
+
+// UpdateDatatype updates the datatypeDoc; if not exists, a new datatypeDoc is inserted.
+func (its *RepositoryBolt) UpdateDatatype(ctx iface.OrdaContext, datatype *schema.DatatypeDoc) errors.OrdaError {
+	datatype.UpdatedAt = time.Now()
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		return updateDatatype(ctx, tx, datatype)
+	})
+}
+
+func updateDatatype(ctx iface.OrdaContext, tx *bbolt.Tx, datatype *schema.DatatypeDoc) errors.OrdaError {
+	if err := put(ctx, tx.Bucket(bucketDatatypes), []byte(datatype.DUID), datatype); err != nil {
+		return err
+	}
+	k := datatypeKey(datatype.CollectionNum, datatype.Key)
+	if err := tx.Bucket(bucketDatatypeKeys).Put(k, []byte(datatype.DUID)); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return nil
+}
+
+// BumpEra starts a new era of the datatype, and increments its Version.
+func (its *RepositoryBolt) BumpEra(ctx iface.OrdaContext, duid string) (uint32, errors.OrdaError) {
+	var era uint32
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		datatype, err := getDatatype(ctx, tx, []byte(duid))
+		if err != nil {
+			return err
+		}
+		if datatype == nil {
+			return errors.ServerNoResource.New(ctx.L(), "datatype "+duid)
+		}
+		datatype.Era++
+		datatype.Version++
+		datatype.UpdatedAt = time.Now()
+		era = datatype.Era
+		return updateDatatype(ctx, tx, datatype)
+	}); err != nil {
+		return 0, err
+	}
+	return era, nil
+}
+
+// PurgeDatatype purges a datatype from BoltDB.
+func (its *RepositoryBolt) PurgeDatatype(ctx iface.OrdaContext, collectionNum int32, key string) errors.OrdaError {
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		k := datatypeKey(collectionNum, key)
+		duid := tx.Bucket(bucketDatatypeKeys).Get(k)
+		if duid == nil {
+			ctx.L().Warnf("find no datatype to purge")
+			return nil
+		}
+		duid = append([]byte{}, duid...)
+		if err := its.purgeDatatype(ctx, tx, duid, k); err != nil {
+			return err
+		}
+		ctx.L().Infof("purged datatype `%s(%d)`", key, collectionNum)
+		return nil
+	})
+}
+
+func (its *RepositoryBolt) purgeDatatype(ctx iface.OrdaContext, tx *bbolt.Tx, duid []byte, key []byte) errors.OrdaError {
+	if err := deleteBucket(ctx, tx.Bucket(bucketOperations), duid); err != nil {
+		return err
+	}
+	if err := deleteBucket(ctx, tx.Bucket(bucketSnapshots), duid); err != nil {
+		return err
+	}
+	if err := deleteKey(ctx, tx.Bucket(bucketDatatypeKeys), key); err != nil {
+		return err
+	}
+	return deleteKey(ctx, tx.Bucket(bucketDatatypes), duid)
+}
+
+func (its *RepositoryBolt) purgeAllCollectionDatatypes(
+	ctx iface.OrdaContext,
+	tx *bbolt.Tx,
+	collectionNum int32,
+) errors.OrdaError {
+	for _, name := range [][]byte{bucketOperations, bucketSnapshots} {
+		if err := purgeNestedBucketsOfCollection(ctx, tx.Bucket(name), collectionNum); err != nil {
+			return err
+		}
+	}
+	var deleting []*schema.DatatypeDoc
+	if err := tx.Bucket(bucketDatatypes).ForEach(func(k, v []byte) error {
+		var datatype schema.DatatypeDoc
+		if err := decode(ctx, v, &datatype); err != nil {
+			return err
+		}
+		if datatype.CollectionNum == collectionNum {
+			deleting = append(deleting, &datatype)
+		}
+		return nil
+	}); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	for _, datatype := range deleting {
+		if err := its.purgeDatatype(ctx, tx, []byte(datatype.DUID), datatypeKey(collectionNum, datatype.Key)); err != nil {
+			return err
+		}
+	}
+	ctx.L().Infof("delete %d datatypes in collection#%d", len(deleting), collectionNum)
+	return nil
+}
+
+// purgeNestedBucketsOfCollection deletes the nested buckets of each DUID, which belong to the collection.
+func purgeNestedBucketsOfCollection(ctx iface.OrdaContext, parent *bbolt.Bucket, collectionNum int32) errors.OrdaError {
+	var deleting [][]byte
+	if err := parent.ForEach(func(duid, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		_, first := parent.Bucket(duid).Cursor().First()
+		if first == nil {
+			return nil
+		}
+		var doc struct {
+			CollectionNum int32 `bson:"colNum"`
+		}
+		if err := decode(ctx, first, &doc); err != nil {
+			return err
+		}
+		if doc.CollectionNum == collectionNum {
+			deleting = append(deleting, append([]byte{}, duid...))
+		}
+		return nil
+	}); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	for _, duid := range deleting {
+		if err := deleteBucket(ctx, parent, duid); err != nil {
+			return err
+		}
+	}
+	ctx.L().Infof("delete %d datatypes of nested buckets in collection#%d", len(deleting), collectionNum)
+	return nil
+}
@@ -0,0 +1,267 @@
+package boltdb
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// InsertOperations inserts operations into BoltDB; either all or none of them are inserted.
+func (its *RepositoryBolt) InsertOperations(ctx iface.OrdaContext, operations []*schema.OperationDoc) errors.OrdaError {
+	if len(operations) == 0 {
+		return nil
+	}
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		return insertOperations(ctx, tx, operations)
+	})
+}
+
+func insertOperations(ctx iface.OrdaContext, tx *bbolt.Tx, operations []*schema.OperationDoc) errors.OrdaError {
+	for _, op := range operations {
+		bucket, err := tx.Bucket(bucketOperations).CreateBucketIfNotExists([]byte(op.DUID))
+		if err != nil {
+			return errors.ServerDBQuery.New(ctx.L(), err.Error())
+		}
+		key := sseqToKey(op.Sseq)
+		if bucket.Get(key) != nil {
+			return errors.ServerDBQuery.New(ctx.L(), fmt.Sprintf("duplicate operation '%s'", op.ID))
+		}
+		if err := put(ctx, bucket, key, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CommitPushPull inserts the operations and updates the datatype in a transaction if its Version is still stored.
+func (its *RepositoryBolt) CommitPushPull(
+	ctx iface.OrdaContext,
+	datatype *schema.DatatypeDoc,
+	operations []*schema.OperationDoc,
+) errors.OrdaError {
+	datatype.UpdatedAt = time.Now()
+	version := datatype.Version
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		prev, err := getDatatype(ctx, tx, []byte(datatype.DUID))
+		if err != nil {
+			return err
+		}
+		var stored uint64 = 0
+		if prev != nil {
+			stored = prev.Version
+		} else if other := tx.Bucket(bucketDatatypeKeys).Get(datatypeKey(datatype.CollectionNum, datatype.Key)); other != nil {
+			msg := fmt.Sprintf("key '%s' is already used by %s", datatype.Key, other)
+			return errors.ServerDBConflict.New(ctx.L(), msg)
+		}
+		if stored != version {
+			msg := fmt.Sprintf("version of %s is %d, not %d", datatype.DUID, stored, version)
+			return errors.ServerDBConflict.New(ctx.L(), msg)
+		}
+		if err := insertOperations(ctx, tx, operations); err != nil {
+			return err
+		}
+		if prev != nil {
+			datatype.Sseq.Begin, datatype.Sseq.Safe = prev.Sseq.Begin, prev.Sseq.Safe
+			datatype.Era = prev.Era
+		}
+		datatype.Version = version + 1
+		return updateDatatype(ctx, tx, datatype)
+	}); err != nil {
+		datatype.Version = version
+		return err
+	}
+	return nil
+}
+
+// CompactOperations advances Sseq.Begin of the datatype to begin, and removes the operations before it.
+func (its *RepositoryBolt) CompactOperations(
+	ctx iface.OrdaContext,
+	duid string,
+	begin, safe uint64,
+) (int64, errors.OrdaError) {
+	var deleted int64 = 0
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		datatype, err := getDatatype(ctx, tx, []byte(duid))
+		if err != nil {
+			return err
+		}
+		if datatype == nil {
+			return errors.ServerNoResource.New(ctx.L(), "datatype "+duid)
+		}
+		if datatype.Sseq.Begin < begin {
+			datatype.Sseq.Begin = begin
+		}
+		datatype.Sseq.Safe = safe
+		if err := updateDatatype(ctx, tx, datatype); err != nil {
+			return err
+		}
+		bucket := tx.Bucket(bucketOperations).Bucket([]byte(duid))
+		if bucket == nil {
+			return nil
+		}
+		var deleting [][]byte
+		c := bucket.Cursor()
+		end := sseqToKey(begin)
+		for k, _ := c.First(); k != nil && bytes.Compare(k, end) < 0; k, _ = c.Next() {
+			deleting = append(deleting, append([]byte{}, k...))
+		}
+		for _, k := range deleting {
+			if err := deleteKey(ctx, bucket, k); err != nil {
+				return err
+			}
+		}
+		deleted = int64(len(deleting))
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// RepairOperations removes orphaned operations, i.e., ones beyond Sseq.End of their datatype or without datatype.
+func (its *RepositoryBolt) RepairOperations(ctx iface.OrdaContext) (int64, errors.OrdaError) {
+	var repaired int64 = 0
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		opBuckets := tx.Bucket(bucketOperations)
+		var duids [][]byte
+		if err := opBuckets.ForEach(func(k, v []byte) error {
+			if v == nil {
+				duids = append(duids, append([]byte{}, k...))
+			}
+			return nil
+		}); err != nil {
+			return errors.ServerDBQuery.New(ctx.L(), err.Error())
+		}
+		for _, duid := range duids {
+			datatype, err := getDatatype(ctx, tx, duid)
+			if err != nil {
+				return err
+			}
+			var sseqEnd uint64 = 0
+			if datatype != nil {
+				sseqEnd = datatype.Sseq.End
+			}
+			bucket := opBuckets.Bucket(duid)
+			var deleting [][]byte
+			c := bucket.Cursor()
+			for k, _ := c.Seek(sseqToKey(sseqEnd + 1)); k != nil; k, _ = c.Next() {
+				deleting = append(deleting, append([]byte{}, k...))
+			}
+			for _, k := range deleting {
+				if err := deleteKey(ctx, bucket, k); err != nil {
+					return err
+				}
+			}
+			if len(deleting) > 0 {
+				ctx.L().Warnf("repair %d orphaned operations of %s beyond sseq %d", len(deleting), duid, sseqEnd)
+				repaired += int64(len(deleting))
+			}
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return repaired, nil
+}
+
+// DeleteOperation deletes operations for the specified sseq
+func (its *RepositoryBolt) DeleteOperation(ctx iface.OrdaContext, duid string, sseq uint32) (int64, errors.OrdaError) {
+	var deleted int64 = 0
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketOperations).Bucket([]byte(duid))
+		if bucket == nil {
+			return nil
+		}
+		key := sseqToKey(uint64(sseq))
+		if bucket.Get(key) == nil {
+			return nil
+		}
+		deleted = 1
+		return deleteKey(ctx, bucket, key)
+	}); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// GetOperations gets operations of the specified range.
+func (its *RepositoryBolt) GetOperations(
+	ctx iface.OrdaContext,
+	duid string,
+	from, to uint64,
+) (model.OpList, []uint64, errors.OrdaError) {
+	var opList []*model.Operation
+	var sseqList []uint64
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketOperations).Bucket([]byte(duid))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Seek(sseqToKey(from)); k != nil; k, v = c.Next() {
+			if to != constants.InfinitySseq && keyToSseq(k) > to {
+				break
+			}
+			var opDoc schema.OperationDoc
+			if err := decode(ctx, v, &opDoc); err != nil {
+				return err
+			}
+			opList = append(opList, opDoc.GetOperation())
+			sseqList = append(sseqList, opDoc.Sseq)
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	return opList, sseqList, nil
+}
+
+// GetLastSseqAt returns the sseq of the last operation created at or before the time, or 0 if there is none.
+func (its *RepositoryBolt) GetLastSseqAt(ctx iface.OrdaContext, duid string, at time.Time) (uint64, errors.OrdaError) {
+	var sseq uint64 = 0
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketOperations).Bucket([]byte(duid))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var opDoc schema.OperationDoc
+			if err := decode(ctx, v, &opDoc); err != nil {
+				return err
+			}
+			if !opDoc.CreatedAt.After(at) {
+				sseq = opDoc.Sseq
+				return nil
+			}
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return sseq, nil
+}
+
+// PurgeOperations purges operations for the specified datatype.
+func (its *RepositoryBolt) PurgeOperations(ctx iface.OrdaContext, collectionNum int32, duid string) errors.OrdaError {
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketOperations).Bucket([]byte(duid))
+		if bucket == nil {
+			ctx.L().Warnf("deleted no operations")
+			return nil
+		}
+		deleted := bucket.Stats().KeyN
+		if err := deleteBucket(ctx, tx.Bucket(bucketOperations), []byte(duid)); err != nil {
+			return err
+		}
+		ctx.L().Infof("deleted %d operations of %s(%d)", deleted, duid, collectionNum)
+		return nil
+	})
+}
@@ -0,0 +1,70 @@
+package boltdb
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/repository"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetOrCreateRealCollection is a method that gets or creates a collection of snapshot
+func (its *RepositoryBolt) GetOrCreateRealCollection(ctx iface.OrdaContext, name string) errors.OrdaError {
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		if _, err := tx.Bucket(bucketRealCollections).CreateBucketIfNotExists([]byte(name)); err != nil {
+			return errors.ServerDBQuery.New(ctx.L(), err.Error())
+		}
+		return nil
+	})
+}
+
+// InsertRealSnapshot inserts a snapshot for real collection.
+func (its *RepositoryBolt) InsertRealSnapshot(
+	ctx iface.OrdaContext,
+	collectionName string,
+	id string,
+	data interface{},
+	sseq uint64,
+) errors.OrdaError {
+	marshaled, err := bson.Marshal(data)
+	if err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error(), data)
+	}
+	var bsonM = bson.M{}
+	if err := bson.Unmarshal(marshaled, &bsonM); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	bsonM["_id"] = id
+	bsonM[repository.Ver] = sseq
+	return its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket, err := tx.Bucket(bucketRealCollections).CreateBucketIfNotExists([]byte(collectionName))
+		if err != nil {
+			return errors.ServerDBQuery.New(ctx.L(), err.Error())
+		}
+		return put(ctx, bucket, []byte(id), bsonM)
+	})
+}
+
+// GetRealSnapshot returns a real snapshot
+func (its *RepositoryBolt) GetRealSnapshot(
+	ctx iface.OrdaContext,
+	collectionName string,
+	id string,
+) (map[string]interface{}, errors.OrdaError) {
+	var snap map[string]interface{}
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketRealCollections).Bucket([]byte(collectionName))
+		if bucket == nil {
+			return nil
+		}
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		return decode(ctx, data, &snap)
+	}); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
@@ -0,0 +1,143 @@
+package boltdb
+
+import (
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// GetLatestSnapshot gets the latest snapshot for the specified datatype.
+func (its *RepositoryBolt) GetLatestSnapshot(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+) (*schema.SnapshotDoc, errors.OrdaError) {
+	var snapshot *schema.SnapshotDoc
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketSnapshots).Bucket([]byte(duid))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var snap schema.SnapshotDoc
+			if err := decode(ctx, v, &snap); err != nil {
+				return err
+			}
+			if snap.CollectionNum == collectionNum {
+				snapshot = &snap
+				return nil
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// InsertSnapshot inserts a snapshot for the specified datatype.
+func (its *RepositoryBolt) InsertSnapshot(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+	sseq uint64,
+	meta []byte,
+	snapshot []byte,
+) errors.OrdaError {
+	snap := &schema.SnapshotDoc{
+		ID:            fmt.Sprintf("%s:%d", duid, sseq),
+		CollectionNum: collectionNum,
+		DUID:          duid,
+		Sseq:          sseq,
+		Meta:          string(meta),
+		Snapshot:      snapshot,
+		CreatedAt:     time.Now(),
+	}
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket, err := tx.Bucket(bucketSnapshots).CreateBucketIfNotExists([]byte(duid))
+		if err != nil {
+			return errors.ServerDBQuery.New(ctx.L(), err.Error())
+		}
+		key := sseqToKey(sseq)
+		if bucket.Get(key) != nil {
+			return errors.ServerDBQuery.New(ctx.L(), fmt.Sprintf("duplicate snapshot '%s'", snap.ID))
+		}
+		return put(ctx, bucket, key, snap)
+	}); err != nil {
+		return err
+	}
+	ctx.L().Infof("insert snapshot: %s", snap.ID)
+	return nil
+}
+
+// GetSnapshots returns all the snapshots of the datatype in ascending order of sseq.
+func (its *RepositoryBolt) GetSnapshots(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+) ([]*schema.SnapshotDoc, errors.OrdaError) {
+	var snapshots []*schema.SnapshotDoc
+	if err := its.view(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketSnapshots).Bucket([]byte(duid))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var snap schema.SnapshotDoc
+			if err := decode(ctx, v, &snap); err != nil {
+				return err
+			}
+			if snap.CollectionNum == collectionNum {
+				snapshots = append(snapshots, &snap)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshots removes the snapshots of the datatype at the specified sseqs.
+func (its *RepositoryBolt) DeleteSnapshots(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+	sseqList []uint64,
+) (int64, errors.OrdaError) {
+	var deleted int64
+	if err := its.update(ctx, func(tx *bbolt.Tx) errors.OrdaError {
+		bucket := tx.Bucket(bucketSnapshots).Bucket([]byte(duid))
+		if bucket == nil {
+			return nil
+		}
+		for _, sseq := range sseqList {
+			key := sseqToKey(sseq)
+			v := bucket.Get(key)
+			if v == nil {
+				continue
+			}
+			var snap schema.SnapshotDoc
+			if err := decode(ctx, v, &snap); err != nil {
+				return err
+			}
+			if snap.CollectionNum != collectionNum {
+				continue
+			}
+			if err := bucket.Delete(key); err != nil {
+				return errors.ServerDBQuery.New(ctx.L(), err.Error())
+			}
+			deleted++
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,10 @@
+package boltdb
+
+import "time"
+
+const defaultOpenTimeout = 3 * time.Second
+
+// Config is a configuration for the embedded BoltDB
+type Config struct {
+	Path string `json:"Path"`
+}
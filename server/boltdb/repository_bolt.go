@@ -0,0 +1,150 @@
+package boltdb
+
+import (
+	"encoding/binary"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"os"
+	"path/filepath"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	bucketClients         = []byte("clients")
+	bucketCollections     = []byte("collections")
+	bucketDatatypes       = []byte("datatypes")
+	bucketDatatypeKeys    = []byte("datatypeKeys")
+	bucketOperations      = []byte("operations")
+	bucketSnapshots       = []byte("snapshots")
+	bucketRealCollections = []byte("realCollections")
+)
+
+// RepositoryBolt is a durable repository for a single node, which stores documents in an embedded BoltDB file.
+// Operations and snapshots are kept in a bucket for each DUID, where they are keyed by sseq.
+type RepositoryBolt struct {
+	db *bbolt.DB
+}
+
+// New creates a new RepositoryBolt
+func New(ctx iface.OrdaContext, conf *Config) (*RepositoryBolt, errors.OrdaError) {
+	if conf == nil || conf.Path == "" {
+		return nil, errors.ServerDBInit.New(ctx.L(), "no path for BoltDB")
+	}
+	if err := os.MkdirAll(filepath.Dir(conf.Path), 0755); err != nil {
+		return nil, errors.ServerDBInit.New(ctx.L(), err.Error())
+	}
+	db, err := bbolt.Open(conf.Path, 0600, &bbolt.Options{Timeout: defaultOpenTimeout})
+	if err != nil {
+		return nil, errors.ServerDBInit.New(ctx.L(), err.Error())
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{
+			bucketClients, bucketCollections, bucketDatatypes, bucketDatatypeKeys,
+			bucketOperations, bucketSnapshots, bucketRealCollections,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		_ = db.Close()
+		return nil, errors.ServerDBInit.New(ctx.L(), err.Error())
+	}
+	ctx.L().Infof("New BoltDB:%v", conf.Path)
+	return &RepositoryBolt{db: db}, nil
+}
+
+// Close closes the repository of BoltDB
+func (its *RepositoryBolt) Close(ctx iface.OrdaContext) errors.OrdaError {
+	if err := its.db.Close(); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return nil
+}
+
+func (its *RepositoryBolt) view(ctx iface.OrdaContext, fn func(tx *bbolt.Tx) errors.OrdaError) errors.OrdaError {
+	var oErr errors.OrdaError
+	if err := its.db.View(func(tx *bbolt.Tx) error {
+		if oErr = fn(tx); oErr != nil {
+			return oErr
+		}
+		return nil
+	}); err != nil && oErr == nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return oErr
+}
+
+// update runs fn in a read-write transaction, which is rolled back if fn returns an error.
+func (its *RepositoryBolt) update(ctx iface.OrdaContext, fn func(tx *bbolt.Tx) errors.OrdaError) errors.OrdaError {
+	var oErr errors.OrdaError
+	if err := its.db.Update(func(tx *bbolt.Tx) error {
+		if oErr = fn(tx); oErr != nil {
+			return oErr
+		}
+		return nil
+	}); err != nil && oErr == nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return oErr
+}
+
+func encode(ctx iface.OrdaContext, doc interface{}) ([]byte, errors.OrdaError) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return data, nil
+}
+
+// decode decodes data into doc; since data is valid only in a transaction, it is copied in advance.
+func decode(ctx iface.OrdaContext, data []byte, doc interface{}) errors.OrdaError {
+	if err := bson.Unmarshal(append([]byte{}, data...), doc); err != nil {
+		return errors.ServerDBDecode.New(ctx.L(), err.Error())
+	}
+	return nil
+}
+
+func put(ctx iface.OrdaContext, bucket *bbolt.Bucket, key []byte, doc interface{}) errors.OrdaError {
+	data, err := encode(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if err := bucket.Put(key, data); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return nil
+}
+
+func deleteKey(ctx iface.OrdaContext, bucket *bbolt.Bucket, key []byte) errors.OrdaError {
+	if err := bucket.Delete(key); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return nil
+}
+
+func deleteBucket(ctx iface.OrdaContext, parent *bbolt.Bucket, name []byte) errors.OrdaError {
+	if err := parent.DeleteBucket(name); err != nil && err != bbolt.ErrBucketNotFound {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return nil
+}
+
+func sseqToKey(sseq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sseq)
+	return key
+}
+
+func keyToSseq(key []byte) uint64 {
+	return binary.BigEndian.Uint64(key)
+}
+
+func datatypeKey(collectionNum int32, key string) []byte {
+	k := make([]byte, 4, 4+len(key))
+	binary.BigEndian.PutUint32(k, uint32(collectionNum))
+	return append(k, key...)
+}
@@ -0,0 +1,51 @@
+package boltdb_test
+
+import (
+	gocontext "context"
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/server/boltdb"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/schema"
+	"github.com/orda-io/orda/server/testonly"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBolt(t *testing.T) {
+	ctx := context.NewOrdaContext(gocontext.TODO(), constants.TagTest).UpdateCollectionTags(t.Name(), 0)
+	conf := &boltdb.Config{Path: filepath.Join(t.TempDir(), "orda.db")}
+
+	t.Run("Can pass the repository test suite", func(t *testing.T) {
+		repo, err := boltdb.New(ctx, conf)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, repo.Close(ctx))
+		}()
+		testonly.TestRepository(t, ctx, repo)
+	})
+
+	t.Run("Can keep documents across restarts", func(t *testing.T) {
+		repo1, err := boltdb.New(ctx, conf)
+		require.NoError(t, err)
+		collectionNum, err := repository.MakeCollection(ctx, repo1, t.Name())
+		require.NoError(t, err)
+		datatype := schema.NewDatatypeDoc("duid", "key", collectionNum, "COUNTER")
+		require.NoError(t, repo1.UpdateDatatype(ctx, datatype))
+		require.NoError(t, repo1.Close(ctx))
+
+		repo2, err := boltdb.New(ctx, conf)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, repo2.Close(ctx))
+		}()
+		collectionNum2, err := repository.MakeCollection(ctx, repo2, t.Name())
+		require.NoError(t, err)
+		require.Equal(t, collectionNum, collectionNum2)
+		datatype2, err := repo2.GetDatatypeByKey(ctx, collectionNum, "key")
+		require.NoError(t, err)
+		require.Equal(t, datatype.DUID, datatype2.DUID)
+	})
+}
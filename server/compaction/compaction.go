@@ -0,0 +1,78 @@
+package compaction
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+)
+
+// Compact removes the operations of the datatype which are covered by the latest snapshot and already pulled by
+// all the subscribed clients within the horizon. Sseq.Begin of the datatype is advanced past the removed operations,
+// and a new era is started so that the clients rebase on the latest snapshot.
+func Compact(
+	ctx iface.OrdaContext,
+	repo repository.Repository,
+	conf *Config,
+	collectionNum int32,
+	duid string,
+) errors.OrdaError {
+	datatypeDoc, err := repo.GetDatatype(ctx, duid)
+	if err != nil {
+		return err
+	}
+	if datatypeDoc == nil {
+		return nil
+	}
+	snapshotDoc, err := repo.GetLatestSnapshot(ctx, collectionNum, duid)
+	if err != nil {
+		return err
+	}
+	if snapshotDoc == nil {
+		return nil
+	}
+	safe := getSafeSseq(conf, datatypeDoc)
+	boundary := safe
+	if snapshotDoc.Sseq < boundary {
+		boundary = snapshotDoc.Sseq
+	}
+	begin := datatypeDoc.Sseq.Begin
+	if begin == 0 {
+		begin = 1
+	}
+	if boundary < begin || boundary-begin+1 < conf.MinOperations {
+		return nil
+	}
+	deleted, err := repo.CompactOperations(ctx, duid, boundary+1, safe)
+	if err != nil {
+		return err
+	}
+	ctx.L().Infof("compact %d operations of %s: sseq.begin %d -> %d", deleted, duid, begin, boundary+1)
+	if deleted == 0 {
+		return nil
+	}
+	era, err := repo.BumpEra(ctx, duid)
+	if err != nil {
+		return err
+	}
+	ctx.L().Infof("start era %d of %s after compaction", era, duid)
+	return nil
+}
+
+// getSafeSseq returns the minimum sseq of checkpoints of the clients which have push-pulled within the horizon.
+func getSafeSseq(conf *Config, datatypeDoc *schema.DatatypeDoc) uint64 {
+	safe := datatypeDoc.Sseq.End
+	now := time.Now()
+	for _, clients := range []map[string]*schema.SubscribedClientDoc{datatypeDoc.RWClients, datatypeDoc.ROClients} {
+		for _, client := range clients {
+			if conf.Horizon > 0 && now.Sub(client.At) > conf.getHorizon() {
+				continue
+			}
+			if client.CP.Sseq < safe {
+				safe = client.CP.Sseq
+			}
+		}
+	}
+	return safe
+}
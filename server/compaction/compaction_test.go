@@ -0,0 +1,87 @@
+package compaction_test
+
+import (
+	gocontext "context"
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/operations"
+	"github.com/orda-io/orda/client/pkg/types"
+	"github.com/orda-io/orda/server/compaction"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/memdb"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCompaction(t *testing.T) {
+	ctx := context.NewOrdaContext(gocontext.TODO(), constants.TagTest).UpdateCollectionTags(t.Name(), 0)
+	repo := memdb.New(ctx)
+	collectionNum, err := repository.MakeCollection(ctx, repo, t.Name())
+	require.NoError(t, err)
+
+	newDatatype := func(key string) *schema.DatatypeDoc {
+		datatype := schema.NewDatatypeDoc(types.NewUID(), key, collectionNum, "COUNTER")
+		active := datatype.AddNewClient(types.NewUID(), int8(model.ClientType_PERSISTENT), false)
+		active.CP.Sseq = 5
+		inactive := datatype.AddNewClient(types.NewUID(), int8(model.ClientType_PERSISTENT), true)
+		inactive.CP.Sseq = 2
+		inactive.At = time.Now().Add(-time.Hour)
+
+		var opList []*schema.OperationDoc
+		for sseq := uint64(1); sseq <= 6; sseq++ {
+			op := operations.NewIncreaseOperation(1)
+			op.ID = model.NewOperationIDWithCUID(types.NewUID())
+			opList = append(opList, schema.NewOperationDoc(op.ToModelOperation(), datatype.DUID, sseq, collectionNum))
+		}
+		datatype.Sseq.End = 6
+		require.NoError(t, repo.CommitPushPull(ctx, datatype, opList))
+		return datatype
+	}
+
+	requireSseqs := func(duid string, begin uint64, sseqs []uint64) {
+		datatype, err := repo.GetDatatype(ctx, duid)
+		require.NoError(t, err)
+		require.Equal(t, begin, datatype.Sseq.Begin)
+		_, sseqList, err := repo.GetOperations(ctx, duid, 1, constants.InfinitySseq)
+		require.NoError(t, err)
+		require.Equal(t, sseqs, sseqList)
+	}
+
+	t.Run("Can compact operations up to the minimum checkpoint", func(t *testing.T) {
+		datatype := newDatatype(t.Name())
+		conf := &compaction.Config{}
+		require.NoError(t, compaction.Compact(ctx, repo, conf, collectionNum, datatype.DUID))
+		requireSseqs(datatype.DUID, 0, []uint64{1, 2, 3, 4, 5, 6}) // no snapshot
+
+		require.NoError(t, repo.InsertSnapshot(ctx, collectionNum, datatype.DUID, 4, []byte("meta"), []byte("snap")))
+		require.NoError(t, compaction.Compact(ctx, repo, conf, collectionNum, datatype.DUID))
+		requireSseqs(datatype.DUID, 3, []uint64{3, 4, 5, 6})
+		stored, err := repo.GetDatatype(ctx, datatype.DUID)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), stored.Era)
+
+		require.NoError(t, compaction.Compact(ctx, repo, conf, collectionNum, datatype.DUID))
+		stored, err = repo.GetDatatype(ctx, datatype.DUID)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), stored.Era) // nothing compacted
+	})
+
+	t.Run("Can compact operations up to the snapshot beyond the horizon", func(t *testing.T) {
+		datatype := newDatatype(t.Name())
+		require.NoError(t, repo.InsertSnapshot(ctx, collectionNum, datatype.DUID, 4, []byte("meta"), []byte("snap")))
+
+		conf := &compaction.Config{Horizon: 60, MinOperations: 5}
+		require.NoError(t, compaction.Compact(ctx, repo, conf, collectionNum, datatype.DUID))
+		requireSseqs(datatype.DUID, 0, []uint64{1, 2, 3, 4, 5, 6}) // fewer than MinOperations
+
+		conf.MinOperations = 0
+		require.NoError(t, compaction.Compact(ctx, repo, conf, collectionNum, datatype.DUID))
+		requireSseqs(datatype.DUID, 5, []uint64{5, 6})
+		stored, err := repo.GetDatatype(ctx, datatype.DUID)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), stored.Sseq.Safe)
+	})
+}
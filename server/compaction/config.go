@@ -0,0 +1,16 @@
+package compaction
+
+import "time"
+
+// Config is a configuration for compacting the operations of datatypes
+type Config struct {
+	// Horizon is the duration in seconds; clients which have not push-pulled for longer than this are not waited for,
+	// and they catch up with a snapshot later. If 0, the operations are kept until every subscribed client pulls them.
+	Horizon int64 `json:"Horizon"`
+	// MinOperations is the minimum number of operations to compact at once.
+	MinOperations uint64 `json:"MinOperations"`
+}
+
+func (its *Config) getHorizon() time.Duration {
+	return time.Duration(its.Horizon) * time.Second
+}
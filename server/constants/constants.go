@@ -1,10 +1,42 @@
 package constants
 
-import "math"
+import (
+	"math"
+
+	"github.com/orda-io/orda/client/pkg/model"
+)
 
 // InfinitySseq is infinite number of sseq
 const InfinitySseq uint64 = math.MaxUint64
 
+// SupportedProtocolVersions are the versions of the protocol that the server can speak with clients
+var SupportedProtocolVersions = []string{model.ProtocolVersion}
+
+// ServerCapabilities are the optional features of the protocol that the server provides
+const ServerCapabilities = model.CapabilityDedup | model.CapabilityPagination | model.CapabilityRebase |
+	model.CapabilityBinaryEncoding | model.CapabilityChunkedSnapshot | model.CapabilityStability
+
+// DefaultCatchUpGap is the default number of operations that a client can fall behind before catching up with a snapshot
+const DefaultCatchUpGap uint64 = 1000
+
+// DefaultPullLimit is the default maximum number of operations pulled in a PushPullPack
+const DefaultPullLimit uint64 = 10000
+
+// DefaultSnapshotChunkSize is the default maximum number of bytes of a snapshot pulled in a PushPullPack
+const DefaultSnapshotChunkSize uint64 = 1 << 20
+
+// DefaultDedupSeconds is the default seconds for which the responses of push-pulls are remembered for retries
+const DefaultDedupSeconds int64 = 30
+
+// DefaultDedupMaxBytes is the default maximum number of bytes of the responses of push-pulls remembered for retries
+const DefaultDedupMaxBytes = 64 << 20
+
+// DefaultMaxMessageSize is the default maximum number of bytes of a message received from a client
+const DefaultMaxMessageSize = 4 << 20
+
+// MaxPushPullRetries is the maximum number of retries of a push-pull which conflicts with concurrent ones
+const MaxPushPullRetries = 10
+
 // TagXXX are emoji tags of logs
 const (
 	TagServer       = "👽"
@@ -15,4 +47,6 @@ const (
 	TagPostPushPull = "🧽"
 	TagTest         = "🦠"
 	TagPatch        = "🧵"
+	TagRestore      = "⏪"
+	TagWebSocket    = "🕸"
 )
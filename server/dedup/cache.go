@@ -0,0 +1,165 @@
+package dedup
+
+import (
+	"container/list"
+	gocontext "context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/orda-io/orda/client/pkg/model"
+	"google.golang.org/protobuf/proto"
+)
+
+type entry struct {
+	key         string
+	fingerprint [sha256.Size]byte
+	response    *model.PushPullMessage
+	size        int
+	element     *list.Element
+	doneCh      chan struct{}
+	expireAt    time.Time
+}
+
+// Cache remembers the responses of the recent push-pulls for a TTL, so that a retried request gets exactly the
+// same response without being processed again. It is local to a server. When the remembered responses exceed
+// maxBytes, the oldest ones are forgotten.
+type Cache struct {
+	ttl      time.Duration
+	maxBytes int
+	mutex    *sync.Mutex
+	entries  map[string]*entry
+	order    *list.List // the remembered entries from the oldest
+	size     int
+}
+
+// New creates a Cache whose responses expire after the ttl, and which remembers responses up to maxBytes.
+func New(ttl time.Duration, maxBytes int) *Cache {
+	return &Cache{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		mutex:    &sync.Mutex{},
+		entries:  make(map[string]*entry),
+		order:    list.New(),
+	}
+}
+
+// Do returns the response of the request, which is identified by the key, processed by the function. If the same
+// request has been processed within the TTL or is being processed, its response is returned instead, and
+// the second returned value is true. The function is given a context detached from the cancellation of goCtx,
+// since its response can be returned to a retried request; it expires after the TTL. The responses of failed or
+// expired requests are not remembered.
+func (its *Cache) Do(
+	goCtx gocontext.Context,
+	key string,
+	request *model.PushPullMessage,
+	process func(goCtx gocontext.Context) (*model.PushPullMessage, error),
+) (*model.PushPullMessage, bool, error) {
+	fingerprint, ok := getFingerprint(request)
+	if !ok {
+		res, err := process(goCtx)
+		return res, false, err
+	}
+	its.mutex.Lock()
+	now := time.Now()
+	its.sweep(now)
+	if e, ok := its.entries[key]; ok && e.fingerprint == fingerprint {
+		its.mutex.Unlock()
+		<-e.doneCh
+		if e.response != nil {
+			return e.response, true, nil
+		}
+		res, err := process(goCtx)
+		return res, false, err
+	} else if ok {
+		its.remove(e)
+	}
+	e := &entry{key: key, fingerprint: fingerprint, doneCh: make(chan struct{})}
+	its.entries[key] = e
+	its.mutex.Unlock()
+
+	processCtx, cancel := gocontext.WithTimeout(detachedContext{goCtx}, its.ttl)
+	defer cancel()
+	res, err := process(processCtx)
+
+	its.mutex.Lock()
+	if err != nil || res == nil || processCtx.Err() != nil || its.entries[key] != e {
+		its.remove(e)
+	} else {
+		its.remember(e, res)
+	}
+	its.mutex.Unlock()
+	close(e.doneCh)
+	return res, false, err
+}
+
+// Len returns the number of the remembered requests.
+func (its *Cache) Len() int {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	return len(its.entries)
+}
+
+// remember keeps the response of the entry, and forgets the oldest responses exceeding maxBytes.
+func (its *Cache) remember(e *entry, res *model.PushPullMessage) {
+	e.response = res // the waiting ones get it even if forgotten
+	e.size = proto.Size(res)
+	if e.size > its.maxBytes {
+		its.remove(e)
+		return
+	}
+	e.expireAt = time.Now().Add(its.ttl)
+	e.element = its.order.PushBack(e)
+	its.size += e.size
+	for its.size > its.maxBytes {
+		its.remove(its.order.Front().Value.(*entry))
+	}
+}
+
+// remove forgets the entry if it is remembered.
+func (its *Cache) remove(e *entry) {
+	if its.entries[e.key] == e {
+		delete(its.entries, e.key)
+	}
+	if e.element != nil {
+		its.order.Remove(e.element)
+		its.size -= e.size
+		e.element = nil
+	}
+}
+
+// sweep removes the expired responses, which are the oldest ones.
+func (its *Cache) sweep(now time.Time) {
+	for front := its.order.Front(); front != nil; front = its.order.Front() {
+		e := front.Value.(*entry)
+		if now.Before(e.expireAt) {
+			return
+		}
+		its.remove(e)
+	}
+}
+
+func getFingerprint(request *model.PushPullMessage) ([sha256.Size]byte, bool) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256(b), true
+}
+
+// detachedContext keeps the values of its parent, but is neither canceled nor has a deadline with it.
+type detachedContext struct {
+	gocontext.Context
+}
+
+func (its detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (its detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (its detachedContext) Err() error {
+	return nil
+}
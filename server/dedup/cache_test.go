@@ -0,0 +1,128 @@
+package dedup
+
+import (
+	gocontext "context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCache(t *testing.T) {
+	newRequest := func(cuid string, seq uint32) *model.PushPullMessage {
+		return model.NewPushPullMessage(seq, &model.Client{CUID: cuid, Collection: "test"})
+	}
+
+	t.Run("Can return the same response for the retried request", func(t *testing.T) {
+		cache := New(time.Minute, 1<<20)
+		var runs int32
+		process := func(gocontext.Context) (*model.PushPullMessage, error) {
+			atomic.AddInt32(&runs, 1)
+			return &model.PushPullMessage{Cuid: "a"}, nil
+		}
+		res1, dup, err := cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), process)
+		require.NoError(t, err)
+		require.False(t, dup)
+		res2, dup, err := cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), process)
+		require.NoError(t, err)
+		require.True(t, dup)
+		require.Same(t, res1, res2)
+		require.Equal(t, int32(1), runs)
+
+		// a different request with the same key is processed again
+		_, dup, _ = cache.Do(gocontext.TODO(), "a:1", newRequest("b", 1), process)
+		require.False(t, dup)
+		require.Equal(t, int32(2), runs)
+	})
+
+	t.Run("Can wait for the request being processed", func(t *testing.T) {
+		cache := New(time.Minute, 1<<20)
+		var runs int32
+		blockCh := make(chan struct{})
+		process := func(gocontext.Context) (*model.PushPullMessage, error) {
+			atomic.AddInt32(&runs, 1)
+			<-blockCh
+			return &model.PushPullMessage{}, nil
+		}
+		wg := sync.WaitGroup{}
+		wg.Add(5)
+		for i := 0; i < 5; i++ {
+			go func() {
+				defer wg.Done()
+				_, _, err := cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), process)
+				require.NoError(t, err)
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(blockCh)
+		wg.Wait()
+		require.Equal(t, int32(1), runs)
+	})
+
+	t.Run("Can forget failed and expired requests", func(t *testing.T) {
+		cache := New(50*time.Millisecond, 1<<20)
+		_, _, err := cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), func(gocontext.Context) (*model.PushPullMessage, error) {
+			return nil, errors.ServerInternal.New(nil, "fail")
+		})
+		require.Error(t, err)
+		require.Equal(t, 0, cache.Len())
+
+		process := func(gocontext.Context) (*model.PushPullMessage, error) {
+			return &model.PushPullMessage{}, nil
+		}
+		_, _, _ = cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), process)
+		time.Sleep(100 * time.Millisecond)
+		_, dup, _ := cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), process)
+		require.False(t, dup)
+		_, _, _ = cache.Do(gocontext.TODO(), "a:2", newRequest("a", 2), process)
+		require.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("Can process on a context detached from the request", func(t *testing.T) {
+		cache := New(time.Minute, 1<<20)
+		goCtx, cancel := gocontext.WithCancel(gocontext.TODO())
+		_, _, err := cache.Do(goCtx, "a:1", newRequest("a", 1), func(processCtx gocontext.Context) (*model.PushPullMessage, error) {
+			cancel()
+			require.NoError(t, processCtx.Err())
+			return &model.PushPullMessage{}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, cache.Len())
+
+		// the response of a request processed longer than the TTL is not remembered
+		cache = New(50*time.Millisecond, 1<<20)
+		_, _, err = cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), func(processCtx gocontext.Context) (*model.PushPullMessage, error) {
+			<-processCtx.Done()
+			return &model.PushPullMessage{}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("Can forget the oldest responses exceeding the maximum bytes", func(t *testing.T) {
+		response := &model.PushPullMessage{Cuid: "0123456789"}
+		cache := New(time.Minute, 2*proto.Size(response))
+		process := func(gocontext.Context) (*model.PushPullMessage, error) {
+			return response, nil
+		}
+		for seq := uint32(1); seq <= 3; seq++ {
+			_, _, _ = cache.Do(gocontext.TODO(), fmt.Sprintf("a:%d", seq), newRequest("a", seq), process)
+		}
+		require.Equal(t, 2, cache.Len())
+		_, dup, _ := cache.Do(gocontext.TODO(), "a:3", newRequest("a", 3), process)
+		require.True(t, dup)
+		_, dup, _ = cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), process)
+		require.False(t, dup)
+
+		// a response larger than the maximum bytes is not remembered
+		cache = New(time.Minute, 1)
+		_, _, _ = cache.Do(gocontext.TODO(), "a:1", newRequest("a", 1), process)
+		require.Equal(t, 0, cache.Len())
+	})
+}
@@ -5,21 +5,42 @@ import (
 	"fmt"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/log"
+	"github.com/orda-io/orda/server/boltdb"
+	"github.com/orda-io/orda/server/compaction"
+	"github.com/orda-io/orda/server/constants"
 	"github.com/orda-io/orda/server/redis"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/retention"
+	"github.com/orda-io/orda/server/scheduler"
 	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/orda-io/orda/server/mongodb"
 )
 
 // OrdaServerConfig is a configuration of OrdaServer
 type OrdaServerConfig struct {
-	RPCServerPort   int             `json:"RPCServerPort"`
-	RestfulPort     int             `json:"RestfulPort"`
-	SwaggerBasePath string          `json:"SwaggerBasePath"`
-	SwaggerJSON     string          `json:"SwaggerJSON"`
-	Notification    string          `json:"Notification"`
-	Mongo           *mongodb.Config `json:"Mongo"`
-	Redis           *redis.Config   `json:"Redis,omitempty"`
+	RPCServerPort    int                `json:"RPCServerPort"`
+	RestfulPort      int                `json:"RestfulPort"`
+	SwaggerBasePath  string             `json:"SwaggerBasePath"`
+	SwaggerJSON      string             `json:"SwaggerJSON"`
+	Notification     string             `json:"Notification"`
+	Repository       string             `json:"Repository,omitempty"`
+	Mongo            *mongodb.Config    `json:"Mongo"`
+	Bolt             *boltdb.Config     `json:"Bolt,omitempty"`
+	Redis            *redis.Config      `json:"Redis,omitempty"`
+	Compaction       *compaction.Config `json:"Compaction,omitempty"`
+	Snapshot         *scheduler.Config  `json:"Snapshot,omitempty"`
+	Retention        *retention.Config  `json:"Retention,omitempty"`
+	CatchUpGap       *uint64            `json:"CatchUpGap,omitempty"`
+	PullLimit        *uint64            `json:"PullLimit,omitempty"`
+	ChunkSize        uint64             `json:"ChunkSize,omitempty"`
+	DedupSeconds     int64              `json:"DedupSeconds,omitempty"`
+	DedupMaxBytes    int                `json:"DedupMaxBytes,omitempty"`
+	WebSocketOrigins []string           `json:"WebSocketOrigins,omitempty"`
+	MaxMessageSize   int                `json:"MaxMessageSize,omitempty"`
 }
 
 // LoadOrdaServerConfig loads config from file.
@@ -52,6 +73,79 @@ func (its *OrdaServerConfig) GetRestfulAddr() string {
 	return fmt.Sprintf(":%d", its.RestfulPort)
 }
 
+// GetRepositoryType returns the type of repository; if not specified, MongoDB is used.
+func (its *OrdaServerConfig) GetRepositoryType() string {
+	if its.Repository == "" {
+		return repository.TypeMongoDB
+	}
+	return its.Repository
+}
+
+// GetCatchUpGap returns the number of operations that a client can fall behind before catching up with a snapshot.
+// If not specified, the default is used; 0 turns off catching up with a snapshot.
+func (its *OrdaServerConfig) GetCatchUpGap() uint64 {
+	if its.CatchUpGap == nil {
+		return constants.DefaultCatchUpGap
+	}
+	return *its.CatchUpGap
+}
+
+// GetPullLimit returns the maximum number of operations pulled in a PushPullPack.
+// If not specified, the default is used; 0 turns off the pagination of pulls.
+func (its *OrdaServerConfig) GetPullLimit() uint64 {
+	if its.PullLimit == nil {
+		return constants.DefaultPullLimit
+	}
+	return *its.PullLimit
+}
+
+// GetChunkSize returns the maximum number of bytes of a snapshot pulled in a PushPullPack.
+func (its *OrdaServerConfig) GetChunkSize() uint64 {
+	if its.ChunkSize == 0 {
+		return constants.DefaultSnapshotChunkSize
+	}
+	return its.ChunkSize
+}
+
+// GetDedupTTL returns how long the responses of push-pulls are remembered for retried requests.
+func (its *OrdaServerConfig) GetDedupTTL() time.Duration {
+	if its.DedupSeconds == 0 {
+		return time.Duration(constants.DefaultDedupSeconds) * time.Second
+	}
+	return time.Duration(its.DedupSeconds) * time.Second
+}
+
+// GetDedupMaxBytes returns the maximum number of bytes of the responses remembered for retried requests.
+func (its *OrdaServerConfig) GetDedupMaxBytes() int {
+	if its.DedupMaxBytes == 0 {
+		return constants.DefaultDedupMaxBytes
+	}
+	return its.DedupMaxBytes
+}
+
+// GetMaxMessageSize returns the maximum number of bytes of a message received from a client through gRPC or WebSocket.
+func (its *OrdaServerConfig) GetMaxMessageSize() int {
+	if its.MaxMessageSize == 0 {
+		return constants.DefaultMaxMessageSize
+	}
+	return its.MaxMessageSize
+}
+
+// IsAllowedWebSocketOrigin examines if a web page of the origin can open a WebSocket to the host. Besides the same
+// origin, WebSocketOrigins are allowed; "*" allows every origin. A request without Origin is not from a browser.
+func (its *OrdaServerConfig) IsAllowedWebSocketOrigin(origin string, host string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range its.WebSocketOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, host)
+}
+
 // String returns a marshaled string
 func (its *OrdaServerConfig) String() string {
 	b, _ := json.Marshal(its)
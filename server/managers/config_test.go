@@ -0,0 +1,41 @@
+package managers
+
+import (
+	"encoding/json"
+	"github.com/orda-io/orda/server/constants"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrdaServerConfig(t *testing.T) {
+	t.Run("Can use defaults if not specified", func(t *testing.T) {
+		conf := &OrdaServerConfig{}
+		require.NoError(t, json.Unmarshal([]byte(`{}`), conf))
+		require.Equal(t, constants.DefaultCatchUpGap, conf.GetCatchUpGap())
+		require.Equal(t, constants.DefaultMaxMessageSize, conf.GetMaxMessageSize())
+		require.Equal(t, constants.DefaultDedupMaxBytes, conf.GetDedupMaxBytes())
+		require.Equal(t, constants.DefaultPullLimit, conf.GetPullLimit())
+	})
+
+	t.Run("Can turn off catch-up and pagination with 0", func(t *testing.T) {
+		conf := &OrdaServerConfig{}
+		require.NoError(t, json.Unmarshal([]byte(`{"CatchUpGap": 0, "PullLimit": 0}`), conf))
+		require.Equal(t, uint64(0), conf.GetCatchUpGap())
+		require.Equal(t, uint64(0), conf.GetPullLimit())
+	})
+
+	t.Run("Can allow WebSocket origins", func(t *testing.T) {
+		conf := &OrdaServerConfig{}
+		require.True(t, conf.IsAllowedWebSocketOrigin("", "orda.io:29862"))
+		require.True(t, conf.IsAllowedWebSocketOrigin("https://orda.io:29862", "orda.io:29862"))
+		require.False(t, conf.IsAllowedWebSocketOrigin("https://evil.com", "orda.io:29862"))
+
+		conf.WebSocketOrigins = []string{"https://app.orda.io"}
+		require.True(t, conf.IsAllowedWebSocketOrigin("https://app.orda.io", "orda.io:29862"))
+		require.False(t, conf.IsAllowedWebSocketOrigin("https://evil.com", "orda.io:29862"))
+
+		conf.WebSocketOrigins = []string{"*"}
+		require.True(t, conf.IsAllowedWebSocketOrigin("https://evil.com", "orda.io:29862"))
+	})
+}
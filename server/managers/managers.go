@@ -1,26 +1,49 @@
 package managers
 
 import (
+	"fmt"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/boltdb"
+	"github.com/orda-io/orda/server/compaction"
+	"github.com/orda-io/orda/server/dedup"
+	"github.com/orda-io/orda/server/memdb"
 	"github.com/orda-io/orda/server/mongodb"
 	"github.com/orda-io/orda/server/notification"
 	"github.com/orda-io/orda/server/redis"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/retention"
+	"github.com/orda-io/orda/server/scheduler"
 	"github.com/orda-io/orda/server/utils"
 )
 
 // Managers are a bundle of infra
 type Managers struct {
-	Mongo    *mongodb.RepositoryMongo
-	Notifier *notification.Notifier
-	Redis    *redis.Client
+	Repository repository.Repository
+	Notifier   *notification.Notifier
+	Redis      *redis.Client
+	Compaction *compaction.Config
+	Retention  *retention.Config
+	Snapshots  *scheduler.Scheduler
+	Requests   *dedup.Cache
+	CatchUpGap uint64
+	PullLimit  uint64
+	ChunkSize  uint64
 }
 
 // New creates Managers with context and config
 func New(ctx iface.OrdaContext, conf *OrdaServerConfig) (*Managers, errors.OrdaError) {
 	var oErr errors.OrdaError
-	clients := &Managers{}
-	if clients.Mongo, oErr = mongodb.New(ctx, conf.Mongo); oErr != nil {
+	clients := &Managers{
+		Compaction: conf.Compaction,
+		Retention:  conf.Retention,
+		CatchUpGap: conf.GetCatchUpGap(),
+		PullLimit:  conf.GetPullLimit(),
+		ChunkSize:  conf.GetChunkSize(),
+		Snapshots:  scheduler.New(ctx, conf.Snapshot),
+		Requests:   dedup.New(conf.GetDedupTTL(), conf.GetDedupMaxBytes()),
+	}
+	if clients.Repository, oErr = newRepository(ctx, conf); oErr != nil {
 		return clients, oErr
 	}
 
@@ -34,6 +57,18 @@ func New(ctx iface.OrdaContext, conf *OrdaServerConfig) (*Managers, errors.OrdaE
 	return clients, nil
 }
 
+func newRepository(ctx iface.OrdaContext, conf *OrdaServerConfig) (repository.Repository, errors.OrdaError) {
+	switch conf.GetRepositoryType() {
+	case repository.TypeMongoDB:
+		return mongodb.New(ctx, conf.Mongo)
+	case repository.TypeMemory:
+		return memdb.New(ctx), nil
+	case repository.TypeBolt:
+		return boltdb.New(ctx, conf.Bolt)
+	}
+	return nil, errors.ServerInit.New(ctx.L(), fmt.Sprintf("unknown repository '%s'", conf.Repository))
+}
+
 // GetLock returns either a local or redis lock
 func (its *Managers) GetLock(ctx iface.OrdaContext, lockName string) utils.Lock {
 	return its.Redis.GetLock(ctx, lockName)
@@ -41,10 +76,11 @@ func (its *Managers) GetLock(ctx iface.OrdaContext, lockName string) utils.Lock
 
 // Close closes this Managers
 func (its *Managers) Close(ctx iface.OrdaContext) {
+	its.Snapshots.Close()
 	if err := its.Redis.Close(); err != nil {
 		ctx.L().Errorf("fail to close redis: %v", err)
 	}
-	if err := its.Mongo.Close(ctx); err != nil {
-		ctx.L().Errorf("fail to close mongo")
+	if err := its.Repository.Close(ctx); err != nil {
+		ctx.L().Errorf("fail to close repository")
 	}
 }
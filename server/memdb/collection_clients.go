@@ -0,0 +1,59 @@
+package memdb
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+)
+
+// UpdateClient updates a clientDoc; if not exists, a new clientDoc is inserted.
+func (its *RepositoryMemory) UpdateClient(ctx iface.OrdaContext, client *schema.ClientDoc) errors.OrdaError {
+	var stored schema.ClientDoc
+	if err := clone(ctx, client, &stored); err != nil {
+		return err
+	}
+	stored.UpdatedAt = time.Now()
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	its.clients[client.CUID] = &stored
+	return nil
+}
+
+// DeleteClient deletes the specified client.
+func (its *RepositoryMemory) DeleteClient(ctx iface.OrdaContext, cuid string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	if _, ok := its.clients[cuid]; !ok {
+		ctx.L().Warnf("fail to find a client to delete: `%s`", cuid)
+		return nil
+	}
+	delete(its.clients, cuid)
+	return nil
+}
+
+// GetClient returns a ClientDoc for the specified CUID.
+func (its *RepositoryMemory) GetClient(ctx iface.OrdaContext, cuid string) (*schema.ClientDoc, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	stored, ok := its.clients[cuid]
+	if !ok {
+		return nil, nil
+	}
+	var client schema.ClientDoc
+	if err := clone(ctx, stored, &client); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (its *RepositoryMemory) purgeAllCollectionClients(ctx iface.OrdaContext, collectionNum int32) {
+	var deleted = 0
+	for cuid, client := range its.clients {
+		if client.CollectionNum == collectionNum {
+			delete(its.clients, cuid)
+			deleted++
+		}
+	}
+	ctx.L().Infof("delete %d clients in collection#%d", deleted, collectionNum)
+}
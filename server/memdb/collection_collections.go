@@ -0,0 +1,63 @@
+package memdb
+
+import (
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+)
+
+// GetCollection gets a collectionDoc with the specified name.
+func (its *RepositoryMemory) GetCollection(ctx iface.OrdaContext, name string) (*schema.CollectionDoc, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	stored, ok := its.collections[name]
+	if !ok {
+		return nil, nil
+	}
+	collection := *stored
+	return &collection, nil
+}
+
+// DeleteCollection deletes collections with the specified name.
+func (its *RepositoryMemory) DeleteCollection(ctx iface.OrdaContext, name string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	delete(its.collections, name)
+	ctx.L().Infof("Collection '%s' is successfully removed", name)
+	return nil
+}
+
+// InsertCollection inserts a document for the specified collection.
+func (its *RepositoryMemory) InsertCollection(ctx iface.OrdaContext, name string) (*schema.CollectionDoc, errors.OrdaError) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	if _, ok := its.collections[name]; ok {
+		return nil, errors.ServerDBQuery.New(ctx.L(), fmt.Sprintf("duplicate collection '%s'", name))
+	}
+	its.colNum++
+	collection := &schema.CollectionDoc{
+		Name:      name,
+		Num:       its.colNum,
+		CreatedAt: time.Now(),
+	}
+	its.collections[name] = collection
+	ctx.L().Infof("insert collection: %+v", collection)
+	clone := *collection
+	return &clone, nil
+}
+
+// PurgeCollection purges all the documents and the real collection related to the collection.
+func (its *RepositoryMemory) PurgeCollection(ctx iface.OrdaContext, name string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	if collectionDoc, ok := its.collections[name]; ok {
+		ctx.L().Infof("purge collection#%d '%s'", collectionDoc.Num, name)
+		its.purgeAllCollectionDatatypes(ctx, collectionDoc.Num)
+		its.purgeAllCollectionClients(ctx, collectionDoc.Num)
+		delete(its.collections, name)
+	}
+	delete(its.realCollections, name)
+	return nil
+}
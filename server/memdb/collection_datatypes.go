@@ -0,0 +1,119 @@
+package memdb
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+)
+
+func (its *RepositoryMemory) cloneDatatype(
+	ctx iface.OrdaContext,
+	stored *schema.DatatypeDoc,
+) (*schema.DatatypeDoc, errors.OrdaError) {
+	if stored == nil {
+		return nil, nil
+	}
+	var datatype schema.DatatypeDoc
+	if err := clone(ctx, stored, &datatype); err != nil {
+		return nil, err
+	}
+	return &datatype, nil
+}
+
+// GetDatatype retrieves a datatypeDoc from memory.
+func (its *RepositoryMemory) GetDatatype(ctx iface.OrdaContext, duid string) (*schema.DatatypeDoc, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	return its.cloneDatatype(ctx, its.datatypes[duid])
+}
+
+func (its *RepositoryMemory) findDatatypeByKey(collectionNum int32, key string) *schema.DatatypeDoc {
+	for _, datatype := range its.datatypes {
+		if datatype.CollectionNum == collectionNum && datatype.Key == key {
+			return datatype
+		}
+	}
+	return nil
+}
+
+// GetDatatypeByKey gets a datatype with the specified key.
+func (its *RepositoryMemory) GetDatatypeByKey(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	key string,
+) (*schema.DatatypeDoc, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	return its.cloneDatatype(ctx, its.findDatatypeByKey(collectionNum, key))
+}
+
+// UpdateDatatype updates the datatypeDoc; if not exists, a new datatypeDoc is inserted.
+func (its *RepositoryMemory) UpdateDatatype(ctx iface.OrdaContext, datatype *schema.DatatypeDoc) errors.OrdaError {
+	datatype.UpdatedAt = time.Now()
+	stored, err := its.cloneDatatype(ctx, datatype)
+	if err != nil {
+		return err
+	}
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	its.datatypes[datatype.DUID] = stored
+	return nil
+}
+
+// BumpEra starts a new era of the datatype, and increments its Version.
+func (its *RepositoryMemory) BumpEra(ctx iface.OrdaContext, duid string) (uint32, errors.OrdaError) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	datatype, ok := its.datatypes[duid]
+	if !ok {
+		return 0, errors.ServerNoResource.New(ctx.L(), "datatype "+duid)
+	}
+	datatype.Era++
+	datatype.Version++
+	datatype.UpdatedAt = time.Now()
+	return datatype.Era, nil
+}
+
+// PurgeDatatype purges a datatype from memory.
+func (its *RepositoryMemory) PurgeDatatype(ctx iface.OrdaContext, collectionNum int32, key string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	doc := its.findDatatypeByKey(collectionNum, key)
+	if doc == nil {
+		ctx.L().Warnf("find no datatype to purge")
+		return nil
+	}
+	its.purgeOperations(ctx, collectionNum, doc.DUID)
+	delete(its.snapshots, doc.DUID)
+	delete(its.datatypes, doc.DUID)
+	ctx.L().Infof("purged datatype `%s(%d)`", key, collectionNum)
+	return nil
+}
+
+func (its *RepositoryMemory) purgeAllCollectionDatatypes(ctx iface.OrdaContext, collectionNum int32) {
+	var deletedOps, deletedSnapshots, deletedDatatypes = 0, 0, 0
+	for duid, opDocs := range its.operations {
+		if len(opDocs) > 0 && opDocs[0].CollectionNum == collectionNum {
+			deletedOps += len(opDocs)
+			delete(its.operations, duid)
+		}
+	}
+	ctx.L().Infof("delete %d operations in collection#%d", deletedOps, collectionNum)
+
+	for duid, snapshotDocs := range its.snapshots {
+		if len(snapshotDocs) > 0 && snapshotDocs[0].CollectionNum == collectionNum {
+			deletedSnapshots += len(snapshotDocs)
+			delete(its.snapshots, duid)
+		}
+	}
+	ctx.L().Infof("delete %d snapshots in collection#%d", deletedSnapshots, collectionNum)
+
+	for duid, datatype := range its.datatypes {
+		if datatype.CollectionNum == collectionNum {
+			delete(its.datatypes, duid)
+			deletedDatatypes++
+		}
+	}
+	ctx.L().Infof("delete %d datatypes in collection#%d", deletedDatatypes, collectionNum)
+}
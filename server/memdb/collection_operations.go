@@ -0,0 +1,235 @@
+package memdb
+
+import (
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/schema"
+	"sort"
+	"time"
+)
+
+// InsertOperations inserts operations into memory. Operations of each datatype are kept in the order of sseq.
+func (its *RepositoryMemory) InsertOperations(ctx iface.OrdaContext, operations []*schema.OperationDoc) errors.OrdaError {
+	stored, err := cloneOperations(ctx, operations)
+	if err != nil {
+		return err
+	}
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	return its.insertOperations(ctx, stored)
+}
+
+// CommitPushPull inserts the operations and updates the datatype at once if its Version is still stored.
+func (its *RepositoryMemory) CommitPushPull(
+	ctx iface.OrdaContext,
+	datatype *schema.DatatypeDoc,
+	operations []*schema.OperationDoc,
+) errors.OrdaError {
+	stored, err := cloneOperations(ctx, operations)
+	if err != nil {
+		return err
+	}
+	datatype.UpdatedAt = time.Now()
+	storedDatatype, err := its.cloneDatatype(ctx, datatype)
+	if err != nil {
+		return err
+	}
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	var version uint64 = 0
+	if prev, ok := its.datatypes[datatype.DUID]; ok {
+		version = prev.Version
+	} else if other := its.findDatatypeByKey(datatype.CollectionNum, datatype.Key); other != nil {
+		msg := fmt.Sprintf("key '%s' is already used by %s", datatype.Key, other.DUID)
+		return errors.ServerDBConflict.New(ctx.L(), msg)
+	}
+	if version != datatype.Version {
+		msg := fmt.Sprintf("version of %s is %d, not %d", datatype.DUID, version, datatype.Version)
+		return errors.ServerDBConflict.New(ctx.L(), msg)
+	}
+	if err := its.insertOperations(ctx, stored); err != nil {
+		return err
+	}
+	if prev, ok := its.datatypes[datatype.DUID]; ok {
+		storedDatatype.Sseq.Begin, storedDatatype.Sseq.Safe = prev.Sseq.Begin, prev.Sseq.Safe
+		storedDatatype.Era = prev.Era
+	}
+	datatype.Version++
+	storedDatatype.Version = datatype.Version
+	its.datatypes[datatype.DUID] = storedDatatype
+	return nil
+}
+
+// CompactOperations advances Sseq.Begin of the datatype to begin, and removes the operations before it.
+func (its *RepositoryMemory) CompactOperations(
+	ctx iface.OrdaContext,
+	duid string,
+	begin, safe uint64,
+) (int64, errors.OrdaError) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	datatype, ok := its.datatypes[duid]
+	if !ok {
+		return 0, errors.ServerNoResource.New(ctx.L(), "datatype "+duid)
+	}
+	if datatype.Sseq.Begin < begin {
+		datatype.Sseq.Begin = begin
+	}
+	datatype.Sseq.Safe = safe
+	opDocs := its.operations[duid]
+	idx := sort.Search(len(opDocs), func(i int) bool {
+		return opDocs[i].Sseq >= begin
+	})
+	if idx == len(opDocs) {
+		delete(its.operations, duid)
+	} else {
+		its.operations[duid] = opDocs[idx:]
+	}
+	return int64(idx), nil
+}
+
+// RepairOperations removes orphaned operations, i.e., ones beyond Sseq.End of their datatype or without datatype.
+func (its *RepositoryMemory) RepairOperations(ctx iface.OrdaContext) (int64, errors.OrdaError) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	var repaired int64 = 0
+	for duid, opDocs := range its.operations {
+		var sseqEnd uint64 = 0
+		if datatype, ok := its.datatypes[duid]; ok {
+			sseqEnd = datatype.Sseq.End
+		}
+		idx := sort.Search(len(opDocs), func(i int) bool {
+			return opDocs[i].Sseq > sseqEnd
+		})
+		if deleted := len(opDocs) - idx; deleted > 0 {
+			ctx.L().Warnf("repair %d orphaned operations of %s beyond sseq %d", deleted, duid, sseqEnd)
+			repaired += int64(deleted)
+		}
+		if idx == 0 {
+			delete(its.operations, duid)
+		} else {
+			its.operations[duid] = opDocs[:idx]
+		}
+	}
+	return repaired, nil
+}
+
+func cloneOperations(ctx iface.OrdaContext, operations []*schema.OperationDoc) ([]*schema.OperationDoc, errors.OrdaError) {
+	var stored = make([]*schema.OperationDoc, len(operations))
+	for i, op := range operations {
+		stored[i] = &schema.OperationDoc{}
+		if err := clone(ctx, op, stored[i]); err != nil {
+			return nil, err
+		}
+	}
+	return stored, nil
+}
+
+func (its *RepositoryMemory) insertOperations(ctx iface.OrdaContext, stored []*schema.OperationDoc) errors.OrdaError {
+	for _, op := range stored {
+		opDocs := its.operations[op.DUID]
+		idx := sort.Search(len(opDocs), func(i int) bool {
+			return opDocs[i].Sseq >= op.Sseq
+		})
+		if idx < len(opDocs) && opDocs[idx].Sseq == op.Sseq {
+			msg := fmt.Sprintf("duplicate operation '%s'", op.ID)
+			return errors.ServerDBQuery.New(ctx.L(), msg)
+		}
+	}
+	for _, op := range stored {
+		opDocs := its.operations[op.DUID]
+		idx := sort.Search(len(opDocs), func(i int) bool {
+			return opDocs[i].Sseq >= op.Sseq
+		})
+		opDocs = append(opDocs, nil)
+		copy(opDocs[idx+1:], opDocs[idx:])
+		opDocs[idx] = op
+		its.operations[op.DUID] = opDocs
+	}
+	return nil
+}
+
+// DeleteOperation deletes operations for the specified sseq
+func (its *RepositoryMemory) DeleteOperation(ctx iface.OrdaContext, duid string, sseq uint32) (int64, errors.OrdaError) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	opDocs := its.operations[duid]
+	for i, op := range opDocs {
+		if op.Sseq == uint64(sseq) {
+			its.operations[duid] = append(opDocs[:i], opDocs[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// GetOperations gets operations of the specified range.
+func (its *RepositoryMemory) GetOperations(
+	ctx iface.OrdaContext,
+	duid string,
+	from, to uint64,
+) (model.OpList, []uint64, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	var opList []*model.Operation
+	var sseqList []uint64
+	for _, opDoc := range its.operations[duid] {
+		if opDoc.Sseq < from {
+			continue
+		}
+		if to != constants.InfinitySseq && opDoc.Sseq > to {
+			break
+		}
+		var op schema.OperationDoc
+		if err := clone(ctx, opDoc, &op); err != nil {
+			return nil, nil, err
+		}
+		opList = append(opList, op.GetOperation())
+		sseqList = append(sseqList, op.Sseq)
+	}
+	return opList, sseqList, nil
+}
+
+// GetLastSseqAt returns the sseq of the last operation created at or before the time, or 0 if there is none.
+func (its *RepositoryMemory) GetLastSseqAt(ctx iface.OrdaContext, duid string, at time.Time) (uint64, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	opDocs := its.operations[duid]
+	for i := len(opDocs) - 1; i >= 0; i-- {
+		if !opDocs[i].CreatedAt.After(at) {
+			return opDocs[i].Sseq, nil
+		}
+	}
+	return 0, nil
+}
+
+// PurgeOperations purges operations for the specified datatype.
+func (its *RepositoryMemory) PurgeOperations(ctx iface.OrdaContext, collectionNum int32, duid string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	its.purgeOperations(ctx, collectionNum, duid)
+	return nil
+}
+
+func (its *RepositoryMemory) purgeOperations(ctx iface.OrdaContext, collectionNum int32, duid string) {
+	var remained []*schema.OperationDoc
+	for _, op := range its.operations[duid] {
+		if op.CollectionNum != collectionNum {
+			remained = append(remained, op)
+		}
+	}
+	deleted := len(its.operations[duid]) - len(remained)
+	if len(remained) == 0 {
+		delete(its.operations, duid)
+	} else {
+		its.operations[duid] = remained
+	}
+	if deleted > 0 {
+		ctx.L().Infof("deleted %d operations of %s(%d)", deleted, duid, collectionNum)
+		return
+	}
+	ctx.L().Warnf("deleted no operations")
+}
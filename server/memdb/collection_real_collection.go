@@ -0,0 +1,73 @@
+package memdb
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetOrCreateRealCollection is a method that gets or creates a collection of snapshot
+func (its *RepositoryMemory) GetOrCreateRealCollection(ctx iface.OrdaContext, name string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	if _, ok := its.realCollections[name]; !ok {
+		its.realCollections[name] = make(map[string]bson.Raw)
+		ctx.L().Infof("create collection:%s", name)
+	}
+	return nil
+}
+
+// InsertRealSnapshot inserts a snapshot for real collection.
+func (its *RepositoryMemory) InsertRealSnapshot(
+	ctx iface.OrdaContext,
+	collectionName string,
+	id string,
+	data interface{},
+	sseq uint64,
+) errors.OrdaError {
+	marshaled, err := bson.Marshal(data)
+	if err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error(), data)
+	}
+	var bsonM = bson.M{}
+	if err := bson.Unmarshal(marshaled, &bsonM); err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	bsonM["_id"] = id
+	bsonM[repository.Ver] = sseq
+	raw, err := bson.Marshal(bsonM)
+	if err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	collection, ok := its.realCollections[collectionName]
+	if !ok {
+		collection = make(map[string]bson.Raw)
+		its.realCollections[collectionName] = collection
+	}
+	collection[id] = raw
+	return nil
+}
+
+// GetRealSnapshot returns a real snapshot
+func (its *RepositoryMemory) GetRealSnapshot(
+	ctx iface.OrdaContext,
+	collectionName string,
+	id string,
+) (map[string]interface{}, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	raw, ok := its.realCollections[collectionName][id]
+	if !ok {
+		return nil, nil
+	}
+	var snap map[string]interface{}
+	if err := bson.Unmarshal(raw, &snap); err != nil {
+		return nil, errors.ServerDBDecode.New(ctx.L(), err.Error())
+	}
+	return snap, nil
+}
@@ -0,0 +1,114 @@
+package memdb
+
+import (
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"sort"
+	"time"
+)
+
+// GetLatestSnapshot gets the latest snapshot for the specified datatype.
+func (its *RepositoryMemory) GetLatestSnapshot(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+) (*schema.SnapshotDoc, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	snapshotDocs := its.snapshots[duid]
+	for i := len(snapshotDocs) - 1; i >= 0; i-- {
+		if snapshotDocs[i].CollectionNum == collectionNum {
+			var snapshot schema.SnapshotDoc
+			if err := clone(ctx, snapshotDocs[i], &snapshot); err != nil {
+				return nil, err
+			}
+			return &snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// InsertSnapshot inserts a snapshot for the specified datatype.
+func (its *RepositoryMemory) InsertSnapshot(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+	sseq uint64,
+	meta []byte,
+	snapshot []byte,
+) errors.OrdaError {
+	snap := &schema.SnapshotDoc{
+		ID:            fmt.Sprintf("%s:%d", duid, sseq),
+		CollectionNum: collectionNum,
+		DUID:          duid,
+		Sseq:          sseq,
+		Meta:          string(meta),
+		Snapshot:      append([]byte{}, snapshot...),
+		CreatedAt:     time.Now(),
+	}
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	snapshotDocs := its.snapshots[duid]
+	idx := sort.Search(len(snapshotDocs), func(i int) bool {
+		return snapshotDocs[i].Sseq >= sseq
+	})
+	if idx < len(snapshotDocs) && snapshotDocs[idx].Sseq == sseq {
+		return errors.ServerDBQuery.New(ctx.L(), fmt.Sprintf("duplicate snapshot '%s'", snap.ID))
+	}
+	snapshotDocs = append(snapshotDocs, nil)
+	copy(snapshotDocs[idx+1:], snapshotDocs[idx:])
+	snapshotDocs[idx] = snap
+	its.snapshots[duid] = snapshotDocs
+	ctx.L().Infof("insert snapshot: %s", snap.ID)
+	return nil
+}
+
+// GetSnapshots returns all the snapshots of the datatype in ascending order of sseq.
+func (its *RepositoryMemory) GetSnapshots(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+) ([]*schema.SnapshotDoc, errors.OrdaError) {
+	its.mutex.RLock()
+	defer its.mutex.RUnlock()
+	var snapshots []*schema.SnapshotDoc
+	for _, snapshotDoc := range its.snapshots[duid] {
+		if snapshotDoc.CollectionNum != collectionNum {
+			continue
+		}
+		var snapshot schema.SnapshotDoc
+		if err := clone(ctx, snapshotDoc, &snapshot); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshots removes the snapshots of the datatype at the specified sseqs.
+func (its *RepositoryMemory) DeleteSnapshots(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+	sseqList []uint64,
+) (int64, errors.OrdaError) {
+	deleting := make(map[uint64]bool)
+	for _, sseq := range sseqList {
+		deleting[sseq] = true
+	}
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	var deleted int64
+	var remained []*schema.SnapshotDoc
+	for _, snapshotDoc := range its.snapshots[duid] {
+		if snapshotDoc.CollectionNum == collectionNum && deleting[snapshotDoc.Sseq] {
+			deleted++
+			continue
+		}
+		remained = append(remained, snapshotDoc)
+	}
+	its.snapshots[duid] = remained
+	return deleted, nil
+}
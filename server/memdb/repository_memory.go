@@ -0,0 +1,53 @@
+package memdb
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/schema"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// RepositoryMemory is a volatile repository which keeps every document in memory.
+// Documents are stored and returned as deep copies encoded in BSON, so that it behaves like RepositoryMongo.
+type RepositoryMemory struct {
+	mutex           sync.RWMutex
+	colNum          int32
+	clients         map[string]*schema.ClientDoc
+	collections     map[string]*schema.CollectionDoc
+	datatypes       map[string]*schema.DatatypeDoc
+	operations      map[string][]*schema.OperationDoc
+	snapshots       map[string][]*schema.SnapshotDoc
+	realCollections map[string]map[string]bson.Raw
+}
+
+// New creates a new RepositoryMemory
+func New(ctx iface.OrdaContext) *RepositoryMemory {
+	ctx.L().Infof("New in-memory repository")
+	return &RepositoryMemory{
+		clients:         make(map[string]*schema.ClientDoc),
+		collections:     make(map[string]*schema.CollectionDoc),
+		datatypes:       make(map[string]*schema.DatatypeDoc),
+		operations:      make(map[string][]*schema.OperationDoc),
+		snapshots:       make(map[string][]*schema.SnapshotDoc),
+		realCollections: make(map[string]map[string]bson.Raw),
+	}
+}
+
+// clone deeply copies src to dst through BSON encoding
+func clone(ctx iface.OrdaContext, src interface{}, dst interface{}) errors.OrdaError {
+	data, err := bson.Marshal(src)
+	if err != nil {
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	if err := bson.Unmarshal(data, dst); err != nil {
+		return errors.ServerDBDecode.New(ctx.L(), err.Error())
+	}
+	return nil
+}
+
+// Close closes the repository of memory
+func (its *RepositoryMemory) Close(ctx iface.OrdaContext) errors.OrdaError {
+	return nil
+}
@@ -0,0 +1,15 @@
+package memdb_test
+
+import (
+	gocontext "context"
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/memdb"
+	"github.com/orda-io/orda/server/testonly"
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.NewOrdaContext(gocontext.TODO(), constants.TagTest).UpdateCollectionTags(t.Name(), 0)
+	testonly.TestRepository(t, ctx, memdb.New(ctx))
+}
@@ -40,8 +40,8 @@ func (its *MongoCollections) InsertCollection(
 	ctx iface.OrdaContext,
 	name string,
 ) (collection *schema.CollectionDoc, err errors.OrdaError) {
-	if err := its.doTransaction(ctx, func() errors.OrdaError {
-		num, err := its.GetNextCollectionNum(ctx)
+	if err := its.doTransaction(ctx, func(txCtx iface.OrdaContext) errors.OrdaError {
+		num, err := its.GetNextCollectionNum(txCtx)
 		if err != nil {
 			return err
 		}
@@ -50,7 +50,7 @@ func (its *MongoCollections) InsertCollection(
 			Num:       num,
 			CreatedAt: time.Now(),
 		}
-		_, err2 := its.collections.InsertOne(ctx, collection)
+		_, err2 := its.collections.InsertOne(txCtx, collection)
 		if err2 != nil {
 			return errors.ServerDBQuery.New(ctx.L(), err2.Error())
 		}
@@ -64,8 +64,8 @@ func (its *MongoCollections) InsertCollection(
 
 // PurgeAllDocumentsOfCollection purges all data for the specified collection.
 func (its *MongoCollections) PurgeAllDocumentsOfCollection(ctx iface.OrdaContext, name string) errors.OrdaError {
-	if err := its.doTransaction(ctx, func() errors.OrdaError {
-		collectionDoc, err := its.GetCollection(ctx, name)
+	if err := its.doTransaction(ctx, func(txCtx iface.OrdaContext) errors.OrdaError {
+		collectionDoc, err := its.GetCollection(txCtx, name)
 		if err != nil {
 			return err
 		}
@@ -73,7 +73,7 @@ func (its *MongoCollections) PurgeAllDocumentsOfCollection(ctx iface.OrdaContext
 			return nil
 		}
 		ctx.L().Infof("purge collection#%d '%s'", collectionDoc.Num, name)
-		return its.purgeAllDocumentsOfCollectionNum(ctx, collectionDoc.Num)
+		return its.purgeAllDocumentsOfCollectionNum(txCtx, collectionDoc.Num)
 	}); err != nil {
 		return err
 	}
@@ -88,7 +88,7 @@ func (its *MongoCollections) purgeAllDocumentsOfCollectionNum(ctx iface.OrdaCont
 	if err := its.purgeAllCollectionClients(ctx, collectionNum); err != nil {
 		return err
 	}
-	filter := schema.GetFilter().AddFilterEQ(schema.CollectionDocFields.Name, collectionNum)
+	filter := schema.GetFilter().AddFilterEQ(schema.CollectionDocFields.Num, collectionNum)
 
 	result, err2 := its.collections.DeleteOne(ctx, filter)
 	if err2 != nil {
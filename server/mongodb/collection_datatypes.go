@@ -1,10 +1,14 @@
 package mongodb
 
 import (
+	"fmt"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/server/schema"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 // GetDatatype retrieves a datatypeDoc from MongoDB
@@ -67,6 +71,57 @@ func (its *MongoCollections) UpdateDatatype(
 	return errors.ServerDBQuery.New(ctx.L(), "fail to update datatype")
 }
 
+// BumpEra starts a new era of the datatype, and increments its Version.
+func (its *MongoCollections) BumpEra(ctx iface.OrdaContext, duid string) (uint32, errors.OrdaError) {
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{
+			{Key: schema.DatatypeDocFields.Era, Value: 1},
+			{Key: schema.DatatypeDocFields.Version, Value: 1},
+		}},
+		{Key: "$set", Value: bson.D{{Key: schema.DatatypeDocFields.UpdatedAt, Value: time.Now()}}},
+	}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	result := its.datatypes.FindOneAndUpdate(ctx, schema.FilterByID(duid), update, opt)
+	if err := result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, errors.ServerNoResource.New(ctx.L(), "datatype "+duid)
+		}
+		return 0, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	var datatype schema.DatatypeDoc
+	if err := result.Decode(&datatype); err != nil {
+		return 0, errors.ServerDBDecode.New(ctx.L(), err.Error())
+	}
+	return datatype.Era, nil
+}
+
+// compareAndUpdateDatatype updates the datatype only if its stored Version equals that of the datatype.
+// A datatype which does not exist yet is inserted when its Version is 0. Sseq.Begin, Sseq.Safe and Era are kept as stored.
+func (its *MongoCollections) compareAndUpdateDatatype(
+	ctx iface.OrdaContext,
+	datatype *schema.DatatypeDoc,
+) errors.OrdaError {
+	f := schema.FilterByID(datatype.DUID)
+	if datatype.Version == 0 { // the datatype stored before Version is introduced has no Version
+		f = f.AddFilterIn(schema.DatatypeDocFields.Version, bson.A{0, nil})
+	} else {
+		f = f.AddFilterEQ(schema.DatatypeDocFields.Version, datatype.Version)
+	}
+	opt := options.Update().SetUpsert(datatype.Version == 0)
+	result, err := its.datatypes.UpdateOne(ctx, f, datatype.ToCommitBSON(), opt)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) { // the datatype or its key exists, but its version is not 0
+			return errors.ServerDBConflict.New(ctx.L(), err.Error())
+		}
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		msg := fmt.Sprintf("version of %s is not %d", datatype.DUID, datatype.Version)
+		return errors.ServerDBConflict.New(ctx.L(), msg)
+	}
+	return nil
+}
+
 func (its *MongoCollections) purgeAllCollectionDatatypes(
 	ctx iface.OrdaContext,
 	collectionNum int32,
@@ -108,12 +163,12 @@ func (its *MongoCollections) PurgeDatatype(
 		ctx.L().Warnf("find no datatype to purge")
 		return nil
 	}
-	if err := its.doTransaction(ctx, func() errors.OrdaError {
-		if err := its.PurgeOperations(ctx, collectionNum, doc.DUID); err != nil {
+	if err := its.doTransaction(ctx, func(txCtx iface.OrdaContext) errors.OrdaError {
+		if err := its.PurgeOperations(txCtx, collectionNum, doc.DUID); err != nil {
 			return err
 		}
 		filter := schema.GetFilter().AddFilterEQ(schema.DatatypeDocFields.DUID, doc.DUID)
-		result, err := its.datatypes.DeleteOne(ctx, filter)
+		result, err := its.datatypes.DeleteOne(txCtx, filter)
 		if err != nil {
 			return errors.ServerDBQuery.New(ctx.L(), err.Error())
 		}
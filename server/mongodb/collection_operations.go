@@ -6,8 +6,10 @@ import (
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/server/schema"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/orda-io/orda/server/constants"
@@ -16,21 +18,42 @@ import (
 // InsertOperations inserts operations into MongoDB
 func (its *MongoCollections) InsertOperations(
 	ctx iface.OrdaContext,
-	operations []interface{},
+	operations []*schema.OperationDoc,
 ) errors.OrdaError {
-	if operations == nil {
-		return nil
+	_, err := its.insertOperations(ctx, operations)
+	return err
+}
+
+// insertOperations inserts operations in order, and returns the number of operations which can have been inserted.
+// When an operation fails to be inserted, the following ones are not inserted.
+func (its *MongoCollections) insertOperations(
+	ctx iface.OrdaContext,
+	operations []*schema.OperationDoc,
+) (int, errors.OrdaError) {
+	if len(operations) == 0 {
+		return 0, nil
+	}
+	var docs = make([]interface{}, len(operations))
+	for i, op := range operations {
+		docs[i] = op
 	}
-	result, err := its.operations.InsertMany(ctx, operations)
+	result, err := its.operations.InsertMany(ctx, docs)
 	if err != nil {
-		return errors.ServerDBQuery.New(ctx.L(), err.Error())
+		inserted := len(operations)
+		if bulkErr, ok := err.(mongo.BulkWriteException); ok && len(bulkErr.WriteErrors) > 0 {
+			inserted = bulkErr.WriteErrors[0].Index
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return inserted, errors.ServerDBConflict.New(ctx.L(), err.Error())
+		}
+		return inserted, errors.ServerDBQuery.New(ctx.L(), err.Error())
 	}
 	if len(result.InsertedIDs) != len(operations) {
 		msg := fmt.Sprintf("the inserted operations (%d) are less than all the intended ones (%d)",
 			len(result.InsertedIDs), len(operations))
-		return errors.ServerDBQuery.New(ctx.L(), msg)
+		return len(operations), errors.ServerDBQuery.New(ctx.L(), msg)
 	}
-	return nil
+	return len(operations), nil
 }
 
 // DeleteOperation deletes operations for the specified sseq
@@ -84,6 +107,22 @@ func (its *MongoCollections) GetOperations(
 	return opList, sseqList, nil
 }
 
+// GetLastSseqAt returns the sseq of the last operation created at or before the time, or 0 if there is none.
+func (its *MongoCollections) GetLastSseqAt(ctx iface.OrdaContext, duid string, at time.Time) (uint64, errors.OrdaError) {
+	f := schema.GetFilter().
+		AddFilterEQ(schema.OperationDocFields.DUID, duid).
+		AddFilterLTE(schema.OperationDocFields.CreatedAt, at)
+	opt := options.FindOne().SetSort(bson.D{{Key: schema.OperationDocFields.Sseq, Value: -1}})
+	var opDoc schema.OperationDoc
+	if err := its.operations.FindOne(ctx, f, opt).Decode(&opDoc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return opDoc.Sseq, nil
+}
+
 // PurgeOperations purges operations for the specified datatype.
 func (its *MongoCollections) PurgeOperations(ctx iface.OrdaContext, collectionNum int32, duid string) errors.OrdaError {
 	f := schema.GetFilter().
@@ -100,3 +139,134 @@ func (its *MongoCollections) PurgeOperations(ctx iface.OrdaContext, collectionNu
 	ctx.L().Warnf("deleted no operations")
 	return nil
 }
+
+// CommitPushPull inserts the operations and updates the datatype in a transaction if its Version is still stored.
+// Without transaction support, the inserted operations are removed if either of them fails.
+func (its *MongoCollections) CommitPushPull(
+	ctx iface.OrdaContext,
+	datatype *schema.DatatypeDoc,
+	operations []*schema.OperationDoc,
+) errors.OrdaError {
+	if err := its.doTransaction(ctx, func(txCtx iface.OrdaContext) errors.OrdaError {
+		if inserted, err := its.insertOperations(txCtx, operations); err != nil {
+			its.removeUncommittedOperations(ctx, datatype.DUID, operations[:inserted])
+			return err
+		}
+		if err := its.compareAndUpdateDatatype(txCtx, datatype); err != nil {
+			its.removeUncommittedOperations(ctx, datatype.DUID, operations)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	datatype.Version++
+	return nil
+}
+
+// removeUncommittedOperations removes the operations inserted by a failed commit without transaction support.
+// Only the operations of the pushing client are removed, since the others in the range belong to a concurrent commit.
+func (its *MongoCollections) removeUncommittedOperations(
+	ctx iface.OrdaContext,
+	duid string,
+	operations []*schema.OperationDoc,
+) {
+	if its.supportTransaction || len(operations) == 0 {
+		return
+	}
+	from, to := operations[0].Sseq, operations[len(operations)-1].Sseq
+	if _, err := its.deleteOperationsInRange(ctx, duid, operations[0].OpID.CUID, from, to); err != nil {
+		ctx.L().Errorf("fail to remove operations of failed commit: %v", err)
+	}
+}
+
+func (its *MongoCollections) deleteOperationsAfter(ctx iface.OrdaContext, duid string, sseq uint64) (int64, errors.OrdaError) {
+	f := schema.GetFilter().
+		AddFilterEQ(schema.OperationDocFields.DUID, duid).
+		AddFilterGT(schema.OperationDocFields.Sseq, sseq)
+	return its.deleteOperations(ctx, f)
+}
+
+func (its *MongoCollections) deleteOperationsInRange(
+	ctx iface.OrdaContext,
+	duid, cuid string,
+	from, to uint64,
+) (int64, errors.OrdaError) {
+	f := schema.GetFilter().
+		AddFilterEQ(schema.OperationDocFields.DUID, duid).
+		AddFilterEQ(schema.OperationDocFields.OpCUID, cuid).
+		AddFilterGTE(schema.OperationDocFields.Sseq, from).
+		AddFilterLTE(schema.OperationDocFields.Sseq, to)
+	return its.deleteOperations(ctx, f)
+}
+
+func (its *MongoCollections) deleteOperations(ctx iface.OrdaContext, f schema.Filter) (int64, errors.OrdaError) {
+	result, err := its.operations.DeleteMany(ctx, f)
+	if err != nil {
+		return 0, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return result.DeletedCount, nil
+}
+
+// CompactOperations advances Sseq.Begin of the datatype to begin, and removes the operations before it.
+// Sseq.Begin is advanced first so that no push-pull would try to read the operations being removed.
+func (its *MongoCollections) CompactOperations(
+	ctx iface.OrdaContext,
+	duid string,
+	begin, safe uint64,
+) (int64, errors.OrdaError) {
+	f := schema.FilterByID(duid)
+	update := bson.D{
+		{Key: "$max", Value: bson.D{{Key: schema.DatatypeDocFields.SseqBegin, Value: begin}}},
+		{Key: "$set", Value: bson.D{{Key: schema.DatatypeDocFields.SseqSafe, Value: safe}}},
+	}
+	result, err := its.datatypes.UpdateOne(ctx, f, update)
+	if err != nil {
+		return 0, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return 0, errors.ServerNoResource.New(ctx.L(), "datatype "+duid)
+	}
+	f = schema.GetFilter().
+		AddFilterEQ(schema.OperationDocFields.DUID, duid).
+		AddFilterLT(schema.OperationDocFields.Sseq, begin)
+	return its.deleteOperations(ctx, f)
+}
+
+// RepairOperations removes orphaned operations, i.e., ones beyond Sseq.End of their datatype or without datatype.
+// Without transaction support, nothing is repaired, since the operations beyond Sseq.End can be being committed by
+// another server.
+func (its *MongoCollections) RepairOperations(ctx iface.OrdaContext) (int64, errors.OrdaError) {
+	if !its.supportTransaction {
+		ctx.L().Infof("skip repairing operations without transaction support")
+		return 0, nil
+	}
+	duids, err := its.operations.Distinct(ctx, schema.OperationDocFields.DUID, schema.GetFilter())
+	if err != nil {
+		return 0, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	var repaired int64 = 0
+	for _, v := range duids {
+		duid, ok := v.(string)
+		if !ok {
+			continue
+		}
+		datatype, err := its.GetDatatype(ctx, duid)
+		if err != nil {
+			return repaired, err
+		}
+		var sseqEnd uint64 = 0
+		if datatype != nil {
+			sseqEnd = datatype.Sseq.End
+		}
+		deleted, err := its.deleteOperationsAfter(ctx, duid, sseqEnd)
+		if err != nil {
+			return repaired, err
+		}
+		if deleted > 0 {
+			ctx.L().Warnf("repair %d orphaned operations of %s beyond sseq %d", deleted, duid, sseqEnd)
+			repaired += deleted
+		}
+	}
+	return repaired, nil
+}
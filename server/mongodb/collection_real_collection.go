@@ -3,6 +3,7 @@ package mongodb
 import (
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/repository"
 	"github.com/orda-io/orda/server/schema"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -11,7 +12,7 @@ import (
 
 const (
 	// Ver is the field name that notes the version.
-	Ver = "_orda_ver_"
+	Ver = repository.Ver
 )
 
 // InsertRealSnapshot inserts a snapshot for real collection.
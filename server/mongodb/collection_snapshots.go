@@ -67,3 +67,46 @@ func (its *MongoCollections) InsertSnapshot(
 	}
 	return nil
 }
+
+// GetSnapshots returns all the snapshots of the datatype in ascending order of sseq.
+func (its *MongoCollections) GetSnapshots(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+) ([]*schema.SnapshotDoc, errors.OrdaError) {
+	f := schema.GetFilter().
+		AddFilterEQ(schema.SnapshotDocFields.CollectionNum, collectionNum).
+		AddFilterEQ(schema.SnapshotDocFields.DUID, duid)
+	opt := options.Find()
+	opt.SetSort(bson.D{{
+		Key:   schema.SnapshotDocFields.Sseq,
+		Value: 1,
+	}})
+	cursor, err := its.snapshots.Find(ctx, f, opt)
+	if err != nil {
+		return nil, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	var snapshots []*schema.SnapshotDoc
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, errors.ServerDBDecode.New(ctx.L(), err.Error())
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshots removes the snapshots of the datatype at the specified sseqs.
+func (its *MongoCollections) DeleteSnapshots(
+	ctx iface.OrdaContext,
+	collectionNum int32,
+	duid string,
+	sseqList []uint64,
+) (int64, errors.OrdaError) {
+	f := schema.GetFilter().
+		AddFilterEQ(schema.SnapshotDocFields.CollectionNum, collectionNum).
+		AddFilterEQ(schema.SnapshotDocFields.DUID, duid).
+		AddFilterIn(schema.SnapshotDocFields.Sseq, sseqList)
+	result, err := its.snapshots.DeleteMany(ctx, f)
+	if err != nil {
+		return 0, errors.ServerDBQuery.New(ctx.L(), err.Error())
+	}
+	return result.DeletedCount, nil
+}
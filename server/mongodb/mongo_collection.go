@@ -12,13 +12,14 @@ import (
 
 // MongoCollections is a bunch of collections used to provide
 type MongoCollections struct {
-	mongoClient *mongo.Client
-	clients     *mongo.Collection
-	counters    *mongo.Collection
-	snapshots   *mongo.Collection
-	datatypes   *mongo.Collection
-	operations  *mongo.Collection
-	collections *mongo.Collection
+	mongoClient        *mongo.Client
+	supportTransaction bool
+	clients            *mongo.Collection
+	counters           *mongo.Collection
+	snapshots          *mongo.Collection
+	datatypes          *mongo.Collection
+	operations         *mongo.Collection
+	collections        *mongo.Collection
 }
 
 // Create creates an empty collection by inserting a document and immediately deleting it.
@@ -59,29 +60,47 @@ func (its *MongoCollections) createIndex(
 	return nil
 }
 
+// sessionContext is an OrdaContext that carries a session of MongoDB in order to run queries in a transaction.
+type sessionContext struct {
+	iface.OrdaContext
+	sc mongo.SessionContext
+}
+
+func (its *sessionContext) Value(key interface{}) interface{} {
+	return its.sc.Value(key)
+}
+
+// SupportTransaction returns true if MongoDB supports transactions, i.e., it is not standalone.
+func (its *MongoCollections) SupportTransaction() bool {
+	return its.supportTransaction
+}
+
+// doTransaction runs the transactions atomically. The queries in the transactions should use the given txCtx.
+// If MongoDB does not support transactions (i.e., standalone), the transactions run without atomicity.
 func (its *MongoCollections) doTransaction(
 	ctx iface.OrdaContext,
-	transactions func() errors.OrdaError,
+	transactions func(txCtx iface.OrdaContext) errors.OrdaError,
 ) errors.OrdaError {
+	if !its.supportTransaction {
+		return transactions(ctx)
+	}
 	session, err := its.mongoClient.StartSession()
 	if err != nil {
 		return errors.ServerDBQuery.New(ctx.L(), err.Error())
 	}
+	defer session.EndSession(ctx)
 
-	if err := session.StartTransaction(); err != nil {
-		return errors.ServerDBQuery.New(ctx.L(), err.Error())
-	}
-	if err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		if err := transactions(); err != nil {
-			return err
+	var oErr errors.OrdaError
+	if _, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if oErr = transactions(&sessionContext{OrdaContext: ctx, sc: sc}); oErr != nil {
+			return nil, oErr
 		}
-		if err := session.CommitTransaction(sc); err != nil {
-			return errors.ServerDBQuery.New(ctx.L(), err.Error())
-		}
-		return nil
+		return nil, nil
 	}); err != nil {
-		return err.(errors.OrdaError)
+		if oErr != nil {
+			return oErr
+		}
+		return errors.ServerDBQuery.New(ctx.L(), err.Error())
 	}
-	session.EndSession(ctx)
 	return nil
 }
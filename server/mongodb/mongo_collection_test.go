@@ -22,6 +22,10 @@ import (
 func TestMongo(t *testing.T) {
 	mongo, ctx, collectionNum := integration.InitTestDBCollection(t, testonly.TestDBName)
 
+	t.Run("Can pass the repository test suite", func(t *testing.T) {
+		testonly.TestRepository(t, ctx, mongo)
+	})
+
 	t.Run("Can make collections simultaneously", func(t *testing.T) {
 		madeCollections := make(map[int32]*schema.CollectionDoc)
 
@@ -74,7 +78,7 @@ func TestMongo(t *testing.T) {
 		op.ID = model.NewOperationIDWithCUID(types.NewUID())
 		modelOp := op.ToModelOperation()
 
-		var oplist []interface{}
+		var oplist []*schema.OperationDoc
 		opDoc := schema.NewOperationDoc(modelOp, "test_duid", 1, collectionNum)
 		log.Logger.Infof("%+v", opDoc.GetOperation())
 		log.Logger.Infof("%+v", modelOp)
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/repository"
 	"github.com/orda-io/orda/server/schema"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -45,7 +46,8 @@ func New(ctx iface.OrdaContext, conf *Config) (*RepositoryMongo, errors.OrdaErro
 		db:     db,
 		client: client,
 		MongoCollections: &MongoCollections{
-			mongoClient: client,
+			mongoClient:        client,
+			supportTransaction: checkTransactionSupport(ctx, db),
 		},
 	}
 	if err := repo.InitializeCollections(ctx); err != nil {
@@ -55,6 +57,23 @@ func New(ctx iface.OrdaContext, conf *Config) (*RepositoryMongo, errors.OrdaErro
 	return repo, nil
 }
 
+// checkTransactionSupport examines if MongoDB is a replica set or a sharded cluster, which supports transactions.
+func checkTransactionSupport(ctx iface.OrdaContext, db *mongo.Database) bool {
+	var result struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := db.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result); err != nil {
+		ctx.L().Warnf("fail to check transaction support: %v", err)
+		return false
+	}
+	if result.SetName == "" && result.Msg != "isdbgrid" {
+		ctx.L().Warnf("MongoDB is standalone; push-pulls are committed without transactions")
+		return false
+	}
+	return true
+}
+
 func getCustomTLSConfig(ctx iface.OrdaContext, caFile string) (*tls.Config, errors.OrdaError) {
 	tlsConfig := new(tls.Config)
 	certs, err := ioutil.ReadFile(caFile)
@@ -152,17 +171,5 @@ func (its *RepositoryMongo) Close(ctx iface.OrdaContext) errors.OrdaError {
 
 // MakeCollection makes a real collection.
 func MakeCollection(ctx iface.OrdaContext, mongo *RepositoryMongo, collectionName string) (int32, errors.OrdaError) {
-	collectionDoc, err := mongo.GetCollection(ctx, collectionName)
-	if err != nil {
-		return 0, err
-	}
-	if collectionDoc != nil {
-		return collectionDoc.Num, nil
-	}
-	collectionDoc, err = mongo.InsertCollection(ctx, collectionName)
-	if err != nil {
-		return 0, err
-	}
-	ctx.L().Infof("create a new collection:%+v", collectionDoc)
-	return collectionDoc.Num, nil
+	return repository.MakeCollection(ctx, mongo, collectionName)
 }
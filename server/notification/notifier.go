@@ -8,24 +8,45 @@ import (
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/server/schema"
+	"sync"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
 
-// Notifier is a struct that takes responsibility for notification
+// Handler is called with a notification for the topic subscribed
+type Handler func(topic string, notification *model.Notification)
+
+// Notifier is a struct that takes responsibility for notification. Several subscribers can subscribe to the same
+// topic; each of them is identified by its name, and unsubscribing one does not affect the others.
 type Notifier struct {
 	mqttClient mqtt.Client
+	mutex      sync.RWMutex
+	handlers   map[string]map[string]Handler // topic -> subscriber -> handler
 }
 
-// NewNotifier creates an instance of Notifier
+// NewNotifier creates an instance of Notifier.
+// If pubSubAddr is empty, notifications are delivered only to the subscribers in this process.
 func NewNotifier(ctx iface.OrdaContext, pubSubAddr string) (*Notifier, errors.OrdaError) {
+	notifier := &Notifier{
+		handlers: make(map[string]map[string]Handler),
+	}
+	if pubSubAddr == "" {
+		ctx.L().Infof("MQTT is NOT initialized")
+		return notifier, nil
+	}
 	serverName := fmt.Sprintf("Orda-Server-%s(%s)", constants.Version, constants.BuildInfo)
 	opts := mqtt.NewClientOptions().AddBroker(pubSubAddr).SetUsername(serverName)
 	client := mqtt.NewClient(opts)
 	if token := client.Connect(); token.Wait() && token.Error() != nil {
 		return nil, errors.ServerInit.New(ctx.L(), token.Error())
 	}
-	return &Notifier{mqttClient: client}, nil
+	notifier.mqttClient = client
+	return notifier, nil
+}
+
+// GetTopic returns the notification topic of the datatype in the collection
+func GetTopic(collectionName string, key string) string {
+	return fmt.Sprintf("%s/%s", collectionName, key)
 }
 
 // NotifyAfterPushPull enables server to send a notification to MQTT server
@@ -36,7 +57,7 @@ func (n *Notifier) NotifyAfterPushPull(
 	datatype *schema.DatatypeDoc,
 	sseq uint64,
 ) errors.OrdaError {
-	topic := fmt.Sprintf("%s/%s", collectionName, datatype.Key)
+	topic := GetTopic(collectionName, datatype.Key)
 	msg := model.Notification{
 		CUID: cuid,
 		DUID: datatype.DUID,
@@ -47,9 +68,84 @@ func (n *Notifier) NotifyAfterPushPull(
 		return errors.ServerNotify.New(ctx.L(), err.Error())
 	}
 	ctx.L().Infof("notify datatype topic '%s': %s", topic, bMsg)
+	if n.mqttClient == nil {
+		go n.dispatch(topic, &msg)
+		return nil
+	}
 	if token := n.mqttClient.Publish(topic, 0, false, bMsg); token.Wait() && token.Error() != nil {
 		return errors.ServerNotify.New(ctx.L(), token.Error())
 	}
 
 	return nil
 }
+
+// dispatch calls the handlers of all the subscribers to the topic.
+func (n *Notifier) dispatch(topic string, notification *model.Notification) {
+	n.mutex.RLock()
+	handlers := make([]Handler, 0, len(n.handlers[topic]))
+	for _, handler := range n.handlers[topic] {
+		handlers = append(handlers, handler)
+	}
+	n.mutex.RUnlock()
+	for _, handler := range handlers {
+		handler(topic, notification)
+	}
+}
+
+// SubscribeNotification makes the subscriber subscribe to the topic, and calls the handler whenever a notification
+// for the topic arrives. Subscribing again replaces the handler of the subscriber.
+func (n *Notifier) SubscribeNotification(
+	ctx iface.OrdaContext,
+	subscriber string,
+	topic string,
+	handler Handler,
+) errors.OrdaError {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	subscribers, ok := n.handlers[topic]
+	if !ok {
+		if n.mqttClient != nil {
+			token := n.mqttClient.Subscribe(topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+				notification := &model.Notification{}
+				if err := json.Unmarshal(msg.Payload(), notification); err != nil {
+					_ = errors.ServerNotify.New(ctx.L(), err.Error())
+					return
+				}
+				n.dispatch(msg.Topic(), notification)
+			})
+			if token.Wait() && token.Error() != nil {
+				return errors.ServerNotify.New(ctx.L(), token.Error())
+			}
+		}
+		subscribers = make(map[string]Handler)
+		n.handlers[topic] = subscribers
+	}
+	subscribers[subscriber] = handler
+	ctx.L().Infof("'%s' subscribes notification topic '%s'", subscriber, topic)
+	return nil
+}
+
+// UnsubscribeNotification makes the subscriber unsubscribe from the topics. A topic is unsubscribed from MQTT when
+// no subscriber remains.
+func (n *Notifier) UnsubscribeNotification(ctx iface.OrdaContext, subscriber string, topics ...string) errors.OrdaError {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	var emptyTopics []string
+	for _, topic := range topics {
+		if subscribers, ok := n.handlers[topic]; ok {
+			delete(subscribers, subscriber)
+			if len(subscribers) == 0 {
+				delete(n.handlers, topic)
+				emptyTopics = append(emptyTopics, topic)
+			}
+		}
+	}
+	ctx.L().Infof("'%s' unsubscribes notification topics %v", subscriber, topics)
+	if n.mqttClient == nil || len(emptyTopics) == 0 {
+		return nil
+	}
+	if token := n.mqttClient.Unsubscribe(emptyTopics...); token.Wait() && token.Error() != nil {
+		return errors.ServerNotify.New(ctx.L(), token.Error())
+	}
+	return nil
+}
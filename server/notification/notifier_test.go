@@ -0,0 +1,49 @@
+package notification_test
+
+import (
+	gocontext "context"
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/notification"
+	"github.com/orda-io/orda/server/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestInProcessNotification(t *testing.T) {
+	ctx := context.NewOrdaContext(gocontext.TODO(), constants.TagTest)
+	notifier, err := notification.NewNotifier(ctx, "")
+	require.NoError(t, err)
+	topic := notification.GetTopic(t.Name(), "key")
+	datatype := &schema.DatatypeDoc{DUID: "duid", UpdatedDatatypeDoc: schema.UpdatedDatatypeDoc{Key: "key"}}
+
+	received := make(chan string, 2)
+	subscribe := func(subscriber string) {
+		require.NoError(t, notifier.SubscribeNotification(ctx, subscriber, topic,
+			func(topic string, notification *model.Notification) {
+				received <- subscriber
+			}))
+	}
+	receive := func() string {
+		select {
+		case subscriber := <-received:
+			return subscriber
+		case <-time.After(time.Second):
+			return ""
+		}
+	}
+	subscribe("grpc")
+	subscribe("websocket")
+	require.NoError(t, notifier.NotifyAfterPushPull(ctx, t.Name(), "cuid", datatype, 1))
+	require.ElementsMatch(t, []string{"grpc", "websocket"}, []string{receive(), receive()})
+
+	// unsubscribing one of the subscribers does not affect the other
+	require.NoError(t, notifier.UnsubscribeNotification(ctx, "websocket", topic))
+	require.NoError(t, notifier.NotifyAfterPushPull(ctx, t.Name(), "cuid", datatype, 2))
+	require.Equal(t, "grpc", receive())
+	require.NoError(t, notifier.UnsubscribeNotification(ctx, "grpc", topic))
+	require.NoError(t, notifier.NotifyAfterPushPull(ctx, t.Name(), "cuid", datatype, 3))
+	require.Equal(t, "", receive())
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/server/schema"
+	"time"
+)
+
+// types of Repository
+const (
+	TypeMongoDB = "mongodb"
+	TypeMemory  = "memory"
+	TypeBolt    = "bolt"
+)
+
+// Ver is the field name that notes the version of a real snapshot.
+const Ver = "_orda_ver_"
+
+// Repository is the storage of Orda server, which stores clients, collections, datatypes, operations and snapshots.
+// Any getter returns nil without error if the requested document does not exist.
+type Repository interface {
+	// clients
+	GetClient(ctx iface.OrdaContext, cuid string) (*schema.ClientDoc, errors.OrdaError)
+	UpdateClient(ctx iface.OrdaContext, client *schema.ClientDoc) errors.OrdaError
+	DeleteClient(ctx iface.OrdaContext, cuid string) errors.OrdaError
+
+	// collections
+	GetCollection(ctx iface.OrdaContext, name string) (*schema.CollectionDoc, errors.OrdaError)
+	InsertCollection(ctx iface.OrdaContext, name string) (*schema.CollectionDoc, errors.OrdaError)
+	DeleteCollection(ctx iface.OrdaContext, name string) errors.OrdaError
+	PurgeCollection(ctx iface.OrdaContext, name string) errors.OrdaError
+
+	// datatypes
+	GetDatatype(ctx iface.OrdaContext, duid string) (*schema.DatatypeDoc, errors.OrdaError)
+	GetDatatypeByKey(ctx iface.OrdaContext, collectionNum int32, key string) (*schema.DatatypeDoc, errors.OrdaError)
+	UpdateDatatype(ctx iface.OrdaContext, datatype *schema.DatatypeDoc) errors.OrdaError
+	// BumpEra starts a new era of the datatype, and returns the new era. Version is incremented as well,
+	// so that a concurrent push-pull having checked the old era fails to commit.
+	BumpEra(ctx iface.OrdaContext, duid string) (uint32, errors.OrdaError)
+	PurgeDatatype(ctx iface.OrdaContext, collectionNum int32, key string) errors.OrdaError
+
+	// operations
+	InsertOperations(ctx iface.OrdaContext, operations []*schema.OperationDoc) errors.OrdaError
+	DeleteOperation(ctx iface.OrdaContext, duid string, sseq uint32) (int64, errors.OrdaError)
+	GetOperations(ctx iface.OrdaContext, duid string, from, to uint64) (model.OpList, []uint64, errors.OrdaError)
+	// GetLastSseqAt returns the sseq of the last operation created at or before the time, or 0 if there is none.
+	GetLastSseqAt(ctx iface.OrdaContext, duid string, at time.Time) (uint64, errors.OrdaError)
+	PurgeOperations(ctx iface.OrdaContext, collectionNum int32, duid string) errors.OrdaError
+
+	// CommitPushPull atomically inserts the operations and updates the datatype whose Sseq.End covers them.
+	// The datatype is updated only if its stored Version is still that of the datatype, and then its Version is
+	// incremented; otherwise, ServerDBConflict is returned.
+	CommitPushPull(ctx iface.OrdaContext, datatype *schema.DatatypeDoc, operations []*schema.OperationDoc) errors.OrdaError
+	// CompactOperations advances Sseq.Begin of the datatype to begin, and removes the operations before it.
+	// It returns the number of removed operations.
+	CompactOperations(ctx iface.OrdaContext, duid string, begin, safe uint64) (int64, errors.OrdaError)
+	// RepairOperations removes orphaned operations, i.e., ones beyond Sseq.End of their datatype or without datatype.
+	// It returns the number of removed operations. Since the operations of a commit are inserted before its datatype
+	// is updated, a repository shared by several servers repairs nothing unless the commit is transactional.
+	RepairOperations(ctx iface.OrdaContext) (int64, errors.OrdaError)
+
+	// snapshots
+	GetLatestSnapshot(ctx iface.OrdaContext, collectionNum int32, duid string) (*schema.SnapshotDoc, errors.OrdaError)
+	InsertSnapshot(ctx iface.OrdaContext, collectionNum int32, duid string, sseq uint64, meta []byte, snapshot []byte) errors.OrdaError
+	// GetSnapshots returns all the snapshots of the datatype in ascending order of sseq.
+	GetSnapshots(ctx iface.OrdaContext, collectionNum int32, duid string) ([]*schema.SnapshotDoc, errors.OrdaError)
+	// DeleteSnapshots removes the snapshots of the datatype at the specified sseqs, and returns the number of removed ones.
+	DeleteSnapshots(ctx iface.OrdaContext, collectionNum int32, duid string, sseqList []uint64) (int64, errors.OrdaError)
+
+	// real collections
+	GetOrCreateRealCollection(ctx iface.OrdaContext, name string) errors.OrdaError
+	InsertRealSnapshot(ctx iface.OrdaContext, collectionName string, id string, data interface{}, sseq uint64) errors.OrdaError
+	GetRealSnapshot(ctx iface.OrdaContext, collectionName string, id string) (map[string]interface{}, errors.OrdaError)
+
+	Close(ctx iface.OrdaContext) errors.OrdaError
+}
+
+// MakeCollection makes a collection if not exists, and returns the number of the collection.
+func MakeCollection(ctx iface.OrdaContext, repo Repository, collectionName string) (int32, errors.OrdaError) {
+	collectionDoc, err := repo.GetCollection(ctx, collectionName)
+	if err != nil {
+		return 0, err
+	}
+	if collectionDoc != nil {
+		return collectionDoc.Num, nil
+	}
+	collectionDoc, err = repo.InsertCollection(ctx, collectionName)
+	if err != nil {
+		return 0, err
+	}
+	ctx.L().Infof("create a new collection:%+v", collectionDoc)
+	return collectionDoc.Num, nil
+}
@@ -0,0 +1,15 @@
+package retention
+
+import "time"
+
+// Config is a configuration for retaining the snapshots of datatypes; the latest snapshot is always retained.
+type Config struct {
+	// KeepLast is the number of the latest snapshots to retain.
+	KeepLast int `json:"KeepLast"`
+	// KeepDays is the number of days for which the last snapshot of each day is retained.
+	KeepDays int `json:"KeepDays"`
+}
+
+func (its *Config) getDailyHorizon() time.Duration {
+	return time.Duration(its.KeepDays) * 24 * time.Hour
+}
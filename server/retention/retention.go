@@ -0,0 +1,62 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/schema"
+)
+
+// Prune removes the snapshots of the datatype which are retained by none of the rules of the Config.
+func Prune(
+	ctx iface.OrdaContext,
+	repo repository.Repository,
+	conf *Config,
+	collectionNum int32,
+	duid string,
+) errors.OrdaError {
+	snapshots, err := repo.GetSnapshots(ctx, collectionNum, duid)
+	if err != nil {
+		return err
+	}
+	expired := selectExpired(conf, snapshots, time.Now())
+	if len(expired) == 0 {
+		return nil
+	}
+	deleted, err := repo.DeleteSnapshots(ctx, collectionNum, duid, expired)
+	if err != nil {
+		return err
+	}
+	ctx.L().Infof("pruned %d snapshots of %s", deleted, duid)
+	return nil
+}
+
+// selectExpired returns the sseqs of the snapshots, given in ascending order of sseq, which are not retained.
+func selectExpired(conf *Config, snapshots []*schema.SnapshotDoc, now time.Time) []uint64 {
+	retained := make(map[uint64]bool)
+	for i := len(snapshots) - 1; i >= 0 && i >= len(snapshots)-conf.KeepLast; i-- {
+		retained[snapshots[i].Sseq] = true
+	}
+	if len(snapshots) > 0 {
+		retained[snapshots[len(snapshots)-1].Sseq] = true
+	}
+	lastOfDays := make(map[string]uint64)
+	for _, snapshot := range snapshots {
+		if now.Sub(snapshot.CreatedAt) < conf.getDailyHorizon() {
+			lastOfDays[snapshot.CreatedAt.UTC().Format("2006-01-02")] = snapshot.Sseq
+		}
+	}
+	for _, sseq := range lastOfDays {
+		retained[sseq] = true
+	}
+
+	var expired []uint64
+	for _, snapshot := range snapshots {
+		if !retained[snapshot.Sseq] {
+			expired = append(expired, snapshot.Sseq)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,51 @@
+package retention
+
+import (
+	gocontext "context"
+	"testing"
+	"time"
+
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/client/pkg/types"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/memdb"
+	"github.com/orda-io/orda/server/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetention(t *testing.T) {
+	t.Run("Can select expired snapshots", func(t *testing.T) {
+		now := time.Date(2022, 3, 10, 12, 0, 0, 0, time.UTC)
+		newSnapshot := func(sseq uint64, ago time.Duration) *schema.SnapshotDoc {
+			return &schema.SnapshotDoc{Sseq: sseq, CreatedAt: now.Add(-ago)}
+		}
+		snapshots := []*schema.SnapshotDoc{
+			newSnapshot(10, 100*time.Hour), // 3/6: out of days
+			newSnapshot(20, 50*time.Hour),  // 3/8: not the last of the day
+			newSnapshot(30, 49*time.Hour),  // 3/8: the last of the day
+			newSnapshot(40, 26*time.Hour),  // 3/9: not the last of the day
+			newSnapshot(50, 25*time.Hour),  // 3/9: the last of the day
+			newSnapshot(60, 3*time.Hour),
+			newSnapshot(70, 2*time.Hour),
+			newSnapshot(80, time.Hour),
+		}
+		require.Equal(t, []uint64{10, 20, 40, 60}, selectExpired(&Config{KeepLast: 2, KeepDays: 3}, snapshots, now))
+		require.Equal(t, []uint64{10, 20, 30, 40, 50, 60, 70}, selectExpired(&Config{}, snapshots, now))
+		require.Nil(t, selectExpired(&Config{KeepLast: 10}, snapshots, now))
+	})
+
+	t.Run("Can prune snapshots", func(t *testing.T) {
+		ctx := context.NewOrdaContext(gocontext.TODO(), constants.TagTest)
+		repo := memdb.New(ctx)
+		duid := types.NewUID()
+		for sseq := uint64(1); sseq <= 5; sseq++ {
+			require.NoError(t, repo.InsertSnapshot(ctx, 1, duid, sseq, []byte("{}"), []byte("{}")))
+		}
+		require.NoError(t, Prune(ctx, repo, &Config{KeepLast: 2}, 1, duid))
+		snapshots, err := repo.GetSnapshots(ctx, 1, duid)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		require.Equal(t, uint64(4), snapshots[0].Sseq)
+		require.Equal(t, uint64(5), snapshots[1].Sseq)
+	})
+}
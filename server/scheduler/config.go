@@ -0,0 +1,53 @@
+package scheduler
+
+import "time"
+
+// Default values of Config
+const (
+	DefaultEveryOperations uint64 = 100
+	DefaultEverySeconds    int64  = 10
+	DefaultIdleSeconds     int64  = 1
+	DefaultWorkers                = 4
+	DefaultMaxRetries             = 3
+)
+
+// Config is a configuration for scheduling jobs reserved for each key, e.g., updating snapshots of datatypes.
+// A job is run when any of the triggers, i.e., EveryOperations, EverySeconds and IdleSeconds, is satisfied.
+type Config struct {
+	// EveryOperations triggers a job when the number of reserved operations reaches it.
+	EveryOperations uint64 `json:"EveryOperations"`
+	// EverySeconds triggers a job when it has passed in seconds since the first reservation, even though busy.
+	EverySeconds int64 `json:"EverySeconds"`
+	// IdleSeconds triggers a job when no reservation is made in this duration in seconds.
+	IdleSeconds int64 `json:"IdleSeconds"`
+	// Workers is the maximum number of jobs run concurrently.
+	Workers int `json:"Workers"`
+	// MaxRetries is the maximum number of retries of a failed job.
+	MaxRetries int `json:"MaxRetries"`
+}
+
+// NewDefaultConfig returns a Config with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		EveryOperations: DefaultEveryOperations,
+		EverySeconds:    DefaultEverySeconds,
+		IdleSeconds:     DefaultIdleSeconds,
+		Workers:         DefaultWorkers,
+		MaxRetries:      DefaultMaxRetries,
+	}
+}
+
+func (its *Config) getWorkers() int {
+	if its.Workers <= 0 {
+		return DefaultWorkers
+	}
+	return its.Workers
+}
+
+func (its *Config) getEvery() time.Duration {
+	return time.Duration(its.EverySeconds) * time.Second
+}
+
+func (its *Config) getIdle() time.Duration {
+	return time.Duration(its.IdleSeconds) * time.Second
+}
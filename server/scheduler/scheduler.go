@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+)
+
+const (
+	tickInterval = 100 * time.Millisecond
+	retryBackoff = 500 * time.Millisecond
+)
+
+// Job is a function run by the Scheduler
+type Job func() errors.OrdaError
+
+type entry struct {
+	key        string
+	job        Job
+	operations uint64
+	firstAt    time.Time
+	lastAt     time.Time
+	retryAt    time.Time
+	retries    int
+	queued     bool
+	running    bool
+}
+
+// Scheduler runs the jobs reserved for each key with bounded workers. The reservations for the same key are
+// coalesced into a single run of the latest job, which is deferred until any of the triggers is satisfied.
+type Scheduler struct {
+	ctx     iface.OrdaContext
+	conf    *Config
+	mutex   *sync.Mutex
+	cond    *sync.Cond
+	entries map[string]*entry
+	ready   []*entry
+	closed  bool
+	doneCh  chan struct{}
+	wg      *sync.WaitGroup
+}
+
+// New creates a Scheduler and starts its workers; if conf is nil, the default Config is used.
+func New(ctx iface.OrdaContext, conf *Config) *Scheduler {
+	if conf == nil {
+		conf = NewDefaultConfig()
+	}
+	mutex := &sync.Mutex{}
+	its := &Scheduler{
+		ctx:     ctx,
+		conf:    conf,
+		mutex:   mutex,
+		cond:    sync.NewCond(mutex),
+		entries: make(map[string]*entry),
+		doneCh:  make(chan struct{}),
+		wg:      &sync.WaitGroup{},
+	}
+	for i := 0; i < conf.getWorkers(); i++ {
+		its.wg.Add(1)
+		go its.work()
+	}
+	its.wg.Add(1)
+	go its.tick()
+	return its
+}
+
+// Reserve reserves the job for the key with the number of operations which make it necessary.
+// If a job for the key is already reserved, it is replaced with the new one.
+func (its *Scheduler) Reserve(key string, operations uint64, job Job) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	if its.closed {
+		return
+	}
+	now := time.Now()
+	e, ok := its.entries[key]
+	if !ok {
+		e = &entry{key: key, firstAt: now}
+		its.entries[key] = e
+	}
+	e.job = job
+	e.operations += operations
+	e.lastAt = now
+	its.enqueueIfDue(e, now)
+}
+
+// Pending returns the number of keys whose jobs are not finished yet.
+func (its *Scheduler) Pending() int {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	return len(its.entries)
+}
+
+// Close stops the Scheduler after the running jobs are finished; the jobs not started are discarded.
+func (its *Scheduler) Close() {
+	its.mutex.Lock()
+	if its.closed {
+		its.mutex.Unlock()
+		return
+	}
+	its.closed = true
+	close(its.doneCh)
+	its.cond.Broadcast()
+	its.mutex.Unlock()
+	its.wg.Wait()
+}
+
+func (its *Scheduler) isDue(e *entry, now time.Time) bool {
+	if e.queued || e.running || e.operations == 0 || now.Before(e.retryAt) {
+		return false
+	}
+	if e.retries > 0 {
+		return true
+	}
+	conf := its.conf
+	if conf.EveryOperations == 0 && conf.EverySeconds == 0 && conf.IdleSeconds == 0 {
+		return true
+	}
+	if conf.EveryOperations > 0 && e.operations >= conf.EveryOperations {
+		return true
+	}
+	if conf.EverySeconds > 0 && now.Sub(e.firstAt) >= conf.getEvery() {
+		return true
+	}
+	if conf.IdleSeconds > 0 && now.Sub(e.lastAt) >= conf.getIdle() {
+		return true
+	}
+	return false
+}
+
+func (its *Scheduler) enqueueIfDue(e *entry, now time.Time) {
+	if !its.isDue(e, now) {
+		return
+	}
+	e.queued = true
+	its.ready = append(its.ready, e)
+	its.cond.Signal()
+}
+
+func (its *Scheduler) tick() {
+	defer its.wg.Done()
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-its.doneCh:
+			return
+		case now := <-ticker.C:
+			its.mutex.Lock()
+			for _, e := range its.entries {
+				its.enqueueIfDue(e, now)
+			}
+			its.mutex.Unlock()
+		}
+	}
+}
+
+func (its *Scheduler) work() {
+	defer its.wg.Done()
+	for {
+		e, job, operations := its.take()
+		if e == nil {
+			return
+		}
+		err := its.run(job)
+		its.finish(e, operations, err)
+	}
+}
+
+func (its *Scheduler) take() (*entry, Job, uint64) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	for len(its.ready) == 0 && !its.closed {
+		its.cond.Wait()
+	}
+	if its.closed {
+		return nil, nil, 0
+	}
+	e := its.ready[0]
+	its.ready = its.ready[1:]
+	e.queued = false
+	e.running = true
+	operations := e.operations
+	e.operations = 0
+	return e, e.job, operations
+}
+
+func (its *Scheduler) run(job Job) (err errors.OrdaError) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.ServerInternal.New(its.ctx.L(), r)
+		}
+	}()
+	return job()
+}
+
+func (its *Scheduler) finish(e *entry, operations uint64, err errors.OrdaError) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	now := time.Now()
+	e.running = false
+	if err != nil {
+		if e.retries < its.conf.MaxRetries {
+			e.retries++
+			e.operations += operations
+			e.retryAt = now.Add(time.Duration(e.retries) * retryBackoff)
+			its.ctx.L().Warnf("retry job of '%s' (%d/%d): %v", e.key, e.retries, its.conf.MaxRetries, err)
+			return
+		}
+		its.ctx.L().Errorf("give up job of '%s' after %d retries: %v", e.key, e.retries, err)
+	}
+	e.retries = 0
+	e.retryAt = time.Time{}
+	if e.operations == 0 {
+		delete(its.entries, e.key)
+		return
+	}
+	e.firstAt = now
+	its.enqueueIfDue(e, now)
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	gocontext "context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	ctx := context.NewOrdaContext(gocontext.TODO(), constants.TagTest)
+
+	t.Run("Can coalesce reservations of the same key", func(t *testing.T) {
+		s := New(ctx, &Config{EveryOperations: 1, Workers: 2})
+		defer s.Close()
+		var runs int32
+		blockCh := make(chan struct{})
+		job := func() errors.OrdaError {
+			atomic.AddInt32(&runs, 1)
+			<-blockCh
+			return nil
+		}
+		s.Reserve("a", 1, job)
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 1 }, time.Second, time.Millisecond)
+		for i := 0; i < 10; i++ {
+			s.Reserve("a", 1, job)
+		}
+		close(blockCh)
+		require.Eventually(t, func() bool { return s.Pending() == 0 }, time.Second, time.Millisecond)
+		require.Equal(t, int32(2), atomic.LoadInt32(&runs))
+	})
+
+	t.Run("Can trigger by operations and idle", func(t *testing.T) {
+		s := New(ctx, &Config{EveryOperations: 3, IdleSeconds: 1})
+		defer s.Close()
+		var runs int32
+		job := func() errors.OrdaError {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		}
+		s.Reserve("a", 2, job)
+		time.Sleep(300 * time.Millisecond)
+		require.Equal(t, int32(0), atomic.LoadInt32(&runs))
+		s.Reserve("a", 1, job)
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 1 }, time.Second, time.Millisecond)
+
+		s.Reserve("b", 1, job)
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 2 }, 2*time.Second, 10*time.Millisecond)
+		require.Equal(t, 0, s.Pending())
+	})
+
+	t.Run("Can retry failed jobs", func(t *testing.T) {
+		s := New(ctx, &Config{EveryOperations: 1, MaxRetries: 2})
+		defer s.Close()
+		var runs int32
+		s.Reserve("a", 1, func() errors.OrdaError {
+			if atomic.AddInt32(&runs, 1) < 3 {
+				return errors.ServerUpdateSnapshot.New(ctx.L(), "fail")
+			}
+			return nil
+		})
+		require.Eventually(t, func() bool { return s.Pending() == 0 }, 3*time.Second, 10*time.Millisecond)
+		require.Equal(t, int32(3), atomic.LoadInt32(&runs))
+	})
+}
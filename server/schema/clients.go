@@ -18,6 +18,7 @@ type ClientDoc struct {
 	CollectionNum int32     `bson:"colNum"`
 	Type          int8      `bson:"type"`
 	SyncType      int8      `bson:"syncType"`
+	Capabilities  uint32    `bson:"capabilities"`
 	CreatedAt     time.Time `bson:"createdAt"`
 	UpdatedAt     time.Time `bson:"updatedAt"`
 }
@@ -29,6 +30,7 @@ var ClientDocFields = struct {
 	CollectionNum string
 	Type          string
 	SyncType      string
+	Capabilities  string
 	CreatedAt     string
 	UpdatedAt     string
 }{
@@ -37,6 +39,7 @@ var ClientDocFields = struct {
 	CollectionNum: "colNum",
 	Type:          "type",
 	SyncType:      "syncType",
+	Capabilities:  "capabilities",
 	CreatedAt:     "createdAt",
 	UpdatedAt:     "updatedAt",
 }
@@ -53,6 +56,7 @@ func (its *ClientDoc) ToUpdateBSON() bson.D {
 			{ClientDocFields.CollectionNum, its.CollectionNum},
 			{ClientDocFields.Type, its.Type},
 			{ClientDocFields.SyncType, its.SyncType},
+			{ClientDocFields.Capabilities, its.Capabilities},
 			{ClientDocFields.CreatedAt, its.CreatedAt},
 		}},
 		{"$currentDate", bson.D{
@@ -104,6 +108,11 @@ func (its *ClientDoc) ToString() string {
 	return fmt.Sprintf("%s(%s)", its.Alias, its.CUID)
 }
 
+// HasCapability examines if the capability is negotiated with the client.
+func (its *ClientDoc) HasCapability(c model.Capability) bool {
+	return model.Capability(its.Capabilities).Has(c)
+}
+
 // GetType returns model.ClientType
 func (its *ClientDoc) GetType() model.ClientType {
 	return model.ClientType(its.Type)
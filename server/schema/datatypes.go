@@ -7,6 +7,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 )
 
@@ -36,6 +37,8 @@ type UpdatedDatatypeDoc struct {
 	CollectionNum int32   `json:"colNum" bson:"colNum"`
 	Type          string  `json:"type" bson:"type"`
 	Sseq          SseqSet `json:"sseq" bson:"sseq"`
+	Era           uint32  `json:"era" bson:"era"`
+	Version       uint64  `json:"ver" bson:"ver"`
 	// SseqBegin uint64                          `json:"sseqBegin" bson:"sseqBegin"`
 	// SseqEnd   uint64                          `json:"sseqEnd" bson:"sseqEnd"`
 	// SseqSafe  uint64                          `json:"sseqSafe" bson:"sseqSafe"`
@@ -44,6 +47,24 @@ type UpdatedDatatypeDoc struct {
 	UpdatedAt time.Time                       `json:"updatedAt" bson:"updatedAt"`
 	RWClients map[string]*SubscribedClientDoc `json:"rwClients" bson:"rwClients"`
 	ROClients map[string]*SubscribedClientDoc `json:"roClients" bson:"roClients"`
+	Stability *StabilityDoc                   `json:"stability,omitempty" bson:"stability"`
+}
+
+// StabilityDoc is the causal stability of a datatype. Every operation after Sseq, including those to be pushed
+// later, has a Lamport not less than Lamport, and has seen the operations having a less Lamport.
+// No operation to come is in an era older than Era.
+type StabilityDoc struct {
+	Era     uint32 `json:"era" bson:"era"`
+	Lamport uint64 `json:"lamport" bson:"lamport"`
+	Sseq    uint64 `json:"sseq" bson:"sseq"`
+}
+
+// GetStable returns the stable timestamp of the causal stability.
+func (its *StabilityDoc) GetStable() *model.Timestamp {
+	if its == nil {
+		return nil
+	}
+	return &model.Timestamp{Era: its.Era, Lamport: its.Lamport}
 }
 
 // DatatypeDocFields defines the fields of DatatypeDoc
@@ -55,20 +76,30 @@ var DatatypeDocFields = struct {
 	SseqBegin     string
 	SseqEnd       string
 	SseqSafe      string
+	Era           string
+	Version       string
 	Visible       string
 	CreatedAt     string
 	UpdatedAt     string
+	RWClients     string
+	ROClients     string
+	Stability     string
 }{
 	DUID:          "_id",
 	Key:           "key",
 	CollectionNum: "colNum",
 	Type:          "type",
-	SseqBegin:     "sseqBegin",
-	SseqEnd:       "sseqEnd",
-	SseqSafe:      "sseqSafe",
+	SseqBegin:     "sseq.begin",
+	SseqEnd:       "sseq.end",
+	SseqSafe:      "sseq.safe",
+	Era:           "era",
+	Version:       "ver",
 	Visible:       "visible",
 	CreatedAt:     "createdAt",
 	UpdatedAt:     "updatedAt",
+	RWClients:     "rwClients",
+	ROClients:     "roClients",
+	Stability:     "stability",
 }
 
 // NewDatatypeDoc returns a new DatatypeDoc
@@ -104,6 +135,7 @@ func (its *DatatypeDoc) GetIndexModel() []mongo.IndexModel {
 			{DatatypeDocFields.CollectionNum, bsonx.Int32(1)},
 			{DatatypeDocFields.Key, bsonx.Int32(1)},
 		},
+		Options: options.Index().SetUnique(true),
 	}}
 }
 
@@ -117,6 +149,26 @@ func (its *DatatypeDoc) ToUpdateBSON() bson.D {
 	return d
 }
 
+// ToCommitBSON transforms DatatypeDoc to BSON type for committing a push-pull, which increments Version.
+// Sseq.Begin, Sseq.Safe and Era are not included because they are changed only by compaction, which advances
+// Sseq.Begin and Sseq.Safe, and by BumpEra, which also increments Version so that a stale commit fails.
+func (its *DatatypeDoc) ToCommitBSON() bson.D {
+	its.UpdatedAt = time.Now()
+	return bson.D{{Key: "$set", Value: bson.D{
+		{Key: DatatypeDocFields.Version, Value: its.Version + 1},
+		{Key: DatatypeDocFields.Key, Value: its.Key},
+		{Key: DatatypeDocFields.CollectionNum, Value: its.CollectionNum},
+		{Key: DatatypeDocFields.Type, Value: its.Type},
+		{Key: DatatypeDocFields.SseqEnd, Value: its.Sseq.End},
+		{Key: DatatypeDocFields.Visible, Value: its.Visible},
+		{Key: DatatypeDocFields.CreatedAt, Value: its.CreatedAt},
+		{Key: DatatypeDocFields.UpdatedAt, Value: its.UpdatedAt},
+		{Key: DatatypeDocFields.RWClients, Value: its.RWClients},
+		{Key: DatatypeDocFields.ROClients, Value: its.ROClients},
+		{Key: DatatypeDocFields.Stability, Value: its.Stability},
+	}}}
+}
+
 // GetType returns the type of datatype.
 func (its *DatatypeDoc) GetType() model.TypeOfDatatype {
 	return model.TypeOfDatatype(model.TypeOfDatatype_value[its.Type])
@@ -162,11 +214,16 @@ func (its *DatatypeDoc) AddNewClient(cuid string, typ int8, ro bool) *Subscribed
 	return clientDoc
 }
 
-// SubscribedClientDoc contains the information of a Client
+// SubscribedClientDoc contains the information of a Client. Seen, Lamport and Era bound the operations which the
+// client has not pushed yet; they have seen the operations until Seen, and their Lamports and eras are not less
+// than Lamport and Era. They are zero until the client reports them.
 type SubscribedClientDoc struct {
-	CP   *model.CheckPoint `bson:"cp"`
-	Type int8              `bson:"t"`
-	At   time.Time         `bson:"at"`
+	CP      *model.CheckPoint `bson:"cp"`
+	Type    int8              `bson:"t"`
+	At      time.Time         `bson:"at"`
+	Seen    uint64            `bson:"seen"`
+	Lamport uint64            `bson:"lamport"`
+	Era     uint32            `bson:"era"`
 }
 
 // GetCheckPoint returns *model.CheckPoint from SubscribedClientDoc
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 )
 
@@ -71,6 +72,7 @@ var OperationDocFields = struct {
 	DUID          string
 	CollectionNum string
 	OpType        string
+	OpCUID        string
 	Sseq          string
 	Operation     string
 	CreatedAt     string
@@ -79,6 +81,7 @@ var OperationDocFields = struct {
 	DUID:          "duid",
 	CollectionNum: "colNum",
 	OpType:        "type",
+	OpCUID:        "id.cuid",
 	Sseq:          "sseq",
 	Operation:     "op",
 	CreatedAt:     "createdAt",
@@ -91,5 +94,6 @@ func (its *OperationDoc) GetIndexModel() []mongo.IndexModel {
 			{OperationDocFields.DUID, bsonx.Int32(1)},
 			{OperationDocFields.Sseq, bsonx.Int32(-1)},
 		},
+		Options: options.Index().SetUnique(true),
 	}}
 }
@@ -35,11 +35,26 @@ func (b Filter) AddFilterGTE(key string, from interface{}) Filter {
 	return append(b, bson.E{Key: key, Value: bson.D{{Key: "$gte", Value: from}}})
 }
 
+// AddFilterGT is a function to add GT to Filter
+func (b Filter) AddFilterGT(key string, from interface{}) Filter {
+	return append(b, bson.E{Key: key, Value: bson.D{{Key: "$gt", Value: from}}})
+}
+
+// AddFilterLT is a function to add LT to Filter
+func (b Filter) AddFilterLT(key string, to interface{}) Filter {
+	return append(b, bson.E{Key: key, Value: bson.D{{Key: "$lt", Value: to}}})
+}
+
 // AddFilterLTE is a function to add LTE to Filter
 func (b Filter) AddFilterLTE(key string, to interface{}) Filter {
 	return append(b, bson.E{Key: key, Value: bson.D{{Key: "$lte", Value: to}}})
 }
 
+// AddFilterIn is a function to add IN to Filter
+func (b Filter) AddFilterIn(key string, values interface{}) Filter {
+	return append(b, bson.E{Key: key, Value: bson.D{{Key: "$in", Value: values}}})
+}
+
 // ToCheckPointBSON is a function to change a checkpoint to BSON
 func ToCheckPointBSON(checkPoint *model.CheckPoint) bson.M {
 	return bson.M{"s": checkPoint.Sseq, "c": checkPoint.Cseq}
@@ -6,6 +6,7 @@ import (
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/server/managers"
+	"github.com/orda-io/orda/server/service"
 	"google.golang.org/grpc/credentials/insecure"
 	"io/ioutil"
 	"net/http"
@@ -16,7 +17,7 @@ import (
 	"github.com/swaggo/swag"
 	"google.golang.org/grpc"
 
-	"github.com/orda-io/orda/server/mongodb"
+	"github.com/orda-io/orda/server/repository"
 )
 
 const (
@@ -30,15 +31,22 @@ type RestServer struct {
 	ctx      iface.OrdaContext
 	conf     *managers.OrdaServerConfig
 	managers *managers.Managers
+	service  *service.OrdaService
 }
 
 // NewRestServer creates a control server.
-func NewRestServer(ctx iface.OrdaContext, conf *managers.OrdaServerConfig, clients *managers.Managers) *RestServer {
+func NewRestServer(
+	ctx iface.OrdaContext,
+	conf *managers.OrdaServerConfig,
+	clients *managers.Managers,
+	service *service.OrdaService,
+) *RestServer {
 
 	return &RestServer{
 		ctx:      ctx,
 		conf:     conf,
 		managers: clients,
+		service:  service,
 	}
 }
 
@@ -53,6 +61,8 @@ func (its *RestServer) Start() errors.OrdaError {
 		return err
 	}
 
+	its.initWebSocketServer(mux)
+
 	if err := http.ListenAndServe(its.conf.GetRestfulAddr(), its.allowCors(mux)); err != nil {
 		return errors.ServerInit.New(its.ctx.L(), err)
 	}
@@ -121,6 +131,11 @@ func (its *RestServer) initGrpcGatewayServer(mux *http.ServeMux) errors.OrdaErro
 	return nil
 }
 
+func (its *RestServer) initWebSocketServer(mux *http.ServeMux) {
+	mux.Handle(apiWebSocket, newWebSocketGateway(its.ctx, its.conf, its.service, its.managers.Notifier))
+	its.ctx.L().Infof("open port: ws://localhost%s%s", its.conf.GetRestfulAddr(), apiWebSocket)
+}
+
 func (its *RestServer) echo(w http.ResponseWriter, r *http.Request) {
 	its.ctx.L().Infof("ignored request: %v  %v%v", r.Method, r.Host, r.URL)
 }
@@ -129,7 +144,7 @@ func (its *RestServer) createCollections(res http.ResponseWriter, req *http.Requ
 	switch req.Method {
 	case http.MethodPut:
 		collectionName := strings.TrimPrefix(req.URL.Path, apiCollections)
-		num, err := mongodb.MakeCollection(its.ctx, its.managers.Mongo, collectionName)
+		num, err := repository.MakeCollection(its.ctx, its.managers.Repository, collectionName)
 		var msg string
 		if err != nil {
 			msg = fmt.Sprintf("Fail to create collection '%s'", collectionName)
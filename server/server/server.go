@@ -70,12 +70,19 @@ func (its *OrdaServer) Start() errors.OrdaError {
 	if its.managers, oErr = managers.New(its.ctx, its.conf); oErr != nil {
 		return oErr
 	}
+	repaired, oErr := its.managers.Repository.RepairOperations(its.ctx)
+	if oErr != nil {
+		return oErr
+	}
+	if repaired > 0 {
+		its.ctx.L().Warnf("repaired %d orphaned operations", repaired)
+	}
 
 	lis, err := net.Listen("tcp", its.conf.GetRPCServerAddr())
 	if err != nil {
 		return errors.ServerInit.New(its.ctx.L(), "fail to listen RPC:"+err.Error())
 	}
-	its.rpcServer = grpc.NewServer()
+	its.rpcServer = grpc.NewServer(grpc.MaxRecvMsgSize(its.conf.GetMaxMessageSize()))
 	reflection.Register(its.rpcServer)
 	its.service = service.NewOrdaService(its.managers)
 	model.RegisterOrdaServiceServer(its.rpcServer, its.service)
@@ -88,7 +95,7 @@ func (its *OrdaServer) Start() errors.OrdaError {
 		}
 	}()
 
-	its.restServer = NewRestServer(its.ctx, its.conf, its.managers)
+	its.restServer = NewRestServer(its.ctx, its.conf, its.managers, its.service)
 	go func() {
 		if err := its.restServer.Start(); err != nil {
 			_ = errors.ServerInit.New(its.ctx.L(), err.Error())
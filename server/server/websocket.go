@@ -0,0 +1,343 @@
+package server
+
+import (
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/managers"
+	"github.com/orda-io/orda/server/notification"
+	"github.com/orda-io/orda/server/service"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	apiWebSocket = "/ws/"
+	// wsPathFormat is the path of WebSocket: /ws/v1/collections/{collection}/clients/{cuid}
+	wsPathFormat = apiWebSocket + "v1/collections/%s/clients/%s"
+
+	wsWriteTimeout   = 10 * time.Second
+	wsPongTimeout    = 60 * time.Second
+	wsPingPeriod     = (wsPongTimeout * 9) / 10
+	wsSendBufferSize = 64
+	// wsSubscriber is the name of the WebSocket gateway subscribing to notifications
+	wsSubscriber = "websocket"
+)
+
+var (
+	wsMarshaler   = protojson.MarshalOptions{EmitUnpopulated: true}
+	wsUnmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// wsGateway serves the push-pull protocol and the realtime notifications over WebSocket, mainly for browser clients.
+// A client sends the protojson form of PushPullMessage, and receives the protojson form of PushPullMessage as
+// the response. Besides, the protojson form of Notification is delivered for the datatypes that the client has
+// pushed or pulled through the session. PushPullMessages are distinguished from Notifications by the 'header'.
+// A bad or unpermitted request is answered with the protojson form of google.rpc.Status without closing the session.
+type wsGateway struct {
+	ctx      iface.OrdaContext
+	conf     *managers.OrdaServerConfig
+	upgrader websocket.Upgrader
+	service  *service.OrdaService
+	notifier *notification.Notifier
+	mutex    sync.Mutex
+	topics   map[string]map[*wsSession]bool
+}
+
+func newWebSocketGateway(
+	ctx iface.OrdaContext,
+	conf *managers.OrdaServerConfig,
+	service *service.OrdaService,
+	notifier *notification.Notifier,
+) *wsGateway {
+	gateway := &wsGateway{
+		ctx:      ctx,
+		conf:     conf,
+		service:  service,
+		notifier: notifier,
+		topics:   make(map[string]map[*wsSession]bool),
+	}
+	gateway.upgrader = websocket.Upgrader{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+		CheckOrigin: func(r *http.Request) bool {
+			return conf.IsAllowedWebSocketOrigin(r.Header.Get("Origin"), r.Host)
+		},
+	}
+	return gateway
+}
+
+func parseWebSocketPath(path string) (collection string, cuid string, ok bool) {
+	if _, err := fmt.Sscanf(strings.ReplaceAll(path, "/", " "),
+		strings.ReplaceAll(wsPathFormat, "/", " "), &collection, &cuid); err != nil {
+		return "", "", false
+	}
+	return collection, cuid, true
+}
+
+// ServeHTTP authenticates a client with the same rules of push-pull, and upgrades the connection to WebSocket.
+func (its *wsGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.NewOrdaContext(r.Context(), constants.TagWebSocket)
+	collection, cuid, ok := parseWebSocketPath(r.URL.Path)
+	if !ok {
+		msg := fmt.Sprintf("invalid path '%s'", r.URL.Path)
+		http.Error(w, errors.ServerBadRequest.New(ctx.L(), msg).Error(), http.StatusBadRequest)
+		return
+	}
+	ctx.UpdateCollectionTags(collection, 0).UpdateClientTags("", cuid)
+	if _, _, err := its.service.ValidateClient(ctx, collection, cuid); err != nil {
+		http.Error(w, err.Error(), runtime.HTTPStatusFromCode(status.Code(err)))
+		return
+	}
+	conn, err := its.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		_ = errors.ServerBadRequest.New(ctx.L(), err.Error())
+		return
+	}
+	session := &wsSession{
+		ctx:        ctx,
+		gateway:    its,
+		conn:       conn,
+		collection: collection,
+		cuid:       cuid,
+		sendCh:     make(chan []byte, wsSendBufferSize),
+		doneCh:     make(chan struct{}),
+		topics:     make(map[string]bool),
+	}
+	ctx.L().Infof("open WebSocket session from %s", r.RemoteAddr)
+	go session.writeLoop()
+	session.readLoop()
+}
+
+func (its *wsGateway) subscribe(session *wsSession, topic string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	sessions, ok := its.topics[topic]
+	if !ok {
+		if err := its.notifier.SubscribeNotification(its.ctx, wsSubscriber, topic, its.deliver); err != nil {
+			return err
+		}
+		sessions = make(map[*wsSession]bool)
+		its.topics[topic] = sessions
+	}
+	sessions[session] = true
+	return nil
+}
+
+func (its *wsGateway) unsubscribe(session *wsSession, topics map[string]bool) {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	var emptyTopics []string
+	for topic := range topics {
+		if sessions, ok := its.topics[topic]; ok {
+			delete(sessions, session)
+			if len(sessions) == 0 {
+				delete(its.topics, topic)
+				emptyTopics = append(emptyTopics, topic)
+			}
+		}
+	}
+	_ = its.notifier.UnsubscribeNotification(its.ctx, wsSubscriber, emptyTopics...)
+}
+
+func (its *wsGateway) deliver(topic string, notification *model.Notification) {
+	its.mutex.Lock()
+	var receivers []*wsSession
+	for session := range its.topics[topic] {
+		if session.cuid != notification.CUID { // drain own notification
+			receivers = append(receivers, session)
+		}
+	}
+	its.mutex.Unlock()
+	if len(receivers) == 0 {
+		return
+	}
+	msg, err := wsMarshaler.Marshal(notification)
+	if err != nil {
+		_ = errors.ServerNotify.New(its.ctx.L(), err.Error())
+		return
+	}
+	for _, session := range receivers {
+		session.send(msg)
+	}
+}
+
+// wsSession is a WebSocket connection of a client
+type wsSession struct {
+	ctx        iface.OrdaContext
+	gateway    *wsGateway
+	conn       *websocket.Conn
+	collection string
+	cuid       string
+	sendCh     chan []byte
+	doneCh     chan struct{}
+	closeOnce  sync.Once
+	mutex      sync.Mutex // guards topics against close() from writeLoop
+	topics     map[string]bool
+}
+
+func (its *wsSession) send(msg []byte) {
+	select {
+	case its.sendCh <- msg:
+	case <-its.doneCh:
+	default:
+		its.ctx.L().Warnf("drop a message for the slow WebSocket session")
+	}
+}
+
+func (its *wsSession) close() {
+	its.closeOnce.Do(func() {
+		its.mutex.Lock()
+		close(its.doneCh)
+		its.gateway.unsubscribe(its, its.topics)
+		its.mutex.Unlock()
+		_ = its.conn.Close()
+		its.ctx.L().Infof("close WebSocket session")
+	})
+}
+
+func (its *wsSession) closeWithError(err error) {
+	its.closeWithCode(websocket.ClosePolicyViolation, err)
+}
+
+func (its *wsSession) closeWithCode(code int, err error) {
+	msg := websocket.FormatCloseMessage(code, err.Error())
+	_ = its.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+	its.close()
+}
+
+func (its *wsSession) readLoop() {
+	defer its.close()
+	its.conn.SetReadLimit(int64(its.gateway.conf.GetMaxMessageSize()))
+	_ = its.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	its.conn.SetPongHandler(func(string) error {
+		return its.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+	for {
+		_, data, err := its.conn.ReadMessage()
+		if err == websocket.ErrReadLimit {
+			its.closeWithCode(websocket.CloseMessageTooBig, err)
+			return
+		}
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				its.ctx.L().Warnf("unexpectedly closed WebSocket: %v", err)
+			}
+			return
+		}
+		_ = its.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		if err := its.processPushPull(data); err != nil {
+			if !its.replyError(err) {
+				its.closeWithError(err)
+				return
+			}
+		}
+	}
+}
+
+func (its *wsSession) processPushPull(data []byte) error {
+	req := &model.PushPullMessage{}
+	if err := wsUnmarshaler.Unmarshal(data, req); err != nil {
+		return errors.ServerBadRequest.New(its.ctx.L(), err.Error())
+	}
+	if req.Collection != its.collection || req.Cuid != its.cuid {
+		msg := fmt.Sprintf("not matched with the session: %s:%s", req.Collection, req.Cuid)
+		return errors.ServerNoPermission.New(its.ctx.L(), msg)
+	}
+	res, err := its.gateway.service.ProcessPushPull(its.ctx, req)
+	if err != nil {
+		return err
+	}
+	for _, ppp := range res.PushPullPacks {
+		if ppp.GetPushPullPackOption().HasErrorBit() {
+			continue
+		}
+		if err := its.subscribe(notification.GetTopic(its.collection, ppp.Key)); err != nil {
+			return err
+		}
+	}
+	msg, err := wsMarshaler.Marshal(res)
+	if err != nil {
+		return errors.ServerInternal.New(its.ctx.L(), err.Error())
+	}
+	its.send(msg)
+	return nil
+}
+
+// replyError sends the error of a bad or unpermitted request as google.rpc.Status, and returns false for the other
+// errors, with which the session should be closed.
+func (its *wsSession) replyError(err error) bool {
+	var st *status.Status
+	if oErr, ok := err.(errors.OrdaError); ok {
+		st = status.Convert(errors.NewRPCError(oErr))
+	} else {
+		st = status.Convert(err)
+	}
+	switch st.Code() {
+	case codes.InvalidArgument, codes.Unauthenticated, codes.PermissionDenied:
+	default:
+		return false
+	}
+	msg, mErr := wsMarshaler.Marshal(st.Proto())
+	if mErr != nil {
+		return false
+	}
+	its.send(msg)
+	return true
+}
+
+// subscribe subscribes the session to the topic unless it has been closed, in which case the subscription
+// would never be removed.
+func (its *wsSession) subscribe(topic string) errors.OrdaError {
+	its.mutex.Lock()
+	defer its.mutex.Unlock()
+	select {
+	case <-its.doneCh:
+		return nil
+	default:
+	}
+	if its.topics[topic] {
+		return nil
+	}
+	if err := its.gateway.subscribe(its, topic); err != nil {
+		return err
+	}
+	its.topics[topic] = true
+	return nil
+}
+
+func (its *wsSession) writeLoop() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		its.close()
+	}()
+	for {
+		select {
+		case msg := <-its.sendCh:
+			_ = its.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := its.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				its.ctx.L().Warnf("fail to write to WebSocket: %v", err)
+				return
+			}
+		case <-ticker.C:
+			_ = its.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := its.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-its.doneCh:
+			return
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/orda-io/orda/client/pkg/context"
 	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/server/admin"
 	"time"
@@ -28,6 +29,10 @@ func (its *OrdaService) ProcessClient(
 				fmt.Sprintf("not allowed CUID '%s'", req.GetCuid())))
 	}
 
+	if err := negotiateProtocol(ctx, req); err != nil {
+		return nil, errors.NewRPCError(err)
+	}
+
 	collectionDoc, rpcErr := its.getCollectionDocWithRPCError(ctx, req.Collection)
 	if rpcErr != nil {
 		return nil, rpcErr
@@ -35,17 +40,18 @@ func (its *OrdaService) ProcessClient(
 	ctx.UpdateCollectionTags(collectionDoc.Name, collectionDoc.Num)
 
 	clientDocFromReq := schema.ClientModelToBson(req.GetClient(), collectionDoc.Num)
+	clientDocFromReq.Capabilities = req.Capabilities
 
 	ctx.L().Infof("REQ[CLIE] %s %v %v", req.ToString(), len(req.Cuid), req.Cuid)
 
-	clientDocFromDB, err := its.managers.Mongo.GetClient(ctx, clientDocFromReq.CUID)
+	clientDocFromDB, err := its.managers.Repository.GetClient(ctx, clientDocFromReq.CUID)
 	if err != nil {
 		return nil, errors.NewRPCError(err)
 	}
 	if clientDocFromDB == nil {
 		clientDocFromReq.CreatedAt = time.Now()
 		ctx.L().Infof("create a new client:%+v", clientDocFromReq)
-		if err := its.managers.Mongo.GetOrCreateRealCollection(ctx, req.Collection); err != nil {
+		if err := its.managers.Repository.GetOrCreateRealCollection(ctx, req.Collection); err != nil {
 			return nil, errors.NewRPCError(err)
 		}
 	} else {
@@ -58,9 +64,30 @@ func (its *OrdaService) ProcessClient(
 		ctx.L().Infof("Client will be updated:%+v", clientDocFromReq)
 	}
 	clientDocFromReq.CreatedAt = time.Now()
-	if err = its.managers.Mongo.UpdateClient(ctx, clientDocFromReq); err != nil {
+	if err = its.managers.Repository.UpdateClient(ctx, clientDocFromReq); err != nil {
 		return nil, errors.NewRPCError(err)
 	}
 	ctx.L().Infof("RES[CLIE] %s", req.ToString())
 	return req, nil
 }
+
+// negotiateProtocol rejects the client speaking an unsupported version of the protocol, and sets the capabilities
+// which both the client and the server support in the request, which is returned as the response.
+func negotiateProtocol(ctx iface.OrdaContext, req *model.ClientMessage) errors.OrdaError {
+	version, agent := req.GetHeader().GetVersion(), req.GetHeader().GetAgent()
+	supported := false
+	for _, v := range constants.SupportedProtocolVersions {
+		if v == version {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		msg := fmt.Sprintf("protocol version '%s' of '%s' is not supported; upgrade to one of %v",
+			version, agent, constants.SupportedProtocolVersions)
+		return errors.ServerIncompatibleClient.New(ctx.L(), msg)
+	}
+	req.Capabilities = uint32(constants.ServerCapabilities & model.Capability(req.Capabilities))
+	ctx.L().Infof("negotiate protocol %s with '%s': capabilities %v", version, agent, model.Capability(req.Capabilities))
+	return nil
+}
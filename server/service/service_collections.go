@@ -8,14 +8,14 @@ import (
 	"github.com/orda-io/orda/client/pkg/model"
 
 	"github.com/orda-io/orda/server/constants"
-	"github.com/orda-io/orda/server/mongodb"
+	"github.com/orda-io/orda/server/repository"
 )
 
 // CreateCollection creates a collection
 func (its *OrdaService) CreateCollection(goCtx goctx.Context, in *model.CollectionMessage) (*model.CollectionMessage, error) {
 	ctx := context.NewOrdaContext(goCtx, constants.TagCreate).
 		UpdateCollectionTags(in.Collection, 0)
-	num, err := mongodb.MakeCollection(ctx, its.managers.Mongo, in.Collection)
+	num, err := repository.MakeCollection(ctx, its.managers.Repository, in.Collection)
 	var msg string
 	if err != nil {
 		return nil, errors.NewRPCError(err)
@@ -31,7 +31,7 @@ func (its *OrdaService) CreateCollection(goCtx goctx.Context, in *model.Collecti
 func (its *OrdaService) ResetCollection(goCtx goctx.Context, in *model.CollectionMessage) (*model.CollectionMessage, error) {
 	ctx := context.NewOrdaContext(goCtx, constants.TagReset).
 		UpdateCollectionTags(in.Collection, 0)
-	if err := its.managers.Mongo.PurgeCollection(ctx, in.Collection); err != nil {
+	if err := its.managers.Repository.PurgeCollection(ctx, in.Collection); err != nil {
 		return nil, errors.NewRPCError(err)
 	}
 	ctx.L().Infof("reset %s collection", in.Collection)
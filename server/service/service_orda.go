@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/server/managers"
@@ -23,7 +24,7 @@ func (its *OrdaService) getCollectionDocWithRPCError(
 	ctx iface.OrdaContext,
 	collection string,
 ) (*schema.CollectionDoc, error) {
-	collectionDoc, err := its.managers.Mongo.GetCollection(ctx, collection)
+	collectionDoc, err := its.managers.Repository.GetCollection(ctx, collection)
 	if err != nil {
 		return nil, errors.NewRPCError(err)
 	}
@@ -32,3 +33,31 @@ func (its *OrdaService) getCollectionDocWithRPCError(
 	}
 	return collectionDoc, nil
 }
+
+// ValidateClient examines if the client of the specified CUID exists and is allowed to access the collection.
+// This is shared by every transport of push-pull, i.e., gRPC, gRPC gateway and WebSocket.
+func (its *OrdaService) ValidateClient(
+	ctx iface.OrdaContext,
+	collection string,
+	cuid string,
+) (*schema.CollectionDoc, *schema.ClientDoc, error) {
+	collectionDoc, rpcErr := its.getCollectionDocWithRPCError(ctx, collection)
+	if rpcErr != nil {
+		return nil, nil, rpcErr
+	}
+	ctx.UpdateCollectionTags(collectionDoc.Name, collectionDoc.Num)
+	clientDoc, err := its.managers.Repository.GetClient(ctx, cuid)
+	if err != nil {
+		return nil, nil, errors.NewRPCError(err)
+	}
+	if clientDoc == nil {
+		msg := fmt.Sprintf("no client '%s:%s'", collection, cuid)
+		return nil, nil, errors.NewRPCError(errors.ServerNoResource.New(ctx.L(), msg))
+	}
+	ctx.UpdateClientTags(clientDoc.Alias, clientDoc.CUID)
+	if clientDoc.CollectionNum != collectionDoc.Num {
+		msg := fmt.Sprintf("client '%s' accesses collection(%d)", clientDoc.ToString(), collectionDoc.Num)
+		return nil, nil, errors.NewRPCError(errors.ServerNoPermission.New(ctx.L(), msg))
+	}
+	return collectionDoc, clientDoc, nil
+}
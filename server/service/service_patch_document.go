@@ -36,7 +36,7 @@ func (its *OrdaService) PatchDocument(goCtx gocontext.Context, req *model.PatchM
 	}
 	defer lock.Unlock()
 
-	datatypeDoc, rpcErr := its.managers.Mongo.GetDatatypeByKey(ctx, collectionDoc.Num, req.Key)
+	datatypeDoc, rpcErr := its.managers.Repository.GetDatatypeByKey(ctx, collectionDoc.Num, req.Key)
 	if rpcErr != nil {
 		return nil, rpcErr
 	}
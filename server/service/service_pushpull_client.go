@@ -4,38 +4,46 @@ import (
 	gocontext "context"
 	"fmt"
 	"github.com/orda-io/orda/client/pkg/context"
-	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/server/schema"
 	"reflect"
 
 	"github.com/orda-io/orda/server/constants"
 )
 
-// ProcessPushPull processes a GRPC for Push-Pull
+// ProcessPushPull processes a GRPC for Push-Pull. The response of a request having a seq is remembered for a while,
+// so that the retried request gets exactly the same response.
 func (its *OrdaService) ProcessPushPull(goCtx gocontext.Context, in *model.PushPullMessage) (*model.PushPullMessage, error) {
 	ctx := context.NewOrdaContext(goCtx, constants.TagPushPull).
 		UpdateClientTags("", in.Cuid)
-	collectionDoc, rpcErr := its.getCollectionDocWithRPCError(ctx, in.Collection)
+	collectionDoc, clientDoc, rpcErr := its.ValidateClient(ctx, in.Collection, in.Cuid)
 	if rpcErr != nil {
 		return nil, rpcErr
 	}
-	ctx.UpdateCollectionTags(collectionDoc.Name, collectionDoc.Num)
-
-	clientDoc, err := its.managers.Mongo.GetClient(ctx, in.Cuid)
-	if err != nil {
-		return nil, errors.NewRPCError(err)
-	}
-	if clientDoc == nil {
-		msg := fmt.Sprintf("no client '%s:%s'", in.Collection, in.Cuid)
-		return nil, errors.NewRPCError(errors.ServerNoResource.New(ctx.L(), msg))
-	}
-	ctx.UpdateClientTags(clientDoc.Alias, clientDoc.CUID)
 	ctx.L().Infof("↪[PUPU] %v", in.ToString(false))
-	if clientDoc.CollectionNum != collectionDoc.Num {
-		msg := fmt.Sprintf("client '%s' accesses collection(%d)", clientDoc.ToString(), collectionDoc.Num)
-		return nil, errors.NewRPCError(errors.ServerNoPermission.New(ctx.L(), msg))
+	if in.GetHeader().GetSeq() == 0 {
+		return its.processPushPullPacks(ctx, in, collectionDoc, clientDoc), nil
 	}
+	// the nonce distinguishes the seqs of a client restarted with the same CUID
+	key := fmt.Sprintf("%s:%s:%d", in.Cuid, in.GetHeader().GetNonce(), in.GetHeader().GetSeq())
+	response, duplicated, err := its.managers.Requests.Do(goCtx, key, in, func(processCtx gocontext.Context) (*model.PushPullMessage, error) {
+		// processed regardless of the request, since the response is returned to the retried one
+		ctx := context.NewOrdaContext(processCtx, constants.TagPushPull).UpdateClientTags("", in.Cuid)
+		return its.processPushPullPacks(ctx, in, collectionDoc, clientDoc), nil
+	})
+	if duplicated {
+		ctx.L().Warnf("↩[PUPU] the response of the duplicate request seq:%d", in.GetHeader().GetSeq())
+	}
+	return response, err
+}
 
+func (its *OrdaService) processPushPullPacks(
+	ctx iface.OrdaContext,
+	in *model.PushPullMessage,
+	collectionDoc *schema.CollectionDoc,
+	clientDoc *schema.ClientDoc,
+) *model.PushPullMessage {
 	response := &model.PushPullMessage{
 		Header:     in.Header,
 		Collection: in.Collection,
@@ -65,5 +73,5 @@ func (its *OrdaService) ProcessPushPull(goCtx gocontext.Context, in *model.PushP
 		}
 	}
 	ctx.L().Infof("↩[PUPU] %v", response.ToString(false))
-	return response, nil
+	return response
 }
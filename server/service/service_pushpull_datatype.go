@@ -7,11 +7,15 @@ import (
 	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/model"
 	"github.com/orda-io/orda/client/pkg/operations"
+	"github.com/orda-io/orda/client/pkg/orda"
+	"github.com/orda-io/orda/server/compaction"
 	"github.com/orda-io/orda/server/constants"
 	"github.com/orda-io/orda/server/managers"
+	"github.com/orda-io/orda/server/retention"
 	"github.com/orda-io/orda/server/schema"
 	"github.com/orda-io/orda/server/snapshot"
 	"github.com/orda-io/orda/server/utils"
+	"math"
 	"runtime/debug"
 )
 
@@ -51,10 +55,13 @@ type PushPullHandler struct {
 	ctx      iface.OrdaContext
 	managers *managers.Managers
 	lock     utils.Lock
+	locked   bool
+	lockHeld bool // whether the caller of the push-pull holds the lock
 
 	casePushPull pushPullCase
 	initialCP    *model.CheckPoint
 	currentCP    *model.CheckPoint
+	prevSseqEnd  uint64
 
 	datatypeDoc   *schema.DatatypeDoc
 	clientDoc     *schema.ClientDoc
@@ -68,7 +75,7 @@ type PushPullHandler struct {
 	resPushPullPack *model.PushPullPack
 	retCh           chan *model.PushPullPack
 
-	pushingOperations []interface{}
+	pushingOperations []*schema.OperationDoc
 	pulledOperations  []model.Operation
 }
 
@@ -101,7 +108,12 @@ func newPushPullHandler(
 }
 
 func (its *PushPullHandler) getLockKey() string {
-	return fmt.Sprintf("PP:%d:%s", its.collectionDoc.Num, its.Key)
+	return getPushPullLockName(its.collectionDoc.Num, its.Key)
+}
+
+// getPushPullLockName returns the name of the lock which push-pulls for a datatype hold.
+func getPushPullLockName(collectionNum int32, key string) string {
+	return utils.GetLockName("PP", collectionNum, key)
 }
 
 // Start begins the push-pull for a datatype and returns the result with the channel 'retCh'
@@ -134,10 +146,18 @@ func (its *PushPullHandler) initialize(retCh chan *model.PushPullPack) errors.Or
 func (its *PushPullHandler) finalize() {
 	if r := recover(); r != nil {
 		its.ctx.L().Errorf("recover panic [%v]: %v", r, string(debug.Stack()))
-
-		return
+		its.err = errors.ServerInternal.New(its.ctx.L(), fmt.Sprintf("panic: %v", r))
+	}
+	if its.resPushPullPack == nil { // finished before initialized, possibly due to a malformed PushPullPack
+		got := its.gotPushPullPack
+		its.resPushPullPack = &model.PushPullPack{Key: got.Key, DUID: got.DUID, Era: got.Era, Type: got.Type}
+		if got.CheckPoint != nil {
+			its.resPushPullPack.CheckPoint = got.CheckPoint.Clone()
+		}
+	}
+	if its.locked {
+		defer its.lock.Unlock()
 	}
-	defer its.lock.Unlock()
 	if its.err == nil {
 		its.ctx.L().Infof("finish with CP %v -> %v and pulled ops: %d",
 			its.initialCP.ToString(), its.currentCP.ToString(), len(its.resPushPullPack.Operations))
@@ -150,10 +170,8 @@ func (its *PushPullHandler) finalize() {
 				if err := its.sendNotification(newCtx); err == nil {
 					// continue
 				}
-				if err := its.reserveUpdateSnapshot(newCtx); err != nil {
-					// continue
-				}
 			}()
+			its.reserveUpdateSnapshot(newCtx)
 		}
 	} else {
 		its.ctx.L().Infof("finish with an error: %v", its.err.Error())
@@ -161,10 +179,22 @@ func (its *PushPullHandler) finalize() {
 		errOp := operations.NewErrorOperation(its.err)
 		its.resPushPullPack.Operations = append(its.resPushPullPack.Operations, errOp.ToModelOperation())
 	}
+	its.encodeOperations()
 	its.ctx.L().Infof("RES[PUPU] %s", its.resPushPullPack.ToString(true))
 	its.retCh <- its.resPushPullPack
 }
 
+// encodeOperations encodes the bodies of the pulled operations in JSON for the client which has not negotiated
+// CapabilityBinaryEncoding, because operations pushed by other clients might be encoded in binary.
+func (its *PushPullHandler) encodeOperations() {
+	if its.clientDoc.HasCapability(model.CapabilityBinaryEncoding) {
+		return
+	}
+	for i, op := range its.resPushPullPack.Operations {
+		its.resPushPullPack.Operations[i] = operations.EncodeBody(op, false)
+	}
+}
+
 func (its *PushPullHandler) recoveryFromPanic() {
 	if r := recover(); r != nil {
 		its.ctx.L().Infof("finished from panic")
@@ -183,9 +213,15 @@ func (its *PushPullHandler) logInitialConditions() {
 	)
 }
 
+// process runs the push-pull. The lock is only an optimization to avoid conflicts among concurrent push-pulls;
+// the correctness is guaranteed by committing with compare-and-set on the version of DatatypeDoc, and retrying on conflicts.
 func (its *PushPullHandler) process(retCh chan *model.PushPullPack) {
-
-	its.lock.TryLock()
+	its.retCh = retCh
+	if its.lockHeld {
+		its.ctx.L().Infof("proceed push-pull with the lock held by the caller")
+	} else if its.locked = its.lock.TryLock(); !its.locked {
+		its.ctx.L().Warnf("proceed push-pull without lock")
+	}
 
 	defer its.finalize()
 
@@ -193,29 +229,55 @@ func (its *PushPullHandler) process(retCh chan *model.PushPullPack) {
 		return
 	}
 
-	if its.err = its.initialize(retCh); its.err != nil {
-		return
+	for retry := 0; ; retry++ {
+		its.err = its.tryPushPull(retCh)
+		if its.err == nil || its.err.Have(errors.ServerDBConflict) == 0 {
+			return
+		}
+		if retry >= constants.MaxPushPullRetries {
+			its.err = errors.PushPullAbortionOfServer.New(its.ctx.L(), its.err.Error())
+			return
+		}
+		its.ctx.L().Warnf("retry push-pull (%d/%d) due to conflict", retry+1, constants.MaxPushPullRetries)
+		its.reset()
 	}
+}
 
-	if its.casePushPull, its.err = its.evaluatePushPullCase(); its.err != nil {
-		return
+func (its *PushPullHandler) tryPushPull(retCh chan *model.PushPullPack) errors.OrdaError {
+	if err := its.initialize(retCh); err != nil {
+		return err
 	}
 
-	if its.err = its.processSubscribeOrCreate(its.casePushPull); its.err != nil {
-		return
+	var err errors.OrdaError
+	if its.casePushPull, err = its.evaluatePushPullCase(); err != nil {
+		return err
 	}
 
+	if err = its.processSubscribeOrCreate(its.casePushPull); err != nil {
+		return err
+	}
+	its.resPushPullPack.Era = its.datatypeDoc.Era
+	if err = its.checkEra(); err != nil {
+		return err
+	}
+	its.prevSseqEnd = its.datatypeDoc.Sseq.End
+
 	its.logInitialConditions()
 
-	if its.err = its.pushOperations(); its.err != nil {
-		return
-	}
-	if its.err = its.pullOperations(); its.err != nil {
-		return
+	if err = its.pushOperations(); err != nil {
+		return err
 	}
-	if its.err = its.commitToMongoDB(); its.err != nil {
-		return
+	if err = its.pullOperations(); err != nil {
+		return err
 	}
+	return its.commit()
+}
+
+// reset clears the states of the conflicted push-pull in order to retry it with the latest DatatypeDoc.
+func (its *PushPullHandler) reset() {
+	its.datatypeDoc = nil
+	its.subClientDoc = nil
+	its.pushingOperations = nil
 }
 
 func (its *PushPullHandler) sendNotification(ctx iface.OrdaContext) errors.OrdaError {
@@ -227,32 +289,53 @@ func (its *PushPullHandler) sendNotification(ctx iface.OrdaContext) errors.OrdaE
 		its.currentCP.Sseq)
 }
 
-func (its *PushPullHandler) reserveUpdateSnapshot(ctx iface.OrdaContext) error {
-	snapshotManager := snapshot.NewManager(ctx, its.managers, its.datatypeDoc, its.collectionDoc)
-	if err := snapshotManager.UpdateSnapshot(); err != nil { // TODO: should be asynchronous
-		return err
+// reserveUpdateSnapshot reserves updating the snapshot, compacting the operations and pruning the snapshots of the datatype;
+// the reservations of a hot datatype are coalesced, so that it does not rebuild the snapshot on every push-pull.
+func (its *PushPullHandler) reserveUpdateSnapshot(ctx iface.OrdaContext) {
+	managers, datatypeDoc, collectionDoc := its.managers, its.datatypeDoc, its.collectionDoc
+	its.managers.Snapshots.Reserve(datatypeDoc.DUID, uint64(len(its.pushingOperations)), func() errors.OrdaError {
+		if err := snapshot.NewManager(ctx, managers, datatypeDoc, collectionDoc).UpdateSnapshot(); err != nil {
+			return err
+		}
+		if err := compactOperations(ctx, managers, collectionDoc.Num, datatypeDoc.DUID); err != nil {
+			return err
+		}
+		return pruneSnapshots(ctx, managers, collectionDoc.Num, datatypeDoc.DUID)
+	})
+}
+
+func compactOperations(ctx iface.OrdaContext, managers *managers.Managers, collectionNum int32, duid string) errors.OrdaError {
+	if managers.Compaction == nil {
+		return nil
 	}
-	return nil
+	return compaction.Compact(ctx, managers.Repository, managers.Compaction, collectionNum, duid)
 }
 
-func (its *PushPullHandler) commitToMongoDB() errors.OrdaError {
-	its.datatypeDoc.Sseq.End = its.currentCP.Sseq
+func pruneSnapshots(ctx iface.OrdaContext, managers *managers.Managers, collectionNum int32, duid string) errors.OrdaError {
+	if managers.Retention == nil {
+		return nil
+	}
+	return retention.Prune(ctx, managers.Repository, managers.Retention, collectionNum, duid)
+}
+
+// commit atomically stores the pushed operations and the DatatypeDoc whose Sseq.End covers them.
+func (its *PushPullHandler) commit() errors.OrdaError {
+	its.datatypeDoc.Sseq.End += uint64(len(its.pushingOperations))
 	its.resPushPullPack.CheckPoint = its.currentCP
 	its.subClientDoc.UpdateAt()
-	if len(its.pushingOperations) > 0 {
-		if err := its.managers.Mongo.InsertOperations(its.ctx, its.pushingOperations); err != nil {
-			return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
-		}
-		its.ctx.L().Infof("commit %d OperationDocs", len(its.pushingOperations))
+	if err := its.updateStability(); err != nil {
+		return err
 	}
-
-	if err := its.managers.Mongo.UpdateDatatype(its.ctx, its.datatypeDoc); err != nil {
+	if err := its.managers.Repository.CommitPushPull(its.ctx, its.datatypeDoc, its.pushingOperations); err != nil {
+		if err.Have(errors.ServerDBConflict) > 0 {
+			return err
+		}
 		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
 	}
-	its.ctx.L().Infof("commit DatatypeDoc [%s]", its.datatypeDoc)
+	its.ctx.L().Infof("commit %d OperationDocs and DatatypeDoc [%s]", len(its.pushingOperations), its.datatypeDoc)
 
 	// if !admin.IsAdminCUID(its.CUID) {
-	// 	if err := its.managers.Mongo.UpdateCheckPointInClient(its.ctx, its.CUID, its.DUID, its.currentCP); err != nil {
+	// 	if err := its.managers.Repository.UpdateCheckPointInClient(its.ctx, its.CUID, its.DUID, its.currentCP); err != nil {
 	// 		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
 	// 	}
 	// 	its.ctx.L().Infof("commit CheckPoint with %s", its.currentCP.String())
@@ -260,21 +343,334 @@ func (its *PushPullHandler) commitToMongoDB() errors.OrdaError {
 	return nil
 }
 
+// updateStability updates the bounds reported by the client, and the causal stability of the datatype computed from
+// the bounds of all the subscribed clients. The stability is delivered to the client which has pulled all the
+// operations until its sseq, so that the client can purge the tombstones deleted before the stable timestamp.
+func (its *PushPullHandler) updateStability() errors.OrdaError {
+	if its.subClientDoc == nil {
+		return nil
+	}
+	if its.gotOption.HasSubscribeBit() || its.gotOption.HasCreateBit() {
+		// the client starts with a new Lamport, which might be less than the stability
+		its.datatypeDoc.Stability = nil
+		its.subClientDoc.Seen, its.subClientDoc.Lamport, its.subClientDoc.Era = 0, 0, 0
+	} else if its.gotPushPullPack.GetSnapshotChunk() == nil && its.clientDoc.HasCapability(model.CapabilityStability) {
+		lowest := its.gotPushPullPack.GetLowest()
+		its.subClientDoc.Seen = its.gotPushPullPack.GetSeenSseq()
+		its.subClientDoc.Lamport, its.subClientDoc.Era = lowest.GetLamport(), lowest.GetEra()
+	}
+	stability, err := its.computeStability()
+	if err != nil {
+		return err
+	}
+	if stability != nil {
+		its.datatypeDoc.Stability = stability
+	}
+	stability = its.datatypeDoc.Stability
+	if stability != nil && its.currentCP.Sseq >= stability.Sseq && its.resPushPullPack.SnapshotChunk == nil &&
+		!its.resPushPullPack.GetPushPullPackOption().HasMoreBit() {
+		its.resPushPullPack.Stable = stability.GetStable()
+	}
+	return nil
+}
+
+// computeStability returns the causal stability from the bounds of all the subscribed clients, and the Lamports of
+// the operations which some of them have not seen. It returns nil if any client has not reported its bounds, or
+// the operations not seen are too many or compacted.
+func (its *PushPullHandler) computeStability() (*schema.StabilityDoc, errors.OrdaError) {
+	end := its.datatypeDoc.Sseq.End
+	seen := end
+	stability := &schema.StabilityDoc{Era: its.datatypeDoc.Era, Lamport: math.MaxUint64, Sseq: end}
+	for _, clients := range []map[string]*schema.SubscribedClientDoc{its.datatypeDoc.RWClients, its.datatypeDoc.ROClients} {
+		for _, client := range clients {
+			if client.Lamport == 0 {
+				return nil, nil
+			}
+			if client.Seen < seen {
+				seen = client.Seen
+			}
+			if client.Lamport < stability.Lamport {
+				stability.Lamport = client.Lamport
+			}
+			if client.Era < stability.Era {
+				stability.Era = client.Era
+			}
+		}
+	}
+	if limit := its.managers.PullLimit; limit > 0 && end-seen > limit {
+		return nil, nil
+	}
+	if seen < its.prevSseqEnd {
+		opList, sseqList, err := its.managers.Repository.GetOperations(its.ctx, its.DUID, seen+1, its.prevSseqEnd)
+		if err != nil {
+			return nil, errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+		}
+		if uint64(len(sseqList)) != its.prevSseqEnd-seen {
+			return nil, nil
+		}
+		for _, op := range opList {
+			if op.GetID().GetLamport() < stability.Lamport {
+				stability.Lamport = op.GetID().GetLamport()
+			}
+		}
+	}
+	for _, opDoc := range its.pushingOperations {
+		if opDoc.OpID.Lamport < stability.Lamport {
+			stability.Lamport = opDoc.OpID.Lamport
+		}
+	}
+	return stability, nil
+}
+
+// checkEra rejects the push-pull of a client in an old era, which should rebase on a snapshot of the current era.
+// It also rejects with PushPullBehindCompaction the client pushing operations without having pulled the compacted
+// operations, since its operations might depend on the tombstones purged in the meantime. The client without CapabilityRebase is not
+// rejected, but catches up with a snapshot if it falls behind Sseq.Begin.
+func (its *PushPullHandler) checkEra() errors.OrdaError {
+	if its.gotOption.HasCreateBit() || its.gotOption.HasSubscribeBit() {
+		return nil
+	}
+	if !its.clientDoc.HasCapability(model.CapabilityRebase) {
+		return nil
+	}
+	if its.gotPushPullPack.Era != its.datatypeDoc.Era {
+		msg := fmt.Sprintf("era %d of the client vs era %d", its.gotPushPullPack.Era, its.datatypeDoc.Era)
+		return errors.PushPullEraMismatch.New(its.ctx.L(), msg)
+	}
+	sseq := its.gotPushPullPack.CheckPoint.Sseq
+	if len(its.gotPushPullPack.Operations) > 0 && !its.gotOption.HasSnapshotBit() && sseq+1 < its.datatypeDoc.Sseq.Begin {
+		msg := fmt.Sprintf("sseq %d of the client is behind the compacted sseq.begin %d", sseq, its.datatypeDoc.Sseq.Begin)
+		return errors.PushPullBehindCompaction.New(its.ctx.L(), msg)
+	}
+	return nil
+}
+
 func (its *PushPullHandler) pullOperations() errors.OrdaError {
 	if its.clientDoc.GetType() == model.ClientType_VOLATILE {
 		return nil
 	}
+	if its.gotPushPullPack.GetPath() != "" {
+		return its.pullDocumentPath()
+	}
 	sseqBegin := its.gotPushPullPack.CheckPoint.Sseq + 1
-	if its.datatypeDoc.Sseq.Begin <= sseqBegin && !its.gotOption.HasSnapshotBit() {
-		opList, sseqList, err := its.managers.Mongo.GetOperations(its.ctx, its.DUID, sseqBegin, constants.InfinitySseq)
+	if chunk := its.gotPushPullPack.GetSnapshotChunk(); chunk != nil {
+		// the client continues to pull the chunks of a snapshot
+		return its.pullSnapshotChunk(chunk)
+	}
+	if its.gotOption.HasSnapshotBit() {
+		// the client rebases on a snapshot
+		return its.pullSnapshot(nil)
+	}
+	if its.datatypeDoc.Sseq.Begin > sseqBegin {
+		return its.pullSnapshot(nil)
+	}
+	if its.isFarBehind() {
+		snapshotDoc, err := its.managers.Repository.GetLatestSnapshot(its.ctx, its.collectionDoc.Num, its.DUID)
 		if err != nil {
 			return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
 		}
-		if len(opList) > 0 {
-			its.currentCP.Sseq = sseqList[len(sseqList)-1] + (uint64)(len(its.pushingOperations))
+		if snapshotDoc != nil && snapshotDoc.Sseq >= sseqBegin {
+			return its.pullSnapshot(snapshotDoc)
+		}
+	}
+	sseqEnd := its.getPullingSseqEnd(sseqBegin - 1)
+	opList, sseqList, err := its.managers.Repository.GetOperations(its.ctx, its.DUID, sseqBegin, sseqEnd)
+	if err != nil {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+	}
+	if sseqEnd >= sseqBegin && uint64(len(opList)) != sseqEnd-sseqBegin+1 {
+		// some operations have been compacted after reading the datatypeDoc
+		return its.pullSnapshot(nil)
+	}
+	if len(opList) > 0 {
+		its.currentCP.Sseq = sseqList[len(sseqList)-1] + (uint64)(len(its.pushingOperations))
+	}
+	its.resPushPullPack.Operations = opList
+	return nil
+}
+
+// hasMoreToPull examines if the operations to pull after the sseq are more than the limit of a PushPullPack.
+// The client without CapabilityPagination pulls all the operations at once.
+func (its *PushPullHandler) hasMoreToPull(sseq uint64) bool {
+	if !its.clientDoc.HasCapability(model.CapabilityPagination) {
+		return false
+	}
+	limit := its.managers.PullLimit
+	return limit > 0 && its.datatypeDoc.Sseq.End > sseq && its.datatypeDoc.Sseq.End-sseq > limit
+}
+
+// getPullingSseqEnd returns the last sseq to pull after the sseq. If more operations remain, MoreBit is set.
+func (its *PushPullHandler) getPullingSseqEnd(sseq uint64) uint64 {
+	if its.hasMoreToPull(sseq) {
+		its.resPushPullPack.GetPushPullPackOption().SetMoreBit()
+		return sseq + its.managers.PullLimit
+	}
+	return its.datatypeDoc.Sseq.End
+}
+
+// isFarBehind examines if the client falls behind more operations than the configured gap.
+func (its *PushPullHandler) isFarBehind() bool {
+	gap := its.managers.CatchUpGap
+	sseq := its.gotPushPullPack.CheckPoint.Sseq
+	return gap > 0 && its.datatypeDoc.Sseq.End > sseq && its.datatypeDoc.Sseq.End-sseq > gap
+}
+
+// pullSnapshot makes the client, which falls behind Sseq.Begin or too far, catch up with the latest snapshot and
+// the following operations. The response has the snapshot bit, and its first operation is a SnapshotOperation.
+// If snapshotDoc is nil, the latest one is read; if none has been made, all the operations are pulled, because
+// the first one is the SnapshotOperation of the creation.
+func (its *PushPullHandler) pullSnapshot(snapshotDoc *schema.SnapshotDoc) errors.OrdaError {
+	if snapshotDoc == nil {
+		var err errors.OrdaError
+		if snapshotDoc, err = its.managers.Repository.GetLatestSnapshot(its.ctx, its.collectionDoc.Num, its.DUID); err != nil {
+			return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+		}
+		if snapshotDoc == nil {
+			if its.datatypeDoc.Sseq.Begin > 1 {
+				return errors.PushPullAbortionOfServer.New(its.ctx.L(), "no snapshot for the compacted operations")
+			}
+			return its.pullAllOperations()
+		}
+	}
+	return its.pullSnapshotFrom(snapshotDoc, 0)
+}
+
+// pullSnapshotChunk pulls the chunk of the snapshot requested by the client. If the snapshot has been pruned
+// in the meantime, the client starts over with the latest snapshot.
+func (its *PushPullHandler) pullSnapshotChunk(chunk *model.SnapshotChunk) errors.OrdaError {
+	snapshotDocs, err := its.managers.Repository.GetSnapshots(its.ctx, its.collectionDoc.Num, its.DUID)
+	if err != nil {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+	}
+	for _, snapshotDoc := range snapshotDocs {
+		if snapshotDoc.Sseq == chunk.Sseq {
+			return its.pullSnapshotFrom(snapshotDoc, chunk.Offset)
+		}
+	}
+	its.ctx.L().Infof("start over pulling snapshot due to no snapshot of sseq %d", chunk.Sseq)
+	return its.pullSnapshot(nil)
+}
+
+// pullSnapshotFrom pulls the snapshot from the offset and the following operations. For the client of
+// CapabilityChunkedSnapshot, the snapshot is pulled in chunks of at most ChunkSize bytes; until the last chunk,
+// the response has MoreBit without any operations, and the CheckPoint does not proceed.
+func (its *PushPullHandler) pullSnapshotFrom(snapshotDoc *schema.SnapshotDoc, offset uint64) errors.OrdaError {
+	snap := snapshotDoc.Snapshot
+	if !its.clientDoc.HasCapability(model.CapabilityBinaryEncoding) {
+		var err errors.OrdaError
+		if snap, err = snapshot.EncodeInJSON(its.ctx, its.datatypeDoc.GetType(), snap); err != nil {
+			return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+		}
+	}
+	its.resPushPullPack.GetPushPullPackOption().SetSnapshotBit()
+	total := uint64(len(snap))
+	if its.clientDoc.HasCapability(model.CapabilityChunkedSnapshot) && (offset > 0 || total > its.managers.ChunkSize) {
+		if offset > total {
+			offset = 0
+		}
+		end := offset + its.managers.ChunkSize
+		if end > total {
+			end = total
 		}
-		its.resPushPullPack.Operations = opList
+		its.resPushPullPack.SnapshotChunk = &model.SnapshotChunk{
+			Sseq:   snapshotDoc.Sseq,
+			Offset: offset,
+			Total:  total,
+			Data:   snap[offset:end],
+		}
+		its.ctx.L().Infof("pull chunk [%d, %d) of %d bytes of snapshot of sseq %d", offset, end, total, snapshotDoc.Sseq)
+		if end < total {
+			its.resPushPullPack.GetPushPullPackOption().SetMoreBit()
+			its.currentCP.Sseq = its.initialCP.Sseq
+			return nil
+		}
+	}
+	sseqEnd := its.getPullingSseqEnd(snapshotDoc.Sseq)
+	opList, sseqList, err := its.managers.Repository.GetOperations(its.ctx, its.DUID, snapshotDoc.Sseq+1, sseqEnd)
+	if err != nil {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+	}
+	lastSseq := snapshotDoc.Sseq
+	if len(sseqList) > 0 {
+		lastSseq = sseqList[len(sseqList)-1]
+	}
+	if its.resPushPullPack.SnapshotChunk == nil {
+		snapOp := operations.NewSnapshotOperation(its.datatypeDoc.GetType(), snap)
+		opList = append(model.OpList{snapOp.ToModelOperation()}, opList...)
+	}
+	its.resPushPullPack.Operations = opList
+	its.currentCP.Sseq = lastSseq + (uint64)(len(its.pushingOperations))
+	its.ctx.L().Infof("pull snapshot of sseq %d and %d operations", snapshotDoc.Sseq, len(opList))
+	return nil
+}
+
+// pullDocumentPath pulls only the snapshot and the operations affecting the subtree of the Document at the path,
+// to which the client subscribes partially. The targets of the operations are resolved against the latest Document.
+func (its *PushPullHandler) pullDocumentPath() errors.OrdaError {
+	if its.datatypeDoc.GetType() != model.TypeOfDatatype_DOCUMENT {
+		return errors.PushPullAbortionOfClient.New(its.ctx.L(), "partial subscription to "+its.datatypeDoc.Type)
+	}
+	datatype, lastSseq, err := snapshot.NewManager(its.ctx, its.managers, its.datatypeDoc, its.collectionDoc).GetLatestDatatype()
+	if err != nil {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+	}
+	filter, err := orda.NewDocumentPathFilter(datatype.(orda.Document), its.gotPushPullPack.GetPath())
+	if err != nil {
+		return errors.PushPullAbortionOfClient.New(its.ctx.L(), err.Error())
+	}
+	sseqBegin := its.gotPushPullPack.CheckPoint.Sseq + 1
+	if its.gotOption.HasSubscribeBit() || its.gotOption.HasSnapshotBit() ||
+		its.datatypeDoc.Sseq.Begin > sseqBegin || its.isFarBehind() {
+		return its.pullDocumentPathSnapshot(filter, lastSseq)
+	}
+	sseqEnd := its.getPullingSseqEnd(sseqBegin - 1)
+	opList, sseqList, err := its.managers.Repository.GetOperations(its.ctx, its.DUID, sseqBegin, sseqEnd)
+	if err != nil {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+	}
+	if sseqEnd >= sseqBegin && uint64(len(opList)) != sseqEnd-sseqBegin+1 {
+		return its.pullDocumentPathSnapshot(filter, lastSseq)
 	}
+	if len(opList) > 0 {
+		its.currentCP.Sseq = sseqList[len(sseqList)-1] + (uint64)(len(its.pushingOperations))
+	}
+	its.resPushPullPack.Operations = filter.FilterOperations(opList)
+	its.ctx.L().Infof("pull %d of %d operations for path '%s'",
+		len(its.resPushPullPack.Operations), len(opList), its.gotPushPullPack.GetPath())
+	return nil
+}
+
+// pullDocumentPathSnapshot pulls the snapshot of the subtree of the Document, which reflects the operations to lastSseq.
+func (its *PushPullHandler) pullDocumentPathSnapshot(filter *orda.DocumentPathFilter, lastSseq uint64) errors.OrdaError {
+	snap, err := filter.MarshalSnapshot(its.clientDoc.HasCapability(model.CapabilityBinaryEncoding))
+	if err != nil {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+	}
+	snapOp := operations.NewSnapshotOperation(model.TypeOfDatatype_DOCUMENT, snap)
+	its.resPushPullPack.Operations = model.OpList{snapOp.ToModelOperation()}
+	its.resPushPullPack.GetPushPullPackOption().SetSnapshotBit()
+	its.currentCP.Sseq = lastSseq + (uint64)(len(its.pushingOperations))
+	its.ctx.L().Infof("pull snapshot of sseq %d for path '%s'", lastSseq, its.gotPushPullPack.GetPath())
+	return nil
+}
+
+// pullAllOperations pulls the operations from the first one, which is the SnapshotOperation of the creation.
+func (its *PushPullHandler) pullAllOperations() errors.OrdaError {
+	sseqEnd := its.getPullingSseqEnd(0)
+	opList, sseqList, err := its.managers.Repository.GetOperations(its.ctx, its.DUID, 1, sseqEnd)
+	if err != nil {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), err.Error())
+	}
+	if len(opList) == 0 {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), "no operation of the creation")
+	}
+	if _, ok := operations.ModelToOperation(opList[0]).(*operations.SnapshotOperation); !ok {
+		return errors.PushPullAbortionOfServer.New(its.ctx.L(), "no snapshot operation of the creation")
+	}
+	its.resPushPullPack.Operations = opList
+	its.resPushPullPack.GetPushPullPackOption().SetSnapshotBit()
+	its.currentCP.Sseq = sseqList[len(sseqList)-1] + (uint64)(len(its.pushingOperations))
+	its.ctx.L().Infof("pull all %d operations from the creation", len(opList))
 	return nil
 }
 
@@ -282,6 +678,11 @@ func (its *PushPullHandler) pushOperations() errors.OrdaError {
 	if its.isReadOnly {
 		return nil
 	}
+	if its.hasMoreToPull(its.gotPushPullPack.CheckPoint.Sseq) {
+		// the operations are pushed again after the client pulls all
+		its.ctx.L().Infof("defer pushing %d operations due to many operations to pull", len(its.gotPushPullPack.Operations))
+		return nil
+	}
 	its.currentCP.Sseq = its.datatypeDoc.Sseq.End
 	for _, op := range its.gotPushPullPack.Operations {
 		switch {
@@ -294,6 +695,8 @@ func (its *PushPullHandler) pushOperations() errors.OrdaError {
 		case its.currentCP.Cseq >= op.ID.GetSeq():
 			its.ctx.L().Warnf("reject operation due to duplicate: %v", op.String())
 		default:
+			// the CheckPoint of the server lets the client resend the missing operations
+			its.resPushPullPack.CheckPoint = its.initialCP.Clone()
 			msg := fmt.Sprintf("cp.Cseq=%d < op.Seq=%d", its.initialCP.Cseq, op.ID.GetSeq())
 			return errors.PushPullMissingOps.New(its.ctx.L(), msg)
 		}
@@ -308,6 +711,8 @@ func (its *PushPullHandler) processSubscribeOrCreate(code pushPullCase) errors.O
 			return its.createDatatype()
 		case caseAllMatchedNotSubscribed:
 			return its.subscribeDatatype()
+		case caseAllMatchedSubscribed:
+			return its.resubscribeDatatype()
 		}
 	} else if its.gotOption.HasSubscribeBit() {
 		switch code {
@@ -316,6 +721,7 @@ func (its *PushPullHandler) processSubscribeOrCreate(code pushPullCase) errors.O
 		case caseUsedDUID:
 		case caseMatchKeyNotType:
 		case caseAllMatchedSubscribed:
+			return its.resubscribeDatatype()
 		case caseAllMatchedNotSubscribed:
 			return its.subscribeDatatype()
 		case caseAllMatchedNotVisible:
@@ -332,6 +738,9 @@ func (its *PushPullHandler) processSubscribeOrCreate(code pushPullCase) errors.O
 		case caseAllMatchedNotVisible: //
 		default:
 		}
+	} else if code == caseMatchNothing {
+		// the datatype has been removed, for example, by resetting the collection
+		return errors.PushPullNoDatatypeToSubscribe.New(its.ctx.L(), its.Key)
 	}
 	return its.initClientInfoWithDatatypeDoc()
 }
@@ -349,6 +758,14 @@ func (its *PushPullHandler) subscribeDatatype() errors.OrdaError {
 	return nil
 }
 
+// resubscribeDatatype continues the subscription of the client which has not learned the DUID yet,
+// for example, while pulling the snapshot in chunks.
+func (its *PushPullHandler) resubscribeDatatype() errors.OrdaError {
+	its.DUID = its.datatypeDoc.DUID
+	its.resPushPullPack.DUID = its.datatypeDoc.DUID
+	return its.initClientInfoWithDatatypeDoc()
+}
+
 func (its *PushPullHandler) createDatatype() errors.OrdaError {
 	its.datatypeDoc = schema.NewDatatypeDoc(its.DUID, its.Key, its.collectionDoc.Num, its.gotPushPullPack.Type.String())
 	option := model.PushPullBitNormal
@@ -382,7 +799,7 @@ func (its *PushPullHandler) evaluatePushPullCase() (pushPullCase, errors.OrdaErr
 	var err errors.OrdaError
 	// (1) first, when having createBit or subscribeBit, check if there exists a datatypeDoc for the key
 	if its.gotOption.HasCreateBit() || its.gotOption.HasSubscribeBit() {
-		its.datatypeDoc, err = its.managers.Mongo.GetDatatypeByKey(its.ctx, its.collectionDoc.Num, its.gotPushPullPack.Key)
+		its.datatypeDoc, err = its.managers.Repository.GetDatatypeByKey(its.ctx, its.collectionDoc.Num, its.gotPushPullPack.Key)
 		if err != nil {
 			return caseError, errors.PushPullAbortionOfServer.New(its.ctx.L(), "fail to get datatype by key from DB")
 		}
@@ -390,7 +807,7 @@ func (its *PushPullHandler) evaluatePushPullCase() (pushPullCase, errors.OrdaErr
 
 	// (2) except with createBit or subscribeBit, search datatypeDoc by DUID
 	if its.datatypeDoc == nil {
-		its.datatypeDoc, err = its.managers.Mongo.GetDatatype(its.ctx, its.DUID)
+		its.datatypeDoc, err = its.managers.Repository.GetDatatype(its.ctx, its.DUID)
 		if err != nil {
 			return caseError, errors.PushPullAbortionOfServer.New(its.ctx.L(), "fail to get datatype by duid from DB")
 		}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/server/admin"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/snapshot"
+)
+
+// RestoreDatatype restores a datatype as of the specified sseq or timestamp. The restored state is pushed as new
+// operations, and a new era is started so that the subscribed clients rebase on it.
+func (its *OrdaService) RestoreDatatype(goCtx gocontext.Context, req *model.RestoreMessage) (*model.RestoreMessage, error) {
+	ctx := context.NewOrdaContext(goCtx, constants.TagRestore).
+		UpdateCollectionTags(req.Collection, 0)
+	collectionDoc, rpcErr := its.getCollectionDocWithRPCError(ctx, req.Collection)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	clientDoc := admin.NewRestoreClient(collectionDoc)
+	ctx.UpdateCollectionTags(collectionDoc.Name, collectionDoc.Num).
+		UpdateClientTags(clientDoc.Alias, clientDoc.CUID).
+		UpdateDatatypeTags(req.Key, "")
+
+	ctx.L().Infof("BEGIN RestoreDatatype: '%v' sseq:%d, timestamp:%d", req.Key, req.Sseq, req.Timestamp)
+	defer ctx.L().Infof("END RestoreDatatype: '%v'", req.Key)
+
+	if req.Sseq == 0 && req.Timestamp == 0 {
+		return nil, errors.NewRPCError(errors.ServerBadRequest.New(ctx.L(), "either sseq or timestamp is required"))
+	}
+
+	// holding the lock of push-pulls, the restored state is not computed on a stale state of the datatype
+	lock := its.managers.GetLock(ctx, getPushPullLockName(collectionDoc.Num, req.Key))
+	if !lock.TryLock() {
+		return nil, errors.NewRPCError(errors.ServerInit.New(ctx.L(), "fail to lock"))
+	}
+	defer lock.Unlock()
+
+	datatypeDoc, rpcErr := its.managers.Repository.GetDatatypeByKey(ctx, collectionDoc.Num, req.Key)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if datatypeDoc == nil {
+		return nil, errors.NewRPCError(errors.ServerNoResource.New(ctx.L(), "datatype "+req.Key))
+	}
+	if req.Sseq > datatypeDoc.Sseq.End {
+		return nil, errors.NewRPCError(errors.ServerBadRequest.New(ctx.L(),
+			fmt.Sprintf("sseq %d is beyond the end %d", req.Sseq, datatypeDoc.Sseq.End)))
+	}
+	ctx.UpdateDatatypeTags(datatypeDoc.Key, datatypeDoc.DUID)
+
+	snapshotManager := snapshot.NewManager(ctx, its.managers, datatypeDoc, collectionDoc)
+	data, lastSseq, err := snapshotManager.GetLatestDatatype()
+	if err != nil {
+		return nil, errors.NewRPCError(err)
+	}
+	target, targetSseq, err := snapshotManager.GetDatatypeAt(req.Sseq, time.Unix(req.Timestamp, 0))
+	if err != nil {
+		return nil, errors.NewRPCError(err)
+	}
+	ctx.L().Infof("restore '%v' as of sseq %d", req.Key, targetSseq)
+
+	if lastSseq > 0 {
+		// only the operations to converge are pushed, without the snapshot operation of creating the datatype
+		data.ResetWired()
+		data.SetState(model.StateOfDatatype_SUBSCRIBED)
+		data.SetCheckPoint(lastSseq, 0)
+	}
+	if err = snapshot.Converge(data, target); err != nil {
+		return nil, errors.NewRPCError(err)
+	}
+
+	ppp := data.CreatePushPullPack()
+	if len(ppp.Operations) > 0 {
+		ctx.L().Infof("%v", ppp.ToString(true))
+		pushPullHandler := newPushPullHandler(ctx, ppp, clientDoc, collectionDoc, its.managers)
+		pushPullHandler.lockHeld = true
+		pppCh := pushPullHandler.Start()
+		res := <-pppCh
+		if res.GetPushPullPackOption().HasErrorBit() {
+			return nil, errors.NewRPCError(errors.ServerInternal.New(ctx.L(), "fail to push the restored state"))
+		}
+	}
+	era, err := its.managers.Repository.BumpEra(ctx, datatypeDoc.DUID)
+	if err != nil {
+		return nil, errors.NewRPCError(err)
+	}
+	ctx.L().Infof("start era %d of '%v' after restoration", era, req.Key)
+	restored, jErr := json.Marshal(data.ToJSON())
+	if jErr != nil {
+		return nil, errors.NewRPCError(errors.ServerInternal.New(ctx.L(), jErr.Error()))
+	}
+	return &model.RestoreMessage{
+		Key:        req.Key,
+		Collection: req.Collection,
+		Sseq:       targetSseq,
+		Timestamp:  req.Timestamp,
+		Json:       string(restored),
+	}, nil
+}
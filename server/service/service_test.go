@@ -2,25 +2,639 @@ package service_test
 
 import (
 	gocontext "context"
+	"fmt"
 	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/operations"
 	"github.com/orda-io/orda/client/pkg/orda"
+	"github.com/orda-io/orda/client/pkg/types"
+	"github.com/orda-io/orda/server/compaction"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/schema"
 	"github.com/orda-io/orda/server/service"
 	"github.com/orda-io/orda/server/testonly"
 	"github.com/orda-io/orda/server/wrapper"
 	integration "github.com/orda-io/orda/test"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"net"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestOrdaService(t *testing.T) {
 
 	_, ctx, _ := integration.InitTestDBCollection(t, testonly.TestDBName)
 	managers, err := integration.NewTestManagers(ctx, testonly.TestDBName)
-	svc := service.NewOrdaService(managers)
 	require.NoError(t, err)
+	testOrdaService(t, ctx, service.NewOrdaService(managers))
+}
+
+func TestOrdaServiceWithMemory(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+	testOrdaService(t, f.Ctx, f.Service)
+}
+
+func TestPushPullAfterCompaction(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+
+	client1 := f.NewClient("1")
+	counter1 := client1.CreateCounter(t.Name(), nil)
+	wrapper1 := f.Wrap(counter1, model.SupportedCapabilities)
+	_, _ = counter1.IncreaseBy(3)
+	f.PushPull(wrapper1)
+	duid := wrapper1.GetDUID()
+
+	require.Eventually(t, func() bool {
+		snapshotDoc, oErr := f.Managers.Repository.GetLatestSnapshot(f.Ctx, f.CollectionNum, duid)
+		return oErr == nil && snapshotDoc != nil && snapshotDoc.Sseq == 2
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, compaction.Compact(f.Ctx, f.Managers.Repository, &compaction.Config{}, f.CollectionNum, duid))
+	_, sseqList, oErr := f.Managers.Repository.GetOperations(f.Ctx, duid, 1, constants.InfinitySseq)
+	require.NoError(t, oErr)
+	require.Equal(t, 0, len(sseqList))
+
+	// the client in the old era rebases its pushing operations on the snapshot of the new era
+	_, _ = counter1.IncreaseBy(2)
+	ppp := f.PushPull(wrapper1)
+	require.Equal(t, errors.PushPullEraMismatch, getPushPullErrorCode(ppp))
+	require.Equal(t, uint32(1), ppp.Era)
+	require.True(t, wrapper1.NeedRebase())
+	ppp = f.PushPull(wrapper1)
+	require.True(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.False(t, wrapper1.NeedRebase())
+	require.Equal(t, int32(5), counter1.Get())
+
+	client2 := f.NewClient("2")
+	counter2 := client2.SubscribeCounter(t.Name(), nil)
+	wrapper2 := f.Wrap(counter2, model.SupportedCapabilities)
+	ppp = f.PushPull(wrapper2)
+	require.True(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.True(t, ppp.GetPushPullPackOption().HasSubscribeBit())
+	require.Equal(t, uint64(3), ppp.CheckPoint.Sseq)
+	require.Equal(t, counter1.Get(), counter2.Get())
+
+	// client3 cannot rebase, so that it catches up with a snapshot instead
+	client3 := f.NewClient("3")
+	counter3 := client3.SubscribeCounter(t.Name(), nil)
+	wrapper3 := f.Wrap(counter3, model.SupportedCapabilities&^model.CapabilityRebase)
+	f.PushPull(wrapper3)
+
+	_, _ = counter2.Increase()
+	require.False(t, f.PushPull(wrapper2).GetPushPullPackOption().HasErrorBit())
+
+	// the client whose checkpoint is behind sseq.begin rebases its pushing operations on the snapshot, even in the era
+	require.Eventually(t, func() bool {
+		snapshotDoc, oErr := f.Managers.Repository.GetLatestSnapshot(f.Ctx, f.CollectionNum, duid)
+		return oErr == nil && snapshotDoc != nil && snapshotDoc.Sseq == 4
+	}, time.Second, 10*time.Millisecond)
+	_, oErr = f.Managers.Repository.CompactOperations(f.Ctx, duid, 5, 4)
+	require.NoError(t, oErr)
+	_, _ = counter1.Increase()
+	ppp = f.PushPull(wrapper1)
+	require.Equal(t, errors.PushPullBehindCompaction, getPushPullErrorCode(ppp))
+	require.True(t, wrapper1.NeedRebase())
+	ppp = f.PushPull(wrapper1)
+	require.True(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.False(t, wrapper1.NeedRebase())
+	require.Equal(t, int32(7), counter1.Get())
+
+	_, _ = counter3.Increase()
+	ppp = f.PushPull(wrapper3)
+	require.False(t, ppp.GetPushPullPackOption().HasErrorBit())
+	require.True(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.False(t, wrapper3.NeedRebase())
+	f.PushPull(wrapper1)
+	require.Equal(t, int32(8), counter1.Get())
+	require.Equal(t, counter1.Get(), counter3.Get())
+}
+
+func TestCatchUpWithSnapshot(t *testing.T) {
+	serverConf := testonly.NewMemoryServerConfig()
+	catchUpGap := uint64(2)
+	serverConf.CatchUpGap = &catchUpGap
+	f := testonly.NewServiceFixture(t, serverConf)
+
+	client1 := f.NewClient("1")
+	counter1 := client1.CreateCounter(t.Name(), nil)
+	wrapper1 := f.Wrap(counter1, model.SupportedCapabilities)
+	f.PushPull(wrapper1)
+
+	client2 := f.NewClient("2")
+	counter2 := client2.SubscribeCounter(t.Name(), nil)
+	// client2 cannot read snapshots in binary
+	wrapper2 := f.Wrap(counter2, model.SupportedCapabilities&^model.CapabilityBinaryEncoding)
+	f.PushPull(wrapper2)
+
+	for i := 0; i < 4; i++ {
+		_, _ = counter1.Increase()
+		f.PushPull(wrapper1)
+	}
+	require.Eventually(t, func() bool {
+		snapshotDoc, oErr := f.Managers.Repository.GetLatestSnapshot(f.Ctx, f.CollectionNum, wrapper1.GetDUID())
+		return oErr == nil && snapshotDoc != nil && snapshotDoc.Sseq == 5 && operations.IsBinaryBody(snapshotDoc.Snapshot)
+	}, time.Second, 10*time.Millisecond)
+
+	_, _ = counter2.IncreaseBy(10)
+	ppp := f.PushPull(wrapper2)
+	require.True(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.False(t, operations.IsBinaryBody(ppp.Operations[0].Body))
+	require.Equal(t, uint64(6), ppp.CheckPoint.Sseq)
+	require.Equal(t, int32(14), counter2.Get())
+
+	ppp = f.PushPull(wrapper1)
+	require.False(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.Equal(t, counter2.Get(), counter1.Get())
+}
+
+func TestPaginatedPull(t *testing.T) {
+	serverConf := testonly.NewMemoryServerConfig()
+	pullLimit := uint64(3)
+	serverConf.PullLimit = &pullLimit
+	f := testonly.NewServiceFixture(t, serverConf)
+
+	client1 := f.NewClient("1")
+	counter1 := client1.CreateCounter(t.Name(), nil)
+	wrapper1 := f.Wrap(counter1, model.SupportedCapabilities)
+	var ppp *model.PushPullPack
+	for i := 0; i < 7; i++ {
+		_, _ = counter1.Increase()
+		ppp = f.PushPull(wrapper1)
+	}
+	require.Equal(t, uint64(8), ppp.CheckPoint.Sseq)
+
+	client2 := f.NewClient("2")
+	counter2 := client2.SubscribeCounter(t.Name(), nil)
+	wrapper2 := f.Wrap(counter2, model.SupportedCapabilities)
+	ppp = f.PushPull(wrapper2)
+	require.True(t, ppp.GetPushPullPackOption().HasMoreBit())
+	require.True(t, ppp.GetPushPullPackOption().HasSubscribeBit())
+	require.Equal(t, 3, len(ppp.Operations))
+	require.True(t, ppp.CheckPoint.Compare(model.NewSetCheckPoint(3, 0)))
+	require.Equal(t, int32(2), counter2.Get())
+
+	// the pushed operation is deferred until pulling all
+	_, _ = counter2.IncreaseBy(10)
+	ppp = f.PushPull(wrapper2)
+	require.True(t, ppp.GetPushPullPackOption().HasMoreBit())
+	require.True(t, ppp.CheckPoint.Compare(model.NewSetCheckPoint(6, 0)))
+
+	ppp = f.PushPull(wrapper2)
+	require.False(t, ppp.GetPushPullPackOption().HasMoreBit())
+	require.True(t, ppp.CheckPoint.Compare(model.NewSetCheckPoint(9, 1)))
+	require.Equal(t, int32(17), counter2.Get())
+
+	f.PushPull(wrapper1)
+	require.Equal(t, counter2.Get(), counter1.Get())
+
+	// client3 cannot paginate, so that it pulls all the operations at once
+	client3 := f.NewClient("3")
+	counter3 := client3.SubscribeCounter(t.Name(), nil)
+	wrapper3 := f.Wrap(counter3, model.SupportedCapabilities&^model.CapabilityPagination)
+	ppp = f.PushPull(wrapper3)
+	require.False(t, ppp.GetPushPullPackOption().HasMoreBit())
+	require.Equal(t, 9, len(ppp.Operations))
+	require.Equal(t, counter1.Get(), counter3.Get())
+}
+
+func TestPaginatedPullOfManyPages(t *testing.T) {
+	serverConf := testonly.NewMemoryServerConfig()
+	pullLimit, catchUpGap := uint64(1), uint64(0)
+	serverConf.PullLimit = &pullLimit
+	serverConf.CatchUpGap = &catchUpGap
+	f := testonly.NewServiceFixture(t, serverConf)
+	addr, stop := serveRPC(t, f.Service)
+	defer stop()
+	f.ClientConf.ServerAddr = addr
+
+	client1 := f.NewClient("1")
+	require.NoError(t, client1.Connect())
+	defer func() {
+		require.NoError(t, client1.Close())
+	}()
+	counter1 := client1.CreateCounter(t.Name(), nil)
+	require.NoError(t, client1.Sync())
+
+	client2 := f.NewClient("2")
+	require.NoError(t, client2.Connect())
+	defer func() {
+		require.NoError(t, client2.Close())
+	}()
+	counter2 := client2.SubscribeCounter(t.Name(), nil)
+	require.NoError(t, client2.Sync())
+
+	const numOps = 150 // more than MaxSyncRounds of the client
+	for i := 0; i < numOps; i++ { // synced one by one not to be coalesced
+		_, _ = counter1.Increase()
+		require.NoError(t, client1.Sync())
+	}
+
+	// a sync keeps pulling a page per round until caught up, beyond MaxSyncRounds
+	require.NoError(t, client2.Sync())
+	require.Equal(t, int32(numOps), counter2.Get())
+}
+
+func TestRestoreDatatype(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+
+	client1 := f.NewClient("1")
+	map1 := client1.CreateMap(t.Name(), nil)
+	wrapper1 := f.Wrap(map1, model.SupportedCapabilities)
+	_, _ = map1.Put("a", "x")
+	_, _ = map1.Put("b", "y")
+	ppp := f.PushPull(wrapper1)
+	restoringSseq := ppp.CheckPoint.Sseq
+
+	_, _ = map1.Remove("a")
+	_, _ = map1.Remove("b")
+	_, _ = map1.Put("c", "z")
+	f.PushPull(wrapper1)
+	require.Equal(t, 1, map1.Size())
+
+	_, err := f.Service.RestoreDatatype(gocontext.TODO(), &model.RestoreMessage{Collection: t.Name(), Key: t.Name()})
+	require.Error(t, err)
+	_, err = f.Service.RestoreDatatype(gocontext.TODO(), &model.RestoreMessage{Collection: t.Name(), Key: t.Name(), Sseq: 100})
+	require.Error(t, err)
+
+	res, err := f.Service.RestoreDatatype(gocontext.TODO(), &model.RestoreMessage{
+		Collection: t.Name(),
+		Key:        t.Name(),
+		Sseq:       restoringSseq,
+	})
+	require.NoError(t, err)
+	require.Equal(t, restoringSseq, res.Sseq)
+	require.JSONEq(t, `{"a":"x","b":"y"}`, res.Json)
+
+	// the restoration starts a new era, on whose snapshot the local operation is rebased
+	_, _ = map1.Put("d", "w")
+	ppp = f.PushPull(wrapper1)
+	require.Equal(t, errors.PushPullEraMismatch, getPushPullErrorCode(ppp))
+	require.True(t, wrapper1.NeedRebase())
+	ppp = f.PushPull(wrapper1)
+	require.True(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.False(t, wrapper1.NeedRebase())
+	require.Equal(t, "w", map1.Get("d"))
+	require.Equal(t, "x", map1.Get("a"))
+	require.Equal(t, "y", map1.Get("b"))
+	require.Nil(t, map1.Get("c"))
+
+	client2 := f.NewClient("2")
+	map2 := client2.SubscribeMap(t.Name(), nil)
+	f.PushPull(f.Wrap(map2, model.SupportedCapabilities))
+	require.Equal(t, map1.ToJSON(), map2.ToJSON())
+}
+
+func TestRestoreDatatypeAtTimestamp(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+
+	client1 := f.NewClient("")
+	map1 := client1.CreateMap(t.Name(), nil)
+	wrapper1 := f.Wrap(map1, model.SupportedCapabilities)
+	_, _ = map1.Put("a", "x")
+	ppp := f.PushPull(wrapper1)
+
+	// no snapshot is taken, and the timestamp falls between the operations, which are in different seconds
+	nextSecond := func() int64 {
+		now := time.Now().Unix()
+		for time.Now().Unix() == now {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return time.Now().Unix()
+	}
+	timestamp := nextSecond()
+	nextSecond()
+	_, _ = map1.Put("b", "y")
+	f.PushPull(wrapper1)
+
+	res, err := f.Service.RestoreDatatype(gocontext.TODO(), &model.RestoreMessage{
+		Collection: t.Name(),
+		Key:        t.Name(),
+		Timestamp:  timestamp,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ppp.CheckPoint.Sseq, res.Sseq)
+	require.JSONEq(t, `{"a":"x"}`, res.Json)
+}
+
+func TestRecreateAfterResetCollection(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+
+	client1 := f.NewClient("1")
+	list1 := client1.CreateList(t.Name(), nil)
+	wrapper1 := f.Wrap(list1, model.SupportedCapabilities)
+	_, _ = list1.InsertMany(0, "a", "b")
+	f.PushPull(wrapper1)
+
+	_, err := f.Service.ResetCollection(gocontext.TODO(), &model.CollectionMessage{Collection: t.Name()})
+	require.NoError(t, err)
+	_, _ = list1.InsertMany(2, "c")
+	_, err = f.Service.ProcessPushPull(gocontext.TODO(), wrapper1.CreatePushPullMessage())
+	require.Error(t, err)
+
+	testonly.RegisterClient(t, f.Service, wrapper1.GetClientModel())
+	ppp := f.PushPull(wrapper1)
+	require.True(t, ppp.GetPushPullPackOption().HasErrorBit())
+	require.True(t, wrapper1.NeedRebase())
+	ppp = f.PushPull(wrapper1)
+	require.True(t, ppp.GetPushPullPackOption().HasCreateBit())
+	require.False(t, wrapper1.NeedRebase())
+
+	client2 := f.NewClient("2")
+	list2 := client2.SubscribeList(t.Name(), nil)
+	f.PushPull(f.Wrap(list2, model.SupportedCapabilities))
+	require.Equal(t, list1.ToJSON(), list2.ToJSON())
+	require.Equal(t, 3, list2.Size())
+}
+
+func TestRecoverFromPushPullErrors(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+	errCh := make(chan errors.OrdaError, 10)
+	handlers := orda.NewHandlers(nil, nil, func(dt orda.Datatype, errs ...errors.OrdaError) {
+		for _, err := range errs {
+			errCh <- err
+		}
+	})
+
+	key := t.Name()
+	client1 := f.NewClient("1")
+	counter1 := client1.CreateCounter(key, handlers)
+	wrapper1 := f.Wrap(counter1, model.SupportedCapabilities)
+	_, _ = counter1.Increase()
+	ppp := f.PushPull(wrapper1)
+
+	t.Run("Can resend operations missed by server", func(t *testing.T) {
+		_, _ = counter1.IncreaseBy(2)
+		_, _ = counter1.IncreaseBy(3)
+		// as if the server had lost the last push-pull
+		wrapper1.SetCheckPoint(ppp.CheckPoint.Sseq, ppp.CheckPoint.Cseq+1)
+		res := f.PushPull(wrapper1)
+		require.True(t, res.GetPushPullPackOption().HasErrorBit())
+		require.True(t, wrapper1.GetRetryBackoff() > 0)
+		res = f.PushPull(wrapper1)
+		require.False(t, res.GetPushPullPackOption().HasErrorBit())
+		require.Equal(t, time.Duration(0), wrapper1.GetRetryBackoff())
+
+		client2 := f.NewClient("2")
+		counter2 := client2.SubscribeCounter(key, nil)
+		f.PushPull(f.Wrap(counter2, model.SupportedCapabilities))
+		require.Equal(t, int32(6), counter2.Get())
+	})
+
+	errorPack := func(code errors.ErrorCode) *model.PushPullPack {
+		res := wrapper1.CreatePushPullPack()
+		res.GetPushPullPackOption().SetErrorBit()
+		res.Operations = []*model.Operation{operations.NewErrorOperationWithCodeAndMsg(code, "test").ToModelOperation()}
+		return res
+	}
+
+	t.Run("Can retry push-pulls aborted by server and give up", func(t *testing.T) {
+		var backoff time.Duration
+		for i := 0; ; i++ {
+			require.True(t, i < 100)
+			wrapper1.ApplyPushPullPack(errorPack(errors.PushPullAbortionOfServer))
+			if wrapper1.GetRetryBackoff() == 0 {
+				break
+			}
+			require.True(t, wrapper1.GetRetryBackoff() > backoff)
+			backoff = wrapper1.GetRetryBackoff()
+		}
+		require.Equal(t, errors.DatatypeSync, (<-errCh).GetCode())
+		f.PushPull(wrapper1)
+		require.Equal(t, time.Duration(0), wrapper1.GetRetryBackoff())
+	})
+
+	t.Run("Can surface errors of client abortion and unknown errors", func(t *testing.T) {
+		wrapper1.ApplyPushPullPack(errorPack(errors.PushPullAbortionOfClient))
+		require.Equal(t, errors.DatatypeSync, (<-errCh).GetCode())
+		wrapper1.ApplyPushPullPack(errorPack(errors.ErrorCode(999)))
+		require.Equal(t, errors.DatatypeSync, (<-errCh).GetCode())
+		require.Equal(t, time.Duration(0), wrapper1.GetRetryBackoff())
+		require.False(t, wrapper1.NeedRebase())
+	})
+
+	t.Run("Can respond to a push-pull panicking in server", func(t *testing.T) {
+		req := wrapper1.CreatePushPullMessage()
+		req.PushPullPacks[0].CheckPoint = nil
+		res, err := f.Service.ProcessPushPull(gocontext.TODO(), req)
+		require.NoError(t, err)
+		require.Equal(t, errors.ServerInternal, getPushPullErrorCode(res.GetPushPullPacks()[0]))
+
+		res2 := f.PushPull(wrapper1)
+		require.False(t, res2.GetPushPullPackOption().HasErrorBit())
+	})
+}
+
+func TestRetriedPushPull(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+
+	client1 := f.NewClient("1")
+	counter1 := client1.CreateCounter(t.Name(), nil)
+	wrapper1 := f.Wrap(counter1, model.SupportedCapabilities)
+	f.PushPull(wrapper1)
+
+	client2 := f.NewClient("2")
+	counter2 := client2.SubscribeCounter(t.Name(), nil)
+	wrapper2 := f.Wrap(counter2, model.SupportedCapabilities)
+	f.PushPull(wrapper2)
+	_, _ = counter2.IncreaseBy(10)
+	f.PushPull(wrapper2)
+
+	// the response of the first request is lost, and the request is retried
+	_, _ = counter1.IncreaseBy(3)
+	req := wrapper1.CreatePushPullMessage()
+	res1, err := f.Service.ProcessPushPull(gocontext.TODO(), req)
+	require.NoError(t, err)
+	res2, err := f.Service.ProcessPushPull(gocontext.TODO(), req)
+	require.NoError(t, err)
+	require.Same(t, res1, res2)
+	require.Equal(t, 1, len(res2.GetPushPullPacks()[0].Operations))
+	wrapper1.ApplyPushPullPack(res2.GetPushPullPacks()[0])
+	require.Equal(t, int32(13), counter1.Get())
+
+	datatypeDoc, oErr := f.Managers.Repository.GetDatatypeByKey(f.Ctx, f.CollectionNum, t.Name())
+	require.NoError(t, oErr)
+	require.Equal(t, uint64(3), datatypeDoc.Sseq.End)
+	f.PushPull(wrapper2)
+	require.Equal(t, counter1.Get(), counter2.Get())
+
+	// a client restarted with the same CUID sends the same seq with another nonce
+	pull := wrapper2.CreatePushPullMessage()
+	pull.Header.Nonce = "before-restart"
+	res1, err = f.Service.ProcessPushPull(gocontext.TODO(), pull)
+	require.NoError(t, err)
+	_, _ = counter1.Increase()
+	f.PushPull(wrapper1)
+	restarted := proto.Clone(pull).(*model.PushPullMessage)
+	restarted.Header.Nonce = "after-restart"
+	res2, err = f.Service.ProcessPushPull(gocontext.TODO(), restarted)
+	require.NoError(t, err)
+	require.NotSame(t, res1, res2)
+	require.Equal(t, 1, len(res2.GetPushPullPacks()[0].Operations))
+}
+
+func TestBinaryEncoding(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+	pushPull := func(w *wrapper.DatatypeWrapper, inBinary bool) *model.PushPullPack {
+		req := w.CreatePushPullMessage()
+		for _, ppp := range req.PushPullPacks {
+			for i, op := range ppp.Operations {
+				ppp.Operations[i] = operations.EncodeBody(op, inBinary)
+			}
+		}
+		return f.Send(w, req)
+	}
+
+	client1 := f.NewClient("1")
+	map1 := client1.CreateMap(t.Name(), nil)
+	wrapper1 := f.Wrap(map1, model.SupportedCapabilities)
+	_, _ = map1.Put("k1", "v1")
+	_, _ = map1.Put("k2", map[string]interface{}{"a": 1.5})
+	pushPull(wrapper1, true)
+
+	client2 := f.NewClient("2")
+	map2 := client2.SubscribeMap(t.Name(), nil)
+	wrapper2 := f.Wrap(map2, model.SupportedCapabilities&^model.CapabilityBinaryEncoding)
+	for _, op := range pushPull(wrapper2, false).Operations {
+		require.False(t, operations.IsBinaryBody(op.Body), op.String())
+	}
+	require.Equal(t, map1.ToJSON(), map2.ToJSON())
+
+	client3 := f.NewClient("3")
+	map3 := client3.SubscribeMap(t.Name(), nil)
+	wrapper3 := f.Wrap(map3, model.SupportedCapabilities)
+	ppp3 := pushPull(wrapper3, true)
+	require.True(t, operations.IsBinaryBody(ppp3.Operations[len(ppp3.Operations)-1].Body))
+	require.Equal(t, map1.ToJSON(), map3.ToJSON())
+
+	putOp := operations.NewPutOperation("k", []interface{}{1, "v"})
+	putOp.SetID(model.NewOperationID())
+	for _, inBinary := range []bool{false, true} {
+		in := &model.EncodingMessage{
+			Type: model.TypeOfDatatype_MAP,
+			Op:   operations.EncodeBody(putOp.ToModelOperation(), inBinary),
+		}
+		out, err := f.Service.TestEncodingOperation(gocontext.TODO(), in)
+		require.NoError(t, err)
+		require.Equal(t, inBinary, operations.IsBinaryBody(out.Op.Body))
+		require.JSONEq(t, string(putOp.ToModelOperation().Body), string(operations.EncodeBody(out.Op, false).Body))
+	}
+}
+
+func TestChunkedSnapshot(t *testing.T) {
+	serverConf := testonly.NewMemoryServerConfig()
+	catchUpGap := uint64(2)
+	serverConf.CatchUpGap = &catchUpGap
+	serverConf.ChunkSize = 32
+	f := testonly.NewServiceFixture(t, serverConf)
+
+	client1 := f.NewClient("1")
+	list1 := client1.CreateList(t.Name(), nil)
+	wrapper1 := f.Wrap(list1, model.SupportedCapabilities)
+	for i := 0; i < 5; i++ {
+		_, _ = list1.InsertMany(0, fmt.Sprintf("value-%d", i), i, true)
+		f.PushPull(wrapper1)
+	}
+	var snapshotDoc *schema.SnapshotDoc
+	require.Eventually(t, func() bool {
+		var oErr errors.OrdaError
+		snapshotDoc, oErr = f.Managers.Repository.GetLatestSnapshot(f.Ctx, f.CollectionNum, wrapper1.GetDUID())
+		return oErr == nil && snapshotDoc != nil && snapshotDoc.Sseq == 6
+	}, time.Second, 10*time.Millisecond)
+	total := uint64(len(snapshotDoc.Snapshot))
+	require.Greater(t, total, 2*serverConf.ChunkSize)
+
+	var progress []uint64
+	handlers := orda.NewHandlers(nil, nil, nil)
+	handlers.SetSnapshotProgressHandler(func(dt orda.Datatype, received uint64, tot uint64) {
+		require.Equal(t, total, tot)
+		progress = append(progress, received)
+	})
+	client2 := f.NewClient("2")
+	list2 := client2.SubscribeList(t.Name(), handlers)
+	wrapper2 := f.Wrap(list2, model.SupportedCapabilities)
+
+	ppp := f.PushPull(wrapper2)
+	require.True(t, ppp.GetPushPullPackOption().HasSubscribeBit())
+	require.True(t, ppp.GetPushPullPackOption().HasMoreBit())
+	require.Equal(t, uint64(0), ppp.GetSnapshotChunk().GetOffset())
+	require.Equal(t, model.StateOfDatatype_DUE_TO_SUBSCRIBE, list2.GetState())
+
+	// the snapshot being pulled is not changed by the operations pushed in the meantime
+	_, _ = list1.Insert(0, "after")
+	f.PushPull(wrapper1)
+	for ppp.GetPushPullPackOption().HasMoreBit() {
+		require.Empty(t, ppp.Operations)
+		ppp = f.PushPull(wrapper2)
+	}
+	// the last chunk is assembled into the SnapshotOperation followed by the operations after the snapshot
+	require.Nil(t, ppp.GetSnapshotChunk())
+	require.Len(t, ppp.Operations, 2)
+	require.Equal(t, model.TypeOfOperation_LIST_SNAPSHOT, ppp.Operations[0].OpType)
+	require.Equal(t, model.StateOfDatatype_SUBSCRIBED, list2.GetState())
+	require.Equal(t, int((total+serverConf.ChunkSize-1)/serverConf.ChunkSize), len(progress))
+	require.Equal(t, total, progress[len(progress)-1])
+	require.Equal(t, uint64(7), ppp.CheckPoint.Sseq)
+	require.Equal(t, list1.ToJSON(), list2.ToJSON())
+
+	// the client not capable of chunks pulls the snapshot at once
+	client3 := f.NewClient("3")
+	list3 := client3.SubscribeList(t.Name(), nil)
+	ppp = f.PushPull(f.Wrap(list3, model.SupportedCapabilities&^model.CapabilityChunkedSnapshot))
+	require.Nil(t, ppp.GetSnapshotChunk())
+	require.False(t, ppp.GetPushPullPackOption().HasMoreBit())
+	require.Equal(t, list1.ToJSON(), list3.ToJSON())
+}
+
+func TestDocumentPathSubscription(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+
+	client1 := f.NewClient("1")
+	doc1 := client1.CreateDocument(t.Name(), nil)
+	wrapper1 := f.Wrap(doc1, model.SupportedCapabilities)
+	_, _ = doc1.PutToObject("a", map[string]interface{}{"x": 1})
+	_, _ = doc1.PutToObject("b", 2)
+	f.PushPull(wrapper1)
+
+	client2 := f.NewClient("2")
+	doc2 := client2.SubscribeDocumentPath(t.Name(), "/a", nil)
+	wrapper2 := f.Wrap(doc2, model.SupportedCapabilities)
+	ppp := f.PushPull(wrapper2)
+	require.True(t, ppp.GetPushPullPackOption().HasSnapshotBit())
+	require.Equal(t, model.StateOfDatatype_SUBSCRIBED, doc2.GetState())
+	require.Equal(t, `{"a":{"x":1}}`, string(doc2.ToJSONBytes()))
+
+	// only the operations affecting the path are pulled
+	_, _ = doc1.PutToObject("b", 3)
+	a1, err := doc1.GetFromObject("a")
+	require.NoError(t, err)
+	_, _ = a1.PutToObject("y", 4)
+	f.PushPull(wrapper1)
+	ppp = f.PushPull(wrapper2)
+	require.Len(t, ppp.Operations, 1)
+	require.Equal(t, model.TypeOfOperation_DOC_OBJ_PUT, ppp.Operations[0].OpType)
+	require.Equal(t, `{"a":{"x":1,"y":4}}`, string(doc2.ToJSONBytes()))
+
+	// the local writes are restricted to the path
+	_, err = doc2.PutToObject("c", 5)
+	require.Error(t, err)
+	require.Equal(t, errors.DatatypeOutOfPath, err.(errors.OrdaError).GetCode())
+	a2, err := doc2.GetByPath("/a")
+	require.NoError(t, err)
+	_, err = a2.PutToObject("z", 6)
+	require.NoError(t, err)
+	f.PushPull(wrapper2)
+	f.PushPull(wrapper1)
+	require.Equal(t, `{"a":{"x":1,"y":4,"z":6},"b":3}`, string(doc1.ToJSONBytes()))
+}
+
+func testOrdaService(t *testing.T, ctx iface.OrdaContext, svc *service.OrdaService) {
 	collectionName := t.Name()
 
 	conf := &orda.ClientConfig{
@@ -43,6 +657,27 @@ func TestOrdaService(t *testing.T) {
 		require.Nil(t, res)
 	})
 
+	t.Run("Can negotiate protocol", func(t *testing.T) {
+		cm := &model.Client{
+			CUID:       types.NewUID(),
+			Alias:      "negotiate",
+			Collection: collectionName,
+		}
+		req := model.NewClientMessage(cm)
+		req.Header.Version = "v0"
+		_, err := svc.ProcessClient(ctx, req)
+		require.Error(t, err)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Contains(t, err.Error(), "'v0'")
+
+		req = model.NewClientMessage(cm)
+		req.Capabilities = uint32(model.CapabilityDedup | model.CapabilityStreaming)
+		res, err := svc.ProcessClient(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, model.ProtocolVersion, res.GetHeader().GetVersion())
+		require.Equal(t, model.CapabilityDedup, model.Capability(res.GetCapabilities()))
+	})
+
 	t.Run("Can do ", func(t *testing.T) {
 
 		client1 := orda.NewClient(conf, t.Name()+"1")
@@ -78,12 +713,301 @@ func TestOrdaService(t *testing.T) {
 		_, _ = counter2.Increase()
 		_, _ = counter2.Increase()
 
+		// the increases are coalesced into one operation
 		res, err = svc.ProcessPushPull(gocontext.TODO(), wrapper2.CreatePushPullMessage())
 		require.NoError(t, err)
 		ppp3 := res.GetPushPullPacks()[0]
 		log.Logger.Infof("%v", ppp3.ToString(false))
-		require.True(t, ppp3.CheckPoint.Compare(model.NewSetCheckPoint(4, 2)))
+		require.True(t, ppp3.CheckPoint.Compare(model.NewSetCheckPoint(3, 1)))
 		require.Equal(t, len(ppp3.Operations), 0)
 		require.Equal(t, ppp3.GetOption(), uint32(model.PushPullBitNormal))
 	})
+
+	t.Run("Can serialize concurrent push-pulls", func(t *testing.T) {
+		const numClients = 5
+		var wrappers []*wrapper.DatatypeWrapper
+		for i := 0; i < numClients; i++ {
+			client := orda.NewClient(conf, fmt.Sprintf("%s%d", t.Name(), i))
+			counter := client.SubscribeOrCreateCounter(t.Name(), nil)
+			w := wrapper.NewDatatypeWrapper(counter)
+			testonly.RegisterClient(t, svc, w.GetClientModel())
+			res, err := svc.ProcessPushPull(gocontext.TODO(), w.CreatePushPullMessage())
+			require.NoError(t, err)
+			w.ApplyPushPullPack(res.GetPushPullPacks()[0])
+			_, _ = counter.Increase()
+			_, _ = counter.IncreaseBy(2)
+			wrappers = append(wrappers, w)
+		}
+
+		wg := sync.WaitGroup{}
+		wg.Add(numClients)
+		for _, w := range wrappers {
+			go func(w *wrapper.DatatypeWrapper) {
+				defer wg.Done()
+				res, err := svc.ProcessPushPull(gocontext.TODO(), w.CreatePushPullMessage())
+				require.NoError(t, err)
+				require.False(t, res.GetPushPullPacks()[0].GetPushPullPackOption().HasErrorBit())
+			}(w)
+		}
+		wg.Wait()
+
+		reader := orda.NewClient(conf, t.Name()+"reader")
+		counter := reader.SubscribeCounter(t.Name(), nil)
+		w := wrapper.NewDatatypeWrapper(counter)
+		testonly.RegisterClient(t, svc, w.GetClientModel())
+		res, err := svc.ProcessPushPull(gocontext.TODO(), w.CreatePushPullMessage())
+		require.NoError(t, err)
+		ppp := res.GetPushPullPacks()[0]
+		// the snapshot operation of the creation and the increase operations coalesced of all the clients
+		require.Equal(t, uint64(numClients+1), ppp.CheckPoint.Sseq)
+		require.Equal(t, numClients+1, len(ppp.Operations))
+		w.ApplyPushPullPack(ppp)
+		require.Equal(t, int32(numClients*3), counter.Get())
+	})
+}
+
+func TestCausalStability(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+
+	client1 := f.NewClient("1")
+	map1 := client1.CreateMap(t.Name(), nil)
+	wrapper1 := f.Wrap(map1, model.SupportedCapabilities)
+	_, _ = map1.Put("a", 1)
+	_, _ = map1.Put("b", 2)
+	require.Nil(t, f.PushPull(wrapper1).Stable)
+
+	client2 := f.NewClient("2")
+	map2 := client2.SubscribeMap(t.Name(), nil)
+	wrapper2 := f.Wrap(map2, model.SupportedCapabilities)
+	require.Nil(t, f.PushPull(wrapper2).Stable)
+
+	_, _ = map1.Remove("a")
+	ppp := f.PushPull(wrapper1)
+	removed := ppp.CheckPoint.Sseq
+	// client2 has not reported its bounds since subscribing
+	require.Nil(t, ppp.Stable)
+
+	// client1 might not have seen the operations of client2 before the removal
+	ppp = f.PushPull(wrapper2)
+	require.NotNil(t, ppp.Stable)
+	require.Nil(t, map2.Get("a"))
+	ops, _, oErr := f.Managers.Repository.GetOperations(f.Ctx, wrapper1.GetDUID(), removed, removed)
+	require.NoError(t, oErr)
+	require.Len(t, ops, 1)
+	require.LessOrEqual(t, ppp.Stable.Lamport, ops[0].ID.Lamport)
+
+	// after both clients have seen the removal, the tombstone becomes stable
+	f.PushPull(wrapper1)
+	ppp = f.PushPull(wrapper2)
+	require.NotNil(t, ppp.Stable)
+	require.Greater(t, ppp.Stable.Lamport, ops[0].ID.Lamport)
+
+	// the key purged can be put again
+	_, _ = map1.Put("a", 3)
+	f.PushPull(wrapper1)
+	_, _ = map2.Put("d", 4)
+	ppp = f.PushPull(wrapper2)
+	require.NotNil(t, ppp.Stable)
+	f.PushPull(wrapper1)
+	require.Equal(t, float64(3), map2.Get("a"))
+	require.Equal(t, map1.ToJSON(), map2.ToJSON())
+
+	// a new subscriber resets the stability
+	client3 := f.NewClient("3")
+	map3 := client3.SubscribeMap(t.Name(), nil)
+	wrapper3 := f.Wrap(map3, model.SupportedCapabilities)
+	require.Nil(t, f.PushPull(wrapper3).Stable)
+	require.Nil(t, f.PushPull(wrapper1).Stable)
+	require.Equal(t, map1.ToJSON(), map3.ToJSON())
+}
+
+func TestLimitUnpushedOperations(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+	f.ClientConf.MaxUnpushedOperations = 3
+	client1 := f.NewClient("1")
+	map1 := client1.CreateMap(t.Name(), nil)
+	wrapper1 := f.Wrap(map1, model.SupportedCapabilities)
+
+	// the snapshot operation of the creation is not pushed yet
+	_, err := map1.Put("a", 1)
+	require.NoError(t, err)
+	_, err = map1.Put("b", 2)
+	require.NoError(t, err)
+	_, err = map1.Put("c", 3)
+	require.Error(t, err)
+	require.Equal(t, errors.DatatypeTooManyUnpushed, err.(errors.OrdaError).GetCode())
+	require.Nil(t, map1.Get("c"))
+
+	require.Equal(t, uint64(3), f.PushPull(wrapper1).CheckPoint.Cseq)
+
+	// the acknowledged operations are no longer pushed nor counted
+	for _, key := range []string{"c", "d", "e"} {
+		_, err = map1.Put(key, 4)
+		require.NoError(t, err)
+	}
+	_, err = map1.Put("f", 5)
+	require.Error(t, err)
+	req := wrapper1.CreatePushPullMessage()
+	require.Len(t, req.PushPullPacks[0].Operations, 3)
+	require.Equal(t, uint64(4), req.PushPullPacks[0].Operations[0].ID.Seq)
+	f.Send(wrapper1, req)
+
+	// the redundant operations are coalesced when reaching the limit
+	_, _ = map1.Put("c", 6)
+	_, _ = map1.Put("c", 7)
+	_, _ = map1.Put("d", 8)
+	_, err = map1.Put("f", 9)
+	require.NoError(t, err)
+	ppp := wrapper1.CreatePushPullPack()
+	require.Len(t, ppp.Operations, 3)
+	require.Equal(t, uint64(7), ppp.Operations[0].ID.Seq)
+	require.Equal(t, uint64(9), ppp.Operations[2].ID.Seq)
+}
+
+func TestCoalescePushedOperations(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+	// returns the pushed PushPullPack
+	pushPull := func(w *wrapper.DatatypeWrapper) *model.PushPullPack {
+		req := w.CreatePushPullMessage()
+		f.Send(w, req)
+		return req.PushPullPacks[0]
+	}
+	client1 := f.NewClient("1")
+	client2 := f.NewClient("2")
+
+	counter1 := client1.CreateCounter(t.Name()+"Counter", nil)
+	counterWrapper1 := f.Wrap(counter1, model.SupportedCapabilities)
+	for i := 0; i < 10; i++ {
+		_, _ = counter1.IncreaseBy(2)
+	}
+	// the snapshot operation of the creation and the sum of the increases
+	require.Len(t, pushPull(counterWrapper1).Operations, 2)
+	_, _ = counter1.Increase()
+	_, _ = counter1.Increase()
+	ppp := pushPull(counterWrapper1)
+	require.Len(t, ppp.Operations, 1)
+	require.Equal(t, uint64(3), ppp.Operations[0].ID.Seq)
+
+	counter2 := client2.SubscribeCounter(t.Name()+"Counter", nil)
+	counterWrapper2 := f.Wrap(counter2, model.SupportedCapabilities)
+	pushPull(counterWrapper2)
+	require.Equal(t, int32(22), counter2.Get())
+
+	map1 := client1.CreateMap(t.Name()+"Map", nil)
+	mapWrapper1 := wrapper.NewDatatypeWrapper(map1)
+	for i := 0; i < 10; i++ {
+		_, _ = map1.Put("a", i)
+		_, _ = map1.Put("b", i)
+	}
+	_, _ = map1.Remove("b")
+	require.Len(t, pushPull(mapWrapper1).Operations, 4)
+
+	map2 := client2.SubscribeMap(t.Name()+"Map", nil)
+	mapWrapper2 := wrapper.NewDatatypeWrapper(map2)
+	pushPull(mapWrapper2)
+	require.Equal(t, map1.ToJSON(), map2.ToJSON())
+
+	list1 := client1.CreateList(t.Name()+"List", nil)
+	listWrapper1 := wrapper.NewDatatypeWrapper(list1)
+	_, _ = list1.InsertMany(0, "a", "b")
+	for i := 0; i < 10; i++ {
+		_, _ = list1.Insert(1, i)
+		_, _ = list1.Delete(1)
+	}
+	require.Len(t, pushPull(listWrapper1).Operations, 2)
+
+	list2 := client2.SubscribeList(t.Name()+"List", nil)
+	listWrapper2 := wrapper.NewDatatypeWrapper(list2)
+	pushPull(listWrapper2)
+	_, _ = list2.Insert(1, "x")
+	_, _ = list1.Insert(1, "y")
+	_, _ = list1.Delete(1)
+	pushPull(listWrapper2)
+	pushPull(listWrapper1)
+	pushPull(listWrapper2)
+	require.Equal(t, list1.ToJSON(), list2.ToJSON())
+}
+
+// getPushPullErrorCode returns the code of the ErrorOperation in the PushPullPack, or 0 if it has no error.
+func getPushPullErrorCode(ppp *model.PushPullPack) errors.ErrorCode {
+	if !ppp.GetPushPullPackOption().HasErrorBit() {
+		return 0
+	}
+	errOp := operations.ModelToOperation(ppp.Operations[0]).(*operations.ErrorOperation)
+	return errOp.GetPushPullError().Code
+}
+
+// serveRPC serves the OrdaService at a random port, and returns its address and the function to stop it.
+func serveRPC(t *testing.T, svc *service.OrdaService) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	rpcServer := grpc.NewServer()
+	model.RegisterOrdaServiceServer(rpcServer, svc)
+	go func() {
+		_ = rpcServer.Serve(lis)
+	}()
+	return lis.Addr().String(), rpcServer.Stop
+}
+
+func TestFlushAndWaitForSync(t *testing.T) {
+	f := testonly.NewServiceFixture(t, nil)
+	addr, stop := serveRPC(t, f.Service)
+	defer stop()
+	f.ClientConf.ServerAddr = addr
+
+	client1 := f.NewClient("1")
+	require.NoError(t, client1.Connect())
+	defer func() {
+		require.NoError(t, client1.Close())
+	}()
+	counter1 := client1.CreateCounter(t.Name()+"Counter", nil)
+	map1 := client1.CreateMap(t.Name()+"Map", nil)
+	_, _ = counter1.IncreaseBy(3)
+	_, _ = map1.Put("a", 1)
+
+	// without syncing in the MANUALLY mode, the operations are never acknowledged
+	timeoutCtx, cancel := gocontext.WithTimeout(gocontext.TODO(), 100*time.Millisecond)
+	defer cancel()
+	err := counter1.Flush(timeoutCtx)
+	require.Error(t, err)
+	require.Equal(t, errors.DatatypeFlush, err.GetCode())
+
+	// a Flush returns when the operations issued before are acknowledged by the sync of another goroutine
+	flushed := make(chan errors.OrdaError)
+	go func() {
+		flushed <- counter1.Flush(gocontext.TODO())
+	}()
+	require.NoError(t, client1.Sync())
+	require.NoError(t, <-flushed)
+	// the operations coalesced after the call are acknowledged as well
+	for i := 0; i < 5; i++ {
+		_, _ = counter1.Increase()
+	}
+	go func() {
+		flushed <- counter1.Flush(gocontext.TODO())
+	}()
+	_, _ = counter1.Increase()
+	require.NoError(t, client1.Sync())
+	require.NoError(t, <-flushed)
+
+	_, _ = counter1.Increase()
+	_, _ = map1.Put("b", 2)
+	require.NoError(t, client1.WaitForSync(gocontext.TODO()))
+	require.NoError(t, counter1.Flush(gocontext.TODO()))
+	require.NoError(t, map1.Flush(gocontext.TODO()))
+
+	client2 := f.NewClient("2")
+	require.NoError(t, client2.Connect())
+	defer func() {
+		require.NoError(t, client2.Close())
+	}()
+	counter2 := client2.SubscribeCounter(t.Name()+"Counter", nil)
+	map2 := client2.SubscribeMap(t.Name()+"Map", nil)
+	require.NoError(t, client2.WaitForSync(gocontext.TODO()))
+	require.Equal(t, counter1.Get(), counter2.Get())
+	require.Equal(t, int32(10), counter2.Get())
+	require.Equal(t, map1.ToJSON(), map2.ToJSON())
+
+	require.NoError(t, client2.Close())
+	require.Error(t, client2.WaitForSync(gocontext.TODO()))
 }
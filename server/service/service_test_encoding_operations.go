@@ -19,7 +19,8 @@ func (its *OrdaService) decodeModelOp(in *model.Operation) iface.Operation {
 	return op
 }
 
-// TestEncodingOperation is used for testing encoded operations; this is necessary to develop SDKs
+// TestEncodingOperation is used for testing encoded operations; this is necessary to develop SDKs.
+// The operation is returned in the same encoding of the body, either JSON or binary.
 func (its *OrdaService) TestEncodingOperation(
 	goCtx gocontext.Context,
 	in *model.EncodingMessage,
@@ -28,6 +29,7 @@ func (its *OrdaService) TestEncodingOperation(
 	defer func() {
 		log.Logger.Infof("Returns %v, %v", ret, er)
 	}()
+	inBinary := operations.IsBinaryBody(in.Op.GetBody())
 	decodedOp := its.decodeModelOp(in.Op)
 	switch cast := decodedOp.(type) {
 	case *operations.SnapshotOperation:
@@ -108,6 +110,7 @@ func (its *OrdaService) TestEncodingOperation(
 		in.Op = operations.NewDeleteOperation(1, 10).ToModelOperation()
 	}
 	in.Op.ID = decodedOp.GetID()
+	in.Op = operations.EncodeBody(in.Op, inBinary)
 	return in, nil
 }
 
@@ -126,10 +129,11 @@ func (its *OrdaService) testEncodingSnapshotOperation(
 		return nil, err
 	}
 
-	regenOp, err := datatype.CreateSnapshotOperation()
+	snap, err := datatype.MarshalSnapshot(operations.IsBinaryBody(sOp.GetBody()))
 	if err != nil {
 		return nil, err
 	}
+	regenOp := operations.NewSnapshotOperation(typeOf, snap)
 	regenOp.SetID(sOp.ID)
 	return &model.EncodingMessage{Type: typeOf, Op: regenOp.ToModelOperation()}, nil
 }
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"github.com/orda-io/orda/client/pkg/errors"
 	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/operations"
 	"github.com/orda-io/orda/client/pkg/orda"
 
 	"github.com/orda-io/orda/server/constants"
 	"github.com/orda-io/orda/server/managers"
 	"github.com/orda-io/orda/server/schema"
+	"time"
 )
 
 // Manager is a struct that updates snapshot of a datatype in Orda server
@@ -34,51 +37,142 @@ func NewManager(
 	}
 }
 
-// GetLatestDatatype returns the datatype from database
-func (its *Manager) GetLatestDatatype() (iface.Datatype, uint64, errors.OrdaError) {
-	var lastSseq uint64 = 0
+func (its *Manager) newDatatype() iface.Datatype {
 	client := orda.NewClient(orda.NewLocalClientConfig(its.collectionDoc.Name), "orda-server")
 	datatype := client.CreateDatatype(its.datatypeDoc.Key, its.datatypeDoc.GetType(), nil).(iface.Datatype)
 	datatype.SetLogger(its.ctx.L())
+	return datatype
+}
+
+// EncodeInJSON returns the snapshot of a datatype encoded in JSON for the clients which cannot read it in binary.
+func EncodeInJSON(ctx iface.OrdaContext, typeOf model.TypeOfDatatype, snap []byte) ([]byte, errors.OrdaError) {
+	if !operations.IsBinaryBody(snap) {
+		return snap, nil
+	}
+	client := orda.NewClient(orda.NewLocalClientConfig("encoding"), "orda-server")
+	datatype := client.CreateDatatype("encoding", typeOf, nil).(iface.Datatype)
+	datatype.SetLogger(ctx.L())
+	if _, err := datatype.ExecuteRemote(operations.NewSnapshotOperation(typeOf, snap)); err != nil {
+		return nil, err
+	}
+	return datatype.MarshalSnapshot(false)
+}
+
+func applySnapshot(datatype iface.Datatype, snapshotDoc *schema.SnapshotDoc) errors.OrdaError {
+	if err := datatype.SetMetaAndSnapshot([]byte(snapshotDoc.Meta), snapshotDoc.Snapshot); err != nil {
+		return err
+	}
+	datatype.ResetWired()
+	return nil
+}
+
+// GetLatestDatatype returns the datatype from database
+func (its *Manager) GetLatestDatatype() (iface.Datatype, uint64, errors.OrdaError) {
+	var lastSseq uint64 = 0
+	datatype := its.newDatatype()
 	if its.datatypeDoc.DUID == "" {
 		return datatype, lastSseq, nil
 	}
 
 	datatype.SetDUID(its.datatypeDoc.DUID)
 
-	snapshotDoc, err := its.managers.Mongo.GetLatestSnapshot(its.ctx, its.datatypeDoc.CollectionNum, its.datatypeDoc.DUID)
+	snapshotDoc, err := its.managers.Repository.GetLatestSnapshot(its.ctx, its.datatypeDoc.CollectionNum, its.datatypeDoc.DUID)
 	if err != nil {
 		return nil, 0, err
 	}
 	if snapshotDoc != nil {
 		lastSseq = snapshotDoc.Sseq
-		if err = datatype.SetMetaAndSnapshot([]byte(snapshotDoc.Meta), snapshotDoc.Snapshot); err != nil {
+		if err = applySnapshot(datatype, snapshotDoc); err != nil {
 			return nil, 0, err
 		}
-		datatype.ResetWired()
 	}
-	opList, sseqList, err := its.managers.Mongo.GetOperations(its.ctx, its.datatypeDoc.DUID, lastSseq+1, constants.InfinitySseq)
+	opList, sseqList, err := its.managers.Repository.GetOperations(its.ctx, its.datatypeDoc.DUID, lastSseq+1, constants.InfinitySseq)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	if len(sseqList) <= 0 {
+	if len(sseqList) > 0 {
+		its.ctx.L().Infof("apply %d operations: %+v", len(opList), opList.ToString(false))
+		if _, err = datatype.ReceiveRemoteModelOperations(opList, false); err != nil {
+			// TODO: should fix corruption
+			return nil, 0, err
+		}
+		lastSseq = sseqList[len(sseqList)-1]
+	}
+	// the operations made on the datatype belong to the current era
+	datatype.SetEra(its.datatypeDoc.Era)
+	return datatype, lastSseq, nil
+}
+
+// GetDatatypeAt returns the datatype as of the specified sseq. If sseq is 0, it returns the datatype as of the last
+// operation created at or before the specified time; if the operations have been compacted, it returns the datatype
+// of the latest snapshot taken at or before the time.
+func (its *Manager) GetDatatypeAt(sseq uint64, at time.Time) (iface.Datatype, uint64, errors.OrdaError) {
+	datatype := its.newDatatype()
+	datatype.SetDUID(its.datatypeDoc.DUID)
+
+	if sseq == 0 {
+		var err errors.OrdaError
+		if sseq, err = its.managers.Repository.GetLastSseqAt(its.ctx, its.datatypeDoc.DUID, at); err != nil {
+			return nil, 0, err
+		}
+	}
+	snapshotDocs, err := its.managers.Repository.GetSnapshots(its.ctx, its.datatypeDoc.CollectionNum, its.datatypeDoc.DUID)
+	if err != nil {
+		return nil, 0, err
+	}
+	var lastSseq uint64 = 0
+	var baseDoc *schema.SnapshotDoc
+	for _, snapshotDoc := range snapshotDocs {
+		if (sseq > 0 && snapshotDoc.Sseq <= sseq) || (sseq == 0 && !snapshotDoc.CreatedAt.After(at)) {
+			baseDoc = snapshotDoc
+		}
+	}
+	if baseDoc != nil {
+		lastSseq = baseDoc.Sseq
+		if err = applySnapshot(datatype, baseDoc); err != nil {
+			return nil, 0, err
+		}
+	} else if sseq == 0 {
+		return nil, 0, errors.ServerNoResource.New(its.ctx.L(), fmt.Sprintf("snapshot at %v", at))
+	}
+	if sseq <= lastSseq {
 		return datatype, lastSseq, nil
 	}
 
-	its.ctx.L().Infof("apply %d operations: %+v", len(opList), opList.ToString(false))
+	opList, sseqList, err := its.managers.Repository.GetOperations(its.ctx, its.datatypeDoc.DUID, lastSseq+1, sseq)
+	if err != nil {
+		return nil, 0, err
+	}
+	if uint64(len(sseqList)) != sseq-lastSseq {
+		return nil, 0, errors.ServerNoResource.New(its.ctx.L(), fmt.Sprintf("operations from sseq %d to %d", lastSseq+1, sseq))
+	}
 	if _, err = datatype.ReceiveRemoteModelOperations(opList, false); err != nil {
-		// TODO: should fix corruption
 		return nil, 0, err
 	}
-	lastSseq = sseqList[len(sseqList)-1]
-	return datatype, lastSseq, nil
+	return datatype, sseq, nil
 }
 
 func (its *Manager) getLockKey() string {
 	return fmt.Sprintf("US:%d:%s", its.collectionDoc.Num, its.datatypeDoc.Key)
 }
 
+// purgeTombstones purges the tombstones of the datatype under the latest causal stability, if the datatype contains
+// all the operations until the sseq of the stability.
+func (its *Manager) purgeTombstones(datatype iface.Datatype, lastSseq uint64) errors.OrdaError {
+	datatypeDoc, err := its.managers.Repository.GetDatatype(its.ctx, its.datatypeDoc.DUID)
+	if err != nil {
+		return err
+	}
+	if datatypeDoc == nil || datatypeDoc.Stability == nil || datatypeDoc.Stability.Sseq > lastSseq {
+		return nil
+	}
+	if purged := datatype.PurgeTombstones(datatypeDoc.Stability.GetStable()); purged > 0 {
+		its.ctx.L().Infof("purge %d tombstones of '%v' before %s", purged, its.datatypeDoc.Key, datatypeDoc.Stability.GetStable().ToString())
+	}
+	return nil
+}
+
 // UpdateSnapshot updates snapshot for specified datatype
 func (its *Manager) UpdateSnapshot() errors.OrdaError {
 	lock := its.managers.GetLock(its.ctx, its.getLockKey())
@@ -91,19 +185,22 @@ func (its *Manager) UpdateSnapshot() errors.OrdaError {
 	if err != nil {
 		return err
 	}
+	if err := its.purgeTombstones(datatype, lastSseq); err != nil {
+		return err
+	}
 
 	meta, snap, err := datatype.GetMetaAndSnapshot()
 	if err != nil {
 		return err
 	}
 
-	if err := its.managers.Mongo.InsertSnapshot(its.ctx, its.collectionDoc.Num, its.datatypeDoc.DUID, lastSseq, meta, snap); err != nil {
+	if err := its.managers.Repository.InsertSnapshot(its.ctx, its.collectionDoc.Num, its.datatypeDoc.DUID, lastSseq, meta, snap); err != nil {
 		return err
 	}
 
 	data := datatype.ToJSON()
 
-	if err := its.managers.Mongo.InsertRealSnapshot(its.ctx, its.collectionDoc.Name, its.datatypeDoc.Key, data, lastSseq); err != nil {
+	if err := its.managers.Repository.InsertRealSnapshot(its.ctx, its.collectionDoc.Name, its.datatypeDoc.Key, data, lastSseq); err != nil {
 		return err
 	}
 	its.ctx.L().Infof("FINISH UPD_SNAP: '%v': %d", its.datatypeDoc.Key, lastSseq)
@@ -0,0 +1,72 @@
+package snapshot
+
+import (
+	"reflect"
+
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/orda"
+)
+
+// Converge makes local operations on the datatype so that it has the same state with the target.
+// When the operations are pushed, the subscribed clients also converge to the state of the target.
+func Converge(datatype iface.Datatype, target iface.Datatype) errors.OrdaError {
+	switch cast := datatype.(type) {
+	case orda.Counter:
+		return convergeCounter(cast, target.(orda.Counter))
+	case orda.Map:
+		return convergeMap(cast, target.(orda.Map))
+	case orda.List:
+		return convergeList(cast, target.(orda.List))
+	case orda.Document:
+		_, err := cast.PatchByJSON(string(target.(orda.Document).ToJSONBytes()))
+		return err
+	}
+	return errors.ServerBadRequest.New(datatype.L(), "not restorable type: "+datatype.GetType().String())
+}
+
+func convergeCounter(counter orda.Counter, target orda.Counter) errors.OrdaError {
+	if delta := target.Get() - counter.Get(); delta != 0 {
+		_, err := counter.IncreaseBy(delta)
+		return err
+	}
+	return nil
+}
+
+func convergeMap(hashMap orda.Map, target orda.Map) errors.OrdaError {
+	current := hashMap.ToJSON().(map[string]interface{})
+	restored := target.ToJSON().(map[string]interface{})
+	for key := range current {
+		if _, ok := restored[key]; !ok {
+			if _, err := hashMap.Remove(key); err != nil {
+				return err
+			}
+		}
+	}
+	for key, value := range restored {
+		if old, ok := current[key]; ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		if _, err := hashMap.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convergeList(list orda.List, target orda.List) errors.OrdaError {
+	if list.Size() > 0 {
+		if _, err := list.DeleteMany(0, list.Size()); err != nil {
+			return err
+		}
+	}
+	if target.Size() == 0 {
+		return nil
+	}
+	values, err := target.GetMany(0, target.Size())
+	if err != nil {
+		return err
+	}
+	_, err = list.InsertMany(0, values...)
+	return err
+}
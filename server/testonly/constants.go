@@ -1,4 +1,20 @@
 package testonly
 
+import (
+	"github.com/orda-io/orda/server/managers"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/scheduler"
+)
+
 // TestDBName is the name of mongodb for testing orda server package
 const TestDBName = "server_unit_test"
+
+// NewMemoryServerConfig returns an OrdaServerConfig which needs neither MongoDB, MQTT nor Redis.
+func NewMemoryServerConfig() *managers.OrdaServerConfig {
+	return &managers.OrdaServerConfig{
+		RPCServerPort: 59063,
+		RestfulPort:   59863,
+		Repository:    repository.TypeMemory,
+		Snapshot:      &scheduler.Config{EveryOperations: 1},
+	}
+}
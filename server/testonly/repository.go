@@ -0,0 +1,308 @@
+package testonly
+
+import (
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/errors"
+	"github.com/orda-io/orda/client/pkg/iface"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/operations"
+	"github.com/orda-io/orda/client/pkg/types"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/repository"
+	"github.com/orda-io/orda/server/schema"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepository runs the common test suite that every implementation of repository.Repository should pass.
+func TestRepository(t *testing.T, ctx iface.OrdaContext, repo repository.Repository) {
+	collectionName := t.Name()
+	require.NoError(t, repo.PurgeCollection(ctx, collectionName))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, repo.DeleteCollection(ctx, fmt.Sprintf("%s_%d", collectionName, i)))
+	}
+	collectionNum, err := repository.MakeCollection(ctx, repo, collectionName)
+	require.NoError(t, err)
+	var snapDUID string
+
+	t.Run("Can make collections simultaneously", func(t *testing.T) {
+		madeCollections := sync.Map{}
+		wg := sync.WaitGroup{}
+		wg.Add(10)
+		for i := 0; i < 10; i++ {
+			go func(idx int) {
+				defer wg.Done()
+				collection, err := repo.InsertCollection(ctx, fmt.Sprintf("%s_%d", collectionName, idx))
+				require.NoError(t, err)
+				madeCollections.Store(collection.Num, collection)
+			}(i)
+		}
+		wg.Wait()
+		cnt := 0
+		madeCollections.Range(func(_, _ interface{}) bool {
+			cnt++
+			return true
+		})
+		require.Equal(t, 10, cnt)
+		_, err := repo.InsertCollection(ctx, collectionName+"_0")
+		require.Error(t, err)
+	})
+
+	t.Run("Can manipulate clientDoc", func(t *testing.T) {
+		client := &schema.ClientDoc{
+			CUID:          types.NewUID(),
+			Alias:         "client",
+			CollectionNum: collectionNum,
+		}
+		require.NoError(t, repo.UpdateClient(ctx, client))
+		clientDoc, err := repo.GetClient(ctx, client.CUID)
+		require.NoError(t, err)
+		require.Equal(t, client.Alias, clientDoc.Alias)
+
+		clientDoc.Alias = "changed"
+		clientDoc2, err := repo.GetClient(ctx, client.CUID)
+		require.NoError(t, err)
+		require.Equal(t, client.Alias, clientDoc2.Alias)
+
+		require.NoError(t, repo.DeleteClient(ctx, client.CUID))
+		clientDoc3, err := repo.GetClient(ctx, client.CUID)
+		require.NoError(t, err)
+		require.Nil(t, clientDoc3)
+	})
+
+	t.Run("Can manipulate datatypeDoc", func(t *testing.T) {
+		d := schema.NewDatatypeDoc(types.NewUID(), "test_key", collectionNum, "test_datatype")
+		d.AddNewClient("aaaa", int8(model.ClientType_EPHEMERAL), true)
+		require.NoError(t, repo.UpdateDatatype(ctx, d))
+
+		datatypeDoc1, err := repo.GetDatatype(ctx, d.DUID)
+		require.NoError(t, err)
+		require.Equal(t, d.Key, datatypeDoc1.Key)
+		require.NotNil(t, datatypeDoc1.GetClientInDatatypeDoc("aaaa", true))
+
+		datatypeDoc2, err := repo.GetDatatype(ctx, "not exist")
+		require.NoError(t, err)
+		require.Nil(t, datatypeDoc2)
+
+		datatypeDoc3, err := repo.GetDatatypeByKey(ctx, d.CollectionNum, d.Key)
+		require.NoError(t, err)
+		require.Equal(t, d.DUID, datatypeDoc3.DUID)
+
+		require.NoError(t, repo.PurgeDatatype(ctx, d.CollectionNum, d.Key))
+		datatypeDoc4, err := repo.GetDatatype(ctx, d.DUID)
+		require.NoError(t, err)
+		require.Nil(t, datatypeDoc4)
+	})
+
+	t.Run("Can manipulate operationDoc", func(t *testing.T) {
+		var opList []*schema.OperationDoc
+		opDUID := types.NewUID()
+		for _, sseq := range []uint64{3, 1, 2} {
+			op := operations.NewIncreaseOperation(int32(sseq))
+			op.ID = model.NewOperationIDWithCUID(types.NewUID())
+			opList = append(opList, schema.NewOperationDoc(op.ToModelOperation(), opDUID, sseq, collectionNum))
+		}
+		require.NoError(t, repo.InsertOperations(ctx, opList))
+		require.Error(t, repo.InsertOperations(ctx, opList[:1]))
+
+		ops, sseqList, err := repo.GetOperations(ctx, opDUID, 2, constants.InfinitySseq)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(ops))
+		require.Equal(t, []uint64{2, 3}, sseqList)
+
+		deleted, err := repo.DeleteOperation(ctx, opDUID, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		ops, _, err = repo.GetOperations(ctx, opDUID, 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(ops))
+
+		require.NoError(t, repo.PurgeOperations(ctx, collectionNum, opDUID))
+		ops, _, err = repo.GetOperations(ctx, opDUID, 1, constants.InfinitySseq)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(ops))
+	})
+
+	t.Run("Can commit push-pull and repair orphaned operations", func(t *testing.T) {
+		datatype := schema.NewDatatypeDoc(types.NewUID(), "commit_key", collectionNum, "COUNTER")
+		opList := newOperationDocs(datatype.DUID, collectionNum, 4)
+		datatype.Sseq.End = 2
+		require.NoError(t, repo.CommitPushPull(ctx, datatype, opList[:2]))
+		require.Equal(t, uint64(1), datatype.Version)
+		datatype.Sseq.End = 3
+		require.Error(t, repo.CommitPushPull(ctx, datatype, opList[1:3]))
+		datatype.Sseq.End = 2
+
+		// two commits which push nothing, but the stale one has read the datatype before the other
+		stale, err := repo.GetDatatype(ctx, datatype.DUID)
+		require.NoError(t, err)
+		subscriber := types.NewUID()
+		datatype.AddNewClient(subscriber, int8(model.ClientType_PERSISTENT), false)
+		require.NoError(t, repo.CommitPushPull(ctx, datatype, nil))
+		stale.AddNewClient(types.NewUID(), int8(model.ClientType_PERSISTENT), true)
+		err = repo.CommitPushPull(ctx, stale, nil)
+		require.Error(t, err)
+		require.Equal(t, errors.ServerDBConflict, err.GetCode())
+
+		// another datatype created concurrently with the same key
+		another := schema.NewDatatypeDoc(types.NewUID(), datatype.Key, collectionNum, "COUNTER")
+		err = repo.CommitPushPull(ctx, another, nil)
+		require.Error(t, err)
+		require.Equal(t, errors.ServerDBConflict, err.GetCode())
+		stored, err := repo.GetDatatype(ctx, datatype.DUID)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), stored.Sseq.End)
+		require.Equal(t, uint64(2), stored.Version)
+		require.Len(t, stored.RWClients, 1)
+		require.Len(t, stored.ROClients, 0)
+		require.Equal(t, schema.RWClient, stored.HasClientInfo(subscriber))
+
+		// operations beyond Sseq.End, as if the server crashed before updating the datatype
+		require.NoError(t, repo.InsertOperations(ctx, opList[2:]))
+		orphanDUID := types.NewUID()
+		orphan := schema.NewOperationDoc(opList[0].GetOperation(), orphanDUID, 1, collectionNum)
+		require.NoError(t, repo.InsertOperations(ctx, []*schema.OperationDoc{orphan}))
+
+		repaired, err := repo.RepairOperations(ctx)
+		require.NoError(t, err)
+		if tx, ok := repo.(interface{ SupportTransaction() bool }); ok && !tx.SupportTransaction() {
+			require.Equal(t, int64(0), repaired)
+			require.NoError(t, repo.PurgeDatatype(ctx, collectionNum, datatype.Key))
+			require.NoError(t, repo.PurgeOperations(ctx, collectionNum, orphanDUID))
+			return
+		}
+		require.True(t, repaired >= 3)
+		_, sseqList, err := repo.GetOperations(ctx, datatype.DUID, 1, constants.InfinitySseq)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1, 2}, sseqList)
+		ops, _, err := repo.GetOperations(ctx, orphanDUID, 1, constants.InfinitySseq)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(ops))
+		require.NoError(t, repo.PurgeDatatype(ctx, collectionNum, datatype.Key))
+	})
+
+	t.Run("Can get the last sseq at a time", func(t *testing.T) {
+		duid := types.NewUID()
+		opList := newOperationDocs(duid, collectionNum, 3)
+		at := time.Now()
+		opList[0].CreatedAt = at.Add(-time.Minute)
+		opList[1].CreatedAt = at
+		opList[2].CreatedAt = at.Add(time.Minute)
+		require.NoError(t, repo.InsertOperations(ctx, opList))
+
+		sseq, err := repo.GetLastSseqAt(ctx, duid, at)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), sseq)
+		sseq, err = repo.GetLastSseqAt(ctx, duid, at.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), sseq)
+		require.NoError(t, repo.PurgeOperations(ctx, collectionNum, duid))
+	})
+
+	t.Run("Can compact operations", func(t *testing.T) {
+		datatype := schema.NewDatatypeDoc(types.NewUID(), "compact_key", collectionNum, "COUNTER")
+		datatype.Sseq.End = 5
+		require.NoError(t, repo.CommitPushPull(ctx, datatype, newOperationDocs(datatype.DUID, collectionNum, 5)))
+
+		deleted, err := repo.CompactOperations(ctx, datatype.DUID, 4, 3)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), deleted)
+		_, sseqList, err := repo.GetOperations(ctx, datatype.DUID, 1, constants.InfinitySseq)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{4, 5}, sseqList)
+
+		// a push-pull which has read the datatype before the compaction does not restore Sseq.Begin
+		require.NoError(t, repo.CommitPushPull(ctx, datatype, nil))
+		// Sseq.Begin never goes backward
+		deleted, err = repo.CompactOperations(ctx, datatype.DUID, 2, 3)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), deleted)
+		stored, err := repo.GetDatatype(ctx, datatype.DUID)
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), stored.Sseq.Begin)
+		require.Equal(t, uint64(3), stored.Sseq.Safe)
+		require.Equal(t, uint64(5), stored.Sseq.End)
+
+		_, err = repo.CompactOperations(ctx, types.NewUID(), 2, 1)
+		require.Error(t, err)
+		require.NoError(t, repo.PurgeDatatype(ctx, collectionNum, datatype.Key))
+	})
+
+	t.Run("Can bump era", func(t *testing.T) {
+		datatype := schema.NewDatatypeDoc(types.NewUID(), "era_key", collectionNum, "COUNTER")
+		require.NoError(t, repo.CommitPushPull(ctx, datatype, nil))
+		era, err := repo.BumpEra(ctx, datatype.DUID)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), era)
+
+		// a push-pull which has read the datatype before the bump fails to commit
+		require.Error(t, repo.CommitPushPull(ctx, datatype, nil))
+		stored, err := repo.GetDatatype(ctx, datatype.DUID)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), stored.Era)
+
+		_, err = repo.BumpEra(ctx, types.NewUID())
+		require.Error(t, err)
+		require.NoError(t, repo.PurgeDatatype(ctx, collectionNum, datatype.Key))
+	})
+
+	t.Run("Can manipulate snapshots", func(t *testing.T) {
+		snapDUID = types.NewUID()
+		require.NoError(t, repo.InsertSnapshot(ctx, collectionNum, snapDUID, 5, []byte("meta5"), []byte("snap5")))
+		require.NoError(t, repo.InsertSnapshot(ctx, collectionNum, snapDUID, 3, []byte("meta3"), []byte("snap3")))
+		require.Error(t, repo.InsertSnapshot(ctx, collectionNum, snapDUID, 3, nil, nil))
+		snapshotDoc, err := repo.GetLatestSnapshot(ctx, collectionNum, snapDUID)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), snapshotDoc.Sseq)
+		require.Equal(t, []byte("snap5"), snapshotDoc.Snapshot)
+
+		require.NoError(t, repo.GetOrCreateRealCollection(ctx, collectionName))
+		require.NoError(t, repo.InsertRealSnapshot(ctx, collectionName, "key", map[string]interface{}{"value": 1}, 5))
+		real, err := repo.GetRealSnapshot(ctx, collectionName, "key")
+		require.NoError(t, err)
+		require.Equal(t, int64(5), real[repository.Ver])
+		require.Equal(t, "key", real["_id"])
+	})
+
+	t.Run("Can get and delete snapshots", func(t *testing.T) {
+		duid := types.NewUID()
+		for _, sseq := range []uint64{4, 2, 8, 6} {
+			require.NoError(t, repo.InsertSnapshot(ctx, collectionNum, duid, sseq, []byte("meta"), []byte("snap")))
+		}
+		deleted, err := repo.DeleteSnapshots(ctx, collectionNum, duid, []uint64{2, 6, 7})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+		snapshotDocs, err := repo.GetSnapshots(ctx, collectionNum, duid)
+		require.NoError(t, err)
+		require.Len(t, snapshotDocs, 2)
+		require.Equal(t, uint64(4), snapshotDocs[0].Sseq)
+		require.Equal(t, uint64(8), snapshotDocs[1].Sseq)
+	})
+
+	t.Run("Can purge collection", func(t *testing.T) {
+		require.NoError(t, repo.PurgeCollection(ctx, collectionName))
+		collectionDoc, err := repo.GetCollection(ctx, collectionName)
+		require.NoError(t, err)
+		require.Nil(t, collectionDoc)
+		snapshotDoc, err := repo.GetLatestSnapshot(ctx, collectionNum, snapDUID)
+		require.NoError(t, err)
+		require.Nil(t, snapshotDoc)
+		real, err := repo.GetRealSnapshot(ctx, collectionName, "key")
+		require.NoError(t, err)
+		require.Nil(t, real)
+	})
+}
+
+func newOperationDocs(duid string, collectionNum int32, n uint64) []*schema.OperationDoc {
+	var opList []*schema.OperationDoc
+	for sseq := uint64(1); sseq <= n; sseq++ {
+		op := operations.NewIncreaseOperation(1)
+		op.ID = model.NewOperationIDWithCUID(types.NewUID())
+		opList = append(opList, schema.NewOperationDoc(op.ToModelOperation(), duid, sseq, collectionNum))
+	}
+	return opList
+}
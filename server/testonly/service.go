@@ -2,9 +2,16 @@ package testonly
 
 import (
 	ctx "context"
+	"github.com/orda-io/orda/client/pkg/context"
+	"github.com/orda-io/orda/client/pkg/iface"
 	"github.com/orda-io/orda/client/pkg/log"
 	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/orda"
+	"github.com/orda-io/orda/server/constants"
+	"github.com/orda-io/orda/server/managers"
+	"github.com/orda-io/orda/server/repository"
 	"github.com/orda-io/orda/server/service"
+	"github.com/orda-io/orda/server/wrapper"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -17,3 +24,69 @@ func RegisterClient(t *testing.T, service *service.OrdaService, client *model.Cl
 	require.NoError(t, err)
 	log.Logger.Infof("ProcessClient result:%v", res)
 }
+
+// ServiceFixture is an OrdaService in memory with a collection named after the test, whose clients sync manually.
+type ServiceFixture struct {
+	t             *testing.T
+	Ctx           iface.OrdaContext
+	Managers      *managers.Managers
+	Service       *service.OrdaService
+	CollectionNum int32
+	ClientConf    *orda.ClientConfig
+}
+
+// NewServiceFixture makes a ServiceFixture with the server config, or NewMemoryServerConfig() if nil.
+// The managers are closed when the test finishes.
+func NewServiceFixture(t *testing.T, conf *managers.OrdaServerConfig) *ServiceFixture {
+	if conf == nil {
+		conf = NewMemoryServerConfig()
+	}
+	ordaCtx := context.NewOrdaContext(ctx.TODO(), constants.TagTest).UpdateCollectionTags(t.Name(), 0)
+	m, err := managers.New(ordaCtx, conf)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		m.Close(ordaCtx)
+	})
+	collectionNum, err := repository.MakeCollection(ordaCtx, m.Repository, t.Name())
+	require.NoError(t, err)
+	return &ServiceFixture{
+		t:             t,
+		Ctx:           ordaCtx,
+		Managers:      m,
+		Service:       service.NewOrdaService(m),
+		CollectionNum: collectionNum,
+		ClientConf: &orda.ClientConfig{
+			CollectionName: t.Name(),
+			SyncType:       model.SyncType_MANUALLY,
+		},
+	}
+}
+
+// NewClient returns a client of ClientConf, whose alias is the name of the test followed by the suffix.
+func (its *ServiceFixture) NewClient(suffix string) orda.Client {
+	return orda.NewClient(its.ClientConf, its.t.Name()+suffix)
+}
+
+// Wrap wraps the datatype, and registers its client with the capabilities.
+func (its *ServiceFixture) Wrap(datatype orda.Datatype, capabilities model.Capability) *wrapper.DatatypeWrapper {
+	w := wrapper.NewDatatypeWrapper(datatype)
+	req := model.NewClientMessage(w.GetClientModel())
+	req.Capabilities = uint32(capabilities)
+	_, err := its.Service.ProcessClient(ctx.TODO(), req)
+	require.NoError(its.t, err)
+	return w
+}
+
+// PushPull push-pulls the datatype of the wrapper, and returns the PushPullPack applied to it.
+func (its *ServiceFixture) PushPull(w *wrapper.DatatypeWrapper) *model.PushPullPack {
+	return its.Send(w, w.CreatePushPullMessage())
+}
+
+// Send sends the PushPullMessage created by the wrapper, and returns the PushPullPack applied to it.
+func (its *ServiceFixture) Send(w *wrapper.DatatypeWrapper, req *model.PushPullMessage) *model.PushPullPack {
+	res, err := its.Service.ProcessPushPull(ctx.TODO(), req)
+	require.NoError(its.t, err)
+	ppp := res.GetPushPullPacks()[0]
+	w.ApplyPushPullPack(ppp)
+	return ppp
+}
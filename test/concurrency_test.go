@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/orda"
+	"github.com/stretchr/testify/require"
+)
+
+func (its *IntegrationTestSuite) TestConcurrentClient() {
+	name := GetFunctionName()
+	config := NewTestOrdaClientConfig(its.collectionName, model.SyncType_MANUALLY)
+
+	client1 := orda.NewClient(config, "concurrentClient1")
+	require.NoError(its.T(), client1.Connect())
+
+	const numGoroutines, numOps = 8, 20
+	counters := make([]orda.Counter, numGoroutines)
+	wg := sync.WaitGroup{}
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("%d", i)
+			counters[i] = client1.SubscribeOrCreateCounter(name+"Counter", nil)
+			map1 := client1.SubscribeOrCreateMap(name+"Map", nil)
+			list1 := client1.SubscribeOrCreateList(name+"List", nil)
+			doc1 := client1.SubscribeOrCreateDocument(name+"Document", nil)
+			for j := 0; j < numOps; j++ {
+				_, _ = counters[i].Increase()
+				_, _ = map1.Put(key, j)
+				_, _ = list1.Insert(0, j)
+				_, _ = doc1.PutToObject(key, j)
+				if err := counters[i].Transaction("increase", func(counter orda.CounterInTx) error {
+					_, _ = counter.Increase()
+					_ = counter.Get()
+					return nil
+				}); err != nil {
+					its.T().Errorf("fail to transact: %v", err)
+				}
+				_, _ = map1.Get(key), map1.ToJSON()
+				_, _ = list1.Size(), list1.ToJSON()
+				_ = doc1.ToJSON()
+				if j%5 == 0 {
+					if err := client1.Sync(); err != nil {
+						its.T().Errorf("fail to sync: %v", err)
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, counter := range counters {
+		require.Equal(its.T(), counters[0], counter)
+	}
+	require.NoError(its.T(), client1.Sync())
+
+	client2 := orda.NewClient(config, "concurrentClient2")
+	require.NoError(its.T(), client2.Connect())
+	defer func() {
+		require.NoError(its.T(), client2.Close())
+	}()
+	counter2 := client2.SubscribeCounter(name+"Counter", nil)
+	map2 := client2.SubscribeMap(name+"Map", nil)
+	list2 := client2.SubscribeList(name+"List", nil)
+	doc2 := client2.SubscribeDocument(name+"Document", nil)
+	require.NoError(its.T(), client2.Sync())
+	require.Equal(its.T(), int32(numGoroutines*numOps*2), counter2.Get())
+	require.Equal(its.T(), numGoroutines, map2.Size())
+	require.Equal(its.T(), numGoroutines*numOps, list2.Size())
+	require.Equal(its.T(), counters[0].ToJSON(), counter2.ToJSON())
+	require.Equal(its.T(), client1.SubscribeOrCreateMap(name+"Map", nil).ToJSON(), map2.ToJSON())
+	require.Equal(its.T(), client1.SubscribeOrCreateDocument(name+"Document", nil).ToJSON(), doc2.ToJSON())
+
+	// Sync and Close racing with each other leave the client closed
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = client1.Sync()
+		}()
+		go func() {
+			defer wg.Done()
+			if err := client1.Close(); err != nil {
+				its.T().Errorf("fail to close: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	require.False(its.T(), client1.IsConnected())
+	require.Error(its.T(), client1.Sync())
+}
@@ -0,0 +1,115 @@
+package integration
+
+import (
+	gocontext "context"
+	"fmt"
+	"github.com/orda-io/orda/client/pkg/model"
+	"github.com/orda-io/orda/client/pkg/orda"
+	"github.com/orda-io/orda/client/pkg/types"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func (its *IntegrationTestSuite) registerTestClient(alias string) *model.Client {
+	conn, err := grpc.Dial(its.conf.GetRPCServerAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(its.T(), err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	cm := &model.Client{
+		CUID:       types.NewUID(),
+		Alias:      alias,
+		Collection: its.collectionName,
+		Type:       model.ClientType_PERSISTENT,
+		SyncType:   model.SyncType_REALTIME,
+	}
+	_, err = model.NewOrdaServiceClient(conn).ProcessClient(gocontext.TODO(), model.NewClientMessage(cm))
+	require.NoError(its.T(), err)
+	return cm
+}
+
+func (its *IntegrationTestSuite) TestWebSocket() {
+	its.Run("Can reject WebSocket of not registered client", func() {
+		url := fmt.Sprintf("ws://localhost:%d/ws/v1/collections/%s/clients/%s",
+			its.conf.RestfulPort, its.collectionName, types.NewUID())
+		_, res, err := websocket.DefaultDialer.Dial(url, nil)
+		require.Error(its.T(), err)
+		require.NotNil(its.T(), res)
+		require.Equal(its.T(), 404, res.StatusCode)
+	})
+
+	its.Run("Can push-pull and receive notifications over WebSocket", func() {
+		key := GetFunctionName()
+
+		config := NewTestOrdaClientConfig(its.collectionName, model.SyncType_MANUALLY)
+		client1 := orda.NewClient(config, "client1")
+		require.NoError(its.T(), client1.Connect())
+		defer func() {
+			_ = client1.Close()
+		}()
+		counter1 := client1.CreateCounter(key, nil)
+		_, _ = counter1.Increase()
+		require.NoError(its.T(), client1.Sync())
+
+		cm := its.registerTestClient("wsClient")
+		url := fmt.Sprintf("ws://localhost:%d/ws/v1/collections/%s/clients/%s",
+			its.conf.RestfulPort, its.collectionName, cm.CUID)
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(its.T(), err)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		option := model.PushPullPackOption(0)
+		ppp := &model.PushPullPack{
+			DUID:       types.NewUID(),
+			Key:        key,
+			Option:     uint32(*option.SetSubscribeBit()),
+			CheckPoint: model.NewCheckPoint(),
+			Type:       model.TypeOfDatatype_COUNTER,
+		}
+		req, err := protojson.Marshal(model.NewPushPullMessage(1, cm, ppp))
+		require.NoError(its.T(), err)
+		require.NoError(its.T(), conn.WriteMessage(websocket.TextMessage, req))
+
+		_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(its.T(), err)
+		res := &model.PushPullMessage{}
+		require.NoError(its.T(), protojson.Unmarshal(data, res))
+		require.Len(its.T(), res.PushPullPacks, 1)
+		resPPP := res.PushPullPacks[0]
+		require.False(its.T(), resPPP.GetPushPullPackOption().HasErrorBit())
+		require.Equal(its.T(), uint64(2), resPPP.CheckPoint.Sseq)
+
+		_, _ = counter1.Increase()
+		require.NoError(its.T(), client1.Sync())
+
+		_, data, err = conn.ReadMessage()
+		require.NoError(its.T(), err)
+		noti := &model.Notification{}
+		require.NoError(its.T(), protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, noti))
+		require.Equal(its.T(), resPPP.DUID, noti.DUID)
+		require.Equal(its.T(), uint64(3), noti.Sseq)
+	})
+	its.Run("Can close WebSocket receiving too big a message", func() {
+		cm := its.registerTestClient("wsBigClient")
+		url := fmt.Sprintf("ws://localhost:%d/ws/v1/collections/%s/clients/%s",
+			its.conf.RestfulPort, its.collectionName, cm.CUID)
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(its.T(), err)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		_ = conn.WriteMessage(websocket.TextMessage, make([]byte, its.conf.GetMaxMessageSize()+1))
+		_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		_, _, err = conn.ReadMessage()
+		require.True(its.T(), websocket.IsCloseError(err, websocket.CloseMessageTooBig))
+	})
+}